@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"api5back/src/calendar"
+	"api5back/src/database"
+	"api5back/src/warehouse"
+)
+
+// parseOptions builds the calendar options from the flags: the national
+// holidays of Brazil are always observed, along with the holidays of the
+// given states, the optional days off when requested, and the holiday
+// calendars of a JSON file holding a list of `calendar.HolidayCalendar`.
+func parseOptions(
+	fiscalYearStart int,
+	states string,
+	optional bool,
+	holidaysPath string,
+) (calendar.Options, error) {
+	if fiscalYearStart < 1 || fiscalYearStart > 12 {
+		return calendar.Options{}, fmt.Errorf("invalid fiscal year start month: %d", fiscalYearStart)
+	}
+
+	options := calendar.Options{
+		FiscalYearStart: time.Month(fiscalYearStart),
+		Holidays:        []calendar.HolidayCalendar{calendar.BrazilNational},
+	}
+
+	if optional {
+		options.Holidays = append(options.Holidays, calendar.BrazilOptional)
+	}
+
+	if states != "" {
+		for _, state := range strings.Split(states, ",") {
+			stateHolidays, ok := calendar.BrazilStates[strings.ToUpper(strings.TrimSpace(state))]
+			if !ok {
+				return calendar.Options{}, fmt.Errorf("no holiday calendar for state %q", state)
+			}
+			options.Holidays = append(options.Holidays, stateHolidays)
+		}
+	}
+
+	if holidaysPath != "" {
+		content, err := os.ReadFile(holidaysPath)
+		if err != nil {
+			return calendar.Options{}, fmt.Errorf("could not read holidays file: %v", err)
+		}
+
+		var holidayCalendars []calendar.HolidayCalendar
+		if err := json.Unmarshal(content, &holidayCalendars); err != nil {
+			return calendar.Options{}, fmt.Errorf("could not parse holidays file: %v", err)
+		}
+		options.Holidays = append(options.Holidays, holidayCalendars...)
+	}
+
+	return options, nil
+}
+
+// Builds the date dimension of the data warehouse for a range of dates.
+// Dates already in the dimension keep their keys and only get their
+// calendar attributes updated.
+//
+//	go run scripts/calendar/main.go -from 2024-01-01 -to 2025-12-31 \
+//		[-fiscal-start 1] [-states SP,RJ] [-optional] [-holidays file.json]
+func main() {
+	from := flag.String("from", "", "first date of the range, as 2006-01-02")
+	to := flag.String("to", "", "last date of the range, as 2006-01-02")
+	fiscalYearStart := flag.Int("fiscal-start", 1, "month the fiscal year starts in, from 1 to 12")
+	states := flag.String("states", "", "comma separated states whose holidays are observed, e.g. SP,RJ")
+	optional := flag.Bool("optional", false, "observe the optional days off, Carnaval and Corpus Christi")
+	holidaysPath := flag.String("holidays", "", "JSON file with additional holiday calendars")
+	flag.Parse()
+
+	fromDate, err := time.Parse(time.DateOnly, *from)
+	if err != nil {
+		panic(fmt.Errorf("scripts/calendar • invalid -from date %q: %v", *from, err))
+	}
+
+	toDate, err := time.Parse(time.DateOnly, *to)
+	if err != nil {
+		panic(fmt.Errorf("scripts/calendar • invalid -to date %q: %v", *to, err))
+	}
+
+	options, err := parseOptions(*fiscalYearStart, *states, *optional, *holidaysPath)
+	if err != nil {
+		panic(fmt.Errorf("scripts/calendar • %v", err))
+	}
+
+	client, err := database.Setup("DW")
+	if err != nil {
+		panic(fmt.Errorf("scripts/calendar • failed to setup data warehouse: %v", err))
+	}
+	defer client.Close()
+
+	result, err := warehouse.LoadCalendar(
+		context.Background(),
+		client,
+		calendar.New(options),
+		fromDate,
+		toDate,
+	)
+	if err != nil {
+		panic(fmt.Errorf("scripts/calendar • failed to load the calendar: %v", err))
+	}
+
+	fmt.Printf(
+		"scripts/calendar • Loaded %s to %s: %d dates created, %d updated\n",
+		fromDate.Format(time.DateOnly),
+		toDate.Format(time.DateOnly),
+		result.Created,
+		result.Updated,
+	)
+}
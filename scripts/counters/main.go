@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/src/database"
+	"api5back/src/warehouse"
+)
+
+// Derives the interview and feedback counters of the facts of the data
+// warehouse from its `dim_interview` and `dim_feedback` rows, in a
+// single transaction. Loading the interviews and feedbacks already
+// derives the counters of their vacancies, so it only rebuilds them
+// after rows were written another way. The facts whose vacancy has no
+// detail rows keep their loaded counters.
+//
+//	go run scripts/counters/main.go
+func main() {
+	client, err := database.Setup("DW")
+	if err != nil {
+		panic(fmt.Errorf("scripts/counters • failed to setup data warehouse: %v", err))
+	}
+	defer client.Close()
+
+	updated, err := warehouse.DeriveFactHiringProcessCounters(context.Background(), client)
+	if err != nil {
+		panic(fmt.Errorf("scripts/counters • failed to derive the fact counters: %v", err))
+	}
+
+	fmt.Printf("scripts/counters • Derived the counters of %d facts\n", updated)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"api5back/src/database"
+	"api5back/src/warehouse"
+)
+
+// parseDateArg parses the argument at the index, as `2006-01-02`, or
+// returns the fallback when there is no such argument.
+func parseDateArg(index int, fallback time.Time) (time.Time, error) {
+	if len(os.Args) <= index {
+		return fallback, nil
+	}
+
+	date, err := time.Parse("2006-01-02", os.Args[index])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("scripts/snapshot • invalid date %q: %v", os.Args[index], err)
+	}
+
+	return date, nil
+}
+
+// Snapshots the pipeline of the data warehouse at a date, today by
+// default, or at every date of a range to backfill it. Running it again
+// for a date replaces the snapshot of the date.
+//
+//	go run scripts/snapshot/main.go [date] [endDate]
+func main() {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	startDate, err := parseDateArg(1, today)
+	if err != nil {
+		panic(err)
+	}
+
+	endDate, err := parseDateArg(2, startDate)
+	if err != nil {
+		panic(err)
+	}
+
+	if endDate.Before(startDate) {
+		panic(fmt.Errorf("scripts/snapshot • end date %s is before start date %s",
+			endDate.Format("2006-01-02"),
+			startDate.Format("2006-01-02"),
+		))
+	}
+
+	client, err := database.Setup("DW")
+	if err != nil {
+		panic(fmt.Errorf("scripts/snapshot • failed to setup data warehouse: %v", err))
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		snapshots, err := warehouse.SnapshotPipeline(ctx, client, date)
+		if err != nil {
+			panic(fmt.Errorf("scripts/snapshot • failed to snapshot %s: %v", date.Format("2006-01-02"), err))
+		}
+
+		fmt.Printf(
+			"scripts/snapshot • Snapshotted %s: %d rows\n",
+			date.Format("2006-01-02"),
+			len(snapshots),
+		)
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"api5back/ent"
+	"api5back/src/calendar"
 	"api5back/src/property"
+	"api5back/src/warehouse"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -26,8 +28,14 @@ var DwDimUser = []ent.DimUser{
 	{DbId: 2, Name: "Bob Ferreira", Occupation: "HR Manager"},
 	{DbId: 3, Name: "Carla Mendes", Occupation: "Software Engineer"},
 	{DbId: 4, Name: "David Costa", Occupation: "Data Analyst"},
-	{DbId: 5, Name: "Eva Lima", Occupation: "Product Manager"},
-	{DbId: 5, Name: "Eva Lima", Occupation: "Chief Product Officer"},
+	{
+		DbId: 5, Name: "Eva Lima", Occupation: "Product Manager",
+		ValidTo: &pgtype.Date{Time: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	},
+	{
+		DbId: 5, Name: "Eva Lima", Occupation: "Chief Product Officer",
+		ValidFrom: &pgtype.Date{Time: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	},
 }
 
 var DwDimDatetime = []ent.DimDatetime{
@@ -386,11 +394,20 @@ func DataWarehouse(client *ent.Client) error {
 	}
 
 	for i, user := range DwDimUser {
-		_, err := client.DimUser.Create().
+		userBuilder := client.DimUser.Create().
 			SetDbId(user.DbId).
 			SetName(user.Name).
 			SetOccupation(user.Occupation).
-			Save(ctx)
+			SetIsCurrent(user.ValidTo == nil)
+
+		if user.ValidFrom != nil {
+			userBuilder.SetValidFrom(user.ValidFrom)
+		}
+		if user.ValidTo != nil {
+			userBuilder.SetValidTo(user.ValidTo)
+		}
+
+		_, err := userBuilder.Save(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to create user [%d] %+v: %+v", i, user, err)
 		}
@@ -412,6 +429,22 @@ func DataWarehouse(client *ent.Client) error {
 		}
 	}
 
+	// the rest of the year is generated, filling in the calendar
+	// attributes of the dates above as well
+	if _, err := warehouse.LoadCalendar(
+		ctx, client,
+		calendar.New(calendar.Options{
+			Holidays: []calendar.HolidayCalendar{
+				calendar.BrazilNational,
+				calendar.BrazilStates["SP"],
+			},
+		}),
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	); err != nil {
+		return fmt.Errorf("failed to load the calendar: %+v", err)
+	}
+
 	for i, vacancy := range DwDimVacancy {
 		_, err := client.DimVacancy.Create().
 			SetDbId(vacancy.DbId).
@@ -445,6 +478,7 @@ func DataWarehouse(client *ent.Client) error {
 	}
 
 	logCurrentCandidate := 1
+	offerDbId := 1
 	var logTotalCandidates int
 
 	for _, fact := range DwFactHiringProcess {
@@ -480,6 +514,8 @@ func DataWarehouse(client *ent.Client) error {
 		}
 
 		factCurrentCandidateDbId := 1
+		var factCandidates []*ent.DimCandidate
+		var factInterviews []*ent.DimInterview
 		factTotalCandidatesRemaining := fact.MetTotalCandidatesApplied - fact.MetTotalCandidatesHired - fact.MetTotalCandidatesInterviewed
 
 		for candidateStatus, candidateCategory := range [4]int{
@@ -523,11 +559,33 @@ func DataWarehouse(client *ent.Client) error {
 					))
 				}
 
-				_, err := candidateBuilder.Save(ctx)
+				candidate, err := candidateBuilder.Save(ctx)
 				if err != nil {
 					return fmt.Errorf("failed to create dim_candidate [%d]: %+v", logCurrentCandidate, err)
 				}
 
+				if err := seedCandidateStatusChanges(ctx, client, candidate); err != nil {
+					return fmt.Errorf("failed to create status changes of dim_candidate [%d]: %+v", logCurrentCandidate, err)
+				}
+
+				factCandidates = append(factCandidates, candidate)
+				if candidate.Status == property.DimCandidateStatusInterview ||
+					candidate.Status == property.DimCandidateStatusHired {
+					interview, err := seedCandidateInterview(ctx, client, fact, candidate)
+					if err != nil {
+						return fmt.Errorf("failed to create dim_interview of dim_candidate [%d]: %+v", logCurrentCandidate, err)
+					}
+					factInterviews = append(factInterviews, interview)
+				}
+
+				offer, err := seedCandidateOffer(ctx, client, fact, candidate, offerDbId)
+				if err != nil {
+					return fmt.Errorf("failed to create dim_offer of dim_candidate [%d]: %+v", logCurrentCandidate, err)
+				}
+				if offer != nil {
+					offerDbId++
+				}
+
 				factCurrentCandidateDbId++
 				logCurrentCandidate++
 
@@ -535,9 +593,193 @@ func DataWarehouse(client *ent.Client) error {
 			}
 		}
 
+		if err := seedFactFeedbacks(ctx, client, fact, factCandidates, factInterviews); err != nil {
+			return fmt.Errorf("failed to create dim_feedback of fact hiring process [%d]: %+v", factId, err)
+		}
+
 		fmt.Print("\033[F\033[K") // \033[F moves up a line, \033[K clears the line
 	}
 
+	// the pipeline is snapshotted on the first of each month of the year
+	// the processes ran
+	for month := time.January; month <= time.December; month++ {
+		date := time.Date(2024, month, 1, 0, 0, 0, 0, time.UTC)
+		if _, err := warehouse.SnapshotPipeline(ctx, client, date); err != nil {
+			return fmt.Errorf("failed to snapshot the pipeline at %s: %+v", date.Format("2006-01-02"), err)
+		}
+	}
+
+	return nil
+}
+
+type seedStatusChange struct {
+	Status property.DimCandidateStatus
+	Date   *pgtype.Date
+}
+
+// seedCandidateStatusChanges records the statuses the candidate went
+// through: every candidate is in analysis once it applies, and moves to
+// its status by `updatedAt`, the hired ones after an interview halfway
+// there.
+func seedCandidateStatusChanges(
+	ctx context.Context,
+	client *ent.Client,
+	candidate *ent.DimCandidate,
+) error {
+	changes := []seedStatusChange{
+		{property.DimCandidateStatusInAnalysis, candidate.ApplyDate},
+	}
+
+	switch candidate.Status {
+	case property.DimCandidateStatusInterview, property.DimCandidateStatusRejected:
+		changes = append(changes, seedStatusChange{candidate.Status, candidate.UpdatedAt})
+	case property.DimCandidateStatusHired:
+		changes = append(
+			changes,
+			seedStatusChange{
+				property.DimCandidateStatusInterview,
+				lerpDate(*candidate.ApplyDate, *candidate.UpdatedAt, 0.5),
+			},
+			seedStatusChange{candidate.Status, candidate.UpdatedAt},
+		)
+	}
+
+	for _, change := range changes {
+		if _, err := warehouse.LoadCandidateStatusChange(
+			ctx, client,
+			candidate,
+			change.Status,
+			change.Date.Time,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seedCandidateInterview interviews the candidate by the recruiter of
+// the fact when it moved to the interview stage, approving the ones
+// that were hired.
+func seedCandidateInterview(
+	ctx context.Context,
+	client *ent.Client,
+	fact ent.FactHiringProcess,
+	candidate *ent.DimCandidate,
+) (*ent.DimInterview, error) {
+	outcome := property.DimInterviewOutcomePending
+	date := candidate.UpdatedAt
+	if candidate.Status == property.DimCandidateStatusHired {
+		outcome = property.DimInterviewOutcomeApproved
+		date = lerpDate(*candidate.ApplyDate, *candidate.UpdatedAt, 0.5)
+	}
+
+	return warehouse.LoadDimInterview(ctx, client, ent.DimInterview{
+		DbId:           candidate.DbId,
+		DimCandidateId: candidate.ID,
+		DimVacancyId:   fact.DimVacancyId,
+		Date:           date,
+		Interviewer:    DwDimUser[fact.DimUserId-1].Name,
+		Outcome:        outcome,
+	})
+}
+
+// seedCandidateOffer makes an offer to the hired candidates, accepted
+// by `updatedAt`, and to some of the others: the rejected ones decline
+// it and the ones being interviewed have not answered yet. Each offer
+// pays the average initial salary of the fact.
+func seedCandidateOffer(
+	ctx context.Context,
+	client *ent.Client,
+	fact ent.FactHiringProcess,
+	candidate *ent.DimCandidate,
+	dbId int,
+) (*ent.DimOffer, error) {
+	var answer property.DimOfferStatus
+	switch {
+	case candidate.Status == property.DimCandidateStatusHired:
+		answer = property.DimOfferStatusAccepted
+	case candidate.Status == property.DimCandidateStatusRejected && candidate.DbId%2 == 1:
+		answer = property.DimOfferStatusDeclined
+	case candidate.Status == property.DimCandidateStatusInterview && candidate.DbId%3 == 0:
+		answer = property.DimOfferStatusSent
+	default:
+		return nil, nil
+	}
+
+	offer := ent.DimOffer{
+		DbId:           dbId,
+		DimCandidateId: candidate.ID,
+		DimVacancyId:   fact.DimVacancyId,
+		Salary:         float64(fact.MetSumSalaryInitial) / float64(max(fact.MetTotalCandidatesHired, 1)),
+		SentDate:       lerpDate(*candidate.ApplyDate, *candidate.UpdatedAt, 0.75),
+		Status:         property.DimOfferStatusSent,
+	}
+
+	sent, err := warehouse.LoadDimOffer(ctx, client, offer, offer.SentDate.Time)
+	if err != nil || answer == property.DimOfferStatusSent {
+		return sent, err
+	}
+
+	offer.Status = answer
+	if answer == property.DimOfferStatusAccepted {
+		offer.AcceptedDate = candidate.UpdatedAt
+	} else {
+		offer.DeclinedDate = candidate.UpdatedAt
+	}
+
+	return warehouse.LoadDimOffer(ctx, client, offer, candidate.UpdatedAt.Time)
+}
+
+// seedFactFeedbacks spreads as many positive, neutral and negative
+// feedbacks as the fact counts over its interviews or, when there are
+// none, over its candidates. Loading them derives the counters of the
+// fact back from the interviews and feedbacks.
+func seedFactFeedbacks(
+	ctx context.Context,
+	client *ent.Client,
+	fact ent.FactHiringProcess,
+	candidates []*ent.DimCandidate,
+	interviews []*ent.DimInterview,
+) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	feedbackDbId := 1
+	for _, sentiment := range []struct {
+		Count int
+		Score float64
+	}{
+		{fact.MetTotalFeedbackPositive, 0.6},
+		{fact.MetTotalNeutral, 0},
+		{fact.MetTotalNegative, -0.6},
+	} {
+		for j := 0; j < sentiment.Count; j++ {
+			feedback := ent.DimFeedback{
+				DbId:           feedbackDbId,
+				DimVacancyId:   fact.DimVacancyId,
+				SentimentScore: sentiment.Score + float64(j%3)*sentiment.Score/6,
+			}
+
+			if len(interviews) > 0 {
+				interview := interviews[(feedbackDbId-1)%len(interviews)]
+				feedback.DimCandidateId = interview.DimCandidateId
+				feedback.DimInterviewId = interview.ID
+				feedback.Date = interview.Date
+			} else {
+				candidate := candidates[(feedbackDbId-1)%len(candidates)]
+				feedback.DimCandidateId = candidate.ID
+				feedback.Date = candidate.ApplyDate
+			}
+
+			if _, err := warehouse.LoadDimFeedback(ctx, client, feedback); err != nil {
+				return err
+			}
+			feedbackDbId++
+		}
+	}
+
 	return nil
 }
 
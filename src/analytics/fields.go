@@ -0,0 +1,120 @@
+package analytics
+
+const (
+	FieldTypeString = "string"
+	FieldTypeNumber = "number"
+	FieldTypeDate   = "date"
+
+	FieldKindDimension = "dimension"
+	FieldKindMeasure   = "measure"
+)
+
+const (
+	joinProcess    = "process"
+	joinDepartment = "department"
+	joinVacancy    = "vacancy"
+	joinUser       = "user"
+	joinDatetime   = "datetime"
+	joinCandidates = "candidates"
+)
+
+// joins holds the tables that can be joined to `fact_hiring_process`,
+// in the order they must be written. The candidates join reads from the
+// table given by `warehouse.DimCandidateTable`.
+var joins = []struct {
+	Name string
+	SQL  string
+}{
+	{joinProcess, "JOIN dim_process p ON p.id = f.dim_process_id"},
+	{joinDepartment, "JOIN dim_department dd ON dd.id = p.dim_department_id"},
+	{joinVacancy, "JOIN dim_vacancy v ON v.id = f.dim_vacancy_id"},
+	{joinUser, "JOIN dim_user u ON u.id = f.dim_user_id"},
+	{joinDatetime, "JOIN dim_datetime dt ON dt.id = f.dim_date_id"},
+	{joinCandidates, "LEFT JOIN LATERAL (" +
+		"SELECT COUNT(*) AS num_candidates, " +
+		"COUNT(*) FILTER (WHERE c.status = 'Hired') AS num_hired, " +
+		"SUM(c.updated_at - c.apply_date) FILTER (WHERE c.status = 'Hired') AS hiring_days " +
+		"FROM %s c WHERE c.dim_vacancy_db_id = v.id" +
+		") cs ON true"},
+}
+
+// Field is a whitelisted expression over the star schema. `Joins` lists
+// every join the expression depends on, prerequisites included.
+type Field struct {
+	Name       string
+	Kind       string
+	Type       string
+	Expression string
+	Joins      []string
+}
+
+func dimension(name, fieldType, expression string, joins ...string) Field {
+	return Field{
+		Name:       name,
+		Kind:       FieldKindDimension,
+		Type:       fieldType,
+		Expression: expression,
+		Joins:      joins,
+	}
+}
+
+func measure(name, expression string, joins ...string) Field {
+	return Field{
+		Name:       name,
+		Kind:       FieldKindMeasure,
+		Type:       FieldTypeNumber,
+		Expression: expression,
+		Joins:      joins,
+	}
+}
+
+func dateGrain(name, grain string) Field {
+	return dimension(
+		name, FieldTypeDate,
+		"date_trunc('"+grain+"', dt.date)::date",
+		joinDatetime,
+	)
+}
+
+func registry(fields ...Field) map[string]Field {
+	fieldsByName := make(map[string]Field)
+	for _, field := range fields {
+		fieldsByName[field.Name] = field
+	}
+
+	return fieldsByName
+}
+
+// Dimensions are the fields a query can group by.
+var Dimensions = registry(
+	dimension("department", FieldTypeString, "dd.name", joinProcess, joinDepartment),
+	dimension("recruiter", FieldTypeString, "u.name", joinUser),
+	dimension("process", FieldTypeString, "p.title", joinProcess),
+	dimension("processStatus", FieldTypeString, "p.status", joinProcess),
+	dimension("vacancy", FieldTypeString, "v.title", joinVacancy),
+	dimension("vacancyStatus", FieldTypeString, "v.status", joinVacancy),
+	dimension("location", FieldTypeString, "v.location", joinVacancy),
+	dimension("numPositions", FieldTypeNumber, "v.num_positions", joinVacancy),
+	dateGrain("date.day", "day"),
+	dateGrain("date.week", "week"),
+	dateGrain("date.month", "month"),
+	dateGrain("date.quarter", "quarter"),
+	dateGrain("date.year", "year"),
+)
+
+// Measures are the aggregations a query can compute.
+var Measures = registry(
+	measure("count", "COUNT(*)::bigint"),
+	measure("totalCandidatesApplied", "SUM(f.met_total_candidates_applied)::bigint"),
+	measure("totalCandidatesInterviewed", "SUM(f.met_total_candidates_interviewed)::bigint"),
+	measure("totalCandidatesHired", "SUM(f.met_total_candidates_hired)::bigint"),
+	measure("sumDurationHiringProcess", "SUM(f.met_sum_duration_hiring_proces)::bigint"),
+	measure("sumSalaryInitial", "SUM(f.met_sum_salary_initial)::bigint"),
+	measure("totalFeedbackPositive", "SUM(f.met_total_feedback_positive)::bigint"),
+	measure("totalFeedbackNeutral", "SUM(f.met_total_neutral)::bigint"),
+	measure("totalFeedbackNegative", "SUM(f.met_total_negative)::bigint"),
+	measure("totalPositions", "SUM(v.num_positions)::bigint", joinVacancy),
+	measure("candidateCount", "COALESCE(SUM(cs.num_candidates), 0)::bigint", joinVacancy, joinCandidates),
+	measure("hiredCandidateCount", "COALESCE(SUM(cs.num_hired), 0)::bigint", joinVacancy, joinCandidates),
+	measure("averageHiringDays", "(SUM(cs.hiring_days)::float8 / NULLIF(SUM(cs.num_hired), 0))", joinVacancy, joinCandidates),
+)
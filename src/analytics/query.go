@@ -0,0 +1,312 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"api5back/src/model"
+	"api5back/src/warehouse"
+)
+
+var (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Statement is a compiled query, ready to be executed with its
+// positional arguments.
+type Statement struct {
+	SQL     string
+	Args    []any
+	Columns []model.AnalyticsColumn
+}
+
+// likeEscaper escapes the wildcards of `ILIKE` patterns, so values are
+// matched literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+type comparison struct {
+	Operator string
+	NumArgs  int
+}
+
+// operators maps the filter operators to their SQL comparison. An
+// operator with -1 arguments takes a list of values.
+var operators = map[string]comparison{
+	"eq":       {"=", 1},
+	"neq":      {"<>", 1},
+	"gt":       {">", 1},
+	"gte":      {">=", 1},
+	"lt":       {"<", 1},
+	"lte":      {"<=", 1},
+	"in":       {"IN", -1},
+	"contains": {"ILIKE", 1},
+}
+
+// LookupField finds a whitelisted dimension or measure by name.
+func LookupField(name string) (Field, error) {
+	if field, ok := Dimensions[name]; ok {
+		return field, nil
+	}
+
+	if field, ok := Measures[name]; ok {
+		return field, nil
+	}
+
+	return Field{}, fmt.Errorf("unknown field: %q", name)
+}
+
+type compiler struct {
+	args  []any
+	joins map[string]bool
+}
+
+func (c *compiler) arg(value any) string {
+	c.args = append(c.args, value)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *compiler) join(field Field) {
+	for _, join := range field.Joins {
+		c.joins[join] = true
+	}
+}
+
+func (c *compiler) condition(filter model.AnalyticsFilter) (Field, string, error) {
+	field, err := LookupField(filter.Field)
+	if err != nil {
+		return Field{}, "", err
+	}
+
+	operator, ok := operators[filter.Operator]
+	if !ok {
+		return Field{}, "", fmt.Errorf(
+			"unknown operator %q for field %q",
+			filter.Operator,
+			filter.Field,
+		)
+	}
+
+	c.join(field)
+
+	switch {
+	case operator.NumArgs < 0:
+		if len(filter.Values) == 0 {
+			return Field{}, "", fmt.Errorf(
+				"operator %q for field %q requires `values`",
+				filter.Operator,
+				filter.Field,
+			)
+		}
+
+		placeholders := make([]string, len(filter.Values))
+		for i, value := range filter.Values {
+			placeholders[i] = c.arg(value)
+		}
+
+		return field, fmt.Sprintf(
+			"%s %s (%s)",
+			field.Expression,
+			operator.Operator,
+			strings.Join(placeholders, ", "),
+		), nil
+	case filter.Value == nil:
+		return Field{}, "", fmt.Errorf(
+			"operator %q for field %q requires a `value`",
+			filter.Operator,
+			filter.Field,
+		)
+	case filter.Operator == "contains":
+		if field.Type != FieldTypeString {
+			return Field{}, "", fmt.Errorf(
+				"operator %q is not supported by field %q",
+				filter.Operator,
+				filter.Field,
+			)
+		}
+
+		value, ok := filter.Value.(string)
+		if !ok {
+			return Field{}, "", fmt.Errorf(
+				"operator %q for field %q requires a string `value`",
+				filter.Operator,
+				filter.Field,
+			)
+		}
+
+		return field, fmt.Sprintf(
+			"%s ILIKE '%%' || %s || '%%' ESCAPE '\\'",
+			field.Expression,
+			c.arg(likeEscaper.Replace(value)),
+		), nil
+	default:
+		return field, fmt.Sprintf(
+			"%s %s %s",
+			field.Expression,
+			operator.Operator,
+			c.arg(filter.Value),
+		), nil
+	}
+}
+
+// Compile validates the query against the whitelisted fields and
+// translates it into a single SQL statement over `fact_hiring_process`.
+func Compile(query model.AnalyticsQuery) (*Statement, error) {
+	if len(query.Dimensions) == 0 && len(query.Measures) == 0 {
+		return nil, errors.New("query requires at least one dimension or measure")
+	}
+
+	c := &compiler{joins: make(map[string]bool)}
+
+	var columns []model.AnalyticsColumn
+	var selections []string
+	selected := make(map[string]bool)
+
+	for _, selection := range []struct {
+		Names    []string
+		Registry map[string]Field
+		Kind     string
+	}{
+		{query.Dimensions, Dimensions, FieldKindDimension},
+		{query.Measures, Measures, FieldKindMeasure},
+	} {
+		for _, name := range selection.Names {
+			if selected[name] {
+				return nil, fmt.Errorf("field %q is selected more than once", name)
+			}
+			selected[name] = true
+
+			field, ok := selection.Registry[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown %s: %q", selection.Kind, name)
+			}
+
+			c.join(field)
+			selections = append(selections, fmt.Sprintf("%s AS %q", field.Expression, field.Name))
+			columns = append(columns, model.AnalyticsColumn{
+				Name: field.Name,
+				Kind: field.Kind,
+				Type: field.Type,
+			})
+		}
+	}
+
+	var where, having []string
+
+	if len(query.AccessGroups) > 0 {
+		placeholders := make([]string, len(query.AccessGroups))
+		for i, departmentId := range query.AccessGroups {
+			placeholders[i] = c.arg(departmentId)
+		}
+
+		// departments are matched by `dbId`, so the ID of any version of
+		// a department grants access to its current version
+		c.joins[joinProcess] = true
+		where = append(where, fmt.Sprintf(
+			"p.dim_department_id IN (SELECT d.id FROM dim_department d "+
+				"JOIN dim_department a ON a.db_id = d.db_id WHERE a.id IN (%s))",
+			strings.Join(placeholders, ", "),
+		))
+	}
+
+	for _, filter := range query.Filters {
+		field, condition, err := c.condition(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		if field.Kind == FieldKindMeasure {
+			having = append(having, condition)
+		} else {
+			where = append(where, condition)
+		}
+	}
+
+	var orderBy []string
+	for _, sort := range query.Sort {
+		if !selected[sort.Field] {
+			return nil, fmt.Errorf("sort field %q is not selected", sort.Field)
+		}
+
+		direction := strings.ToUpper(sort.Direction)
+		if direction == "" {
+			direction = "ASC"
+		}
+		if direction != "ASC" && direction != "DESC" {
+			return nil, fmt.Errorf("invalid sort direction: %q", sort.Direction)
+		}
+
+		orderBy = append(orderBy, fmt.Sprintf("%q %s", sort.Field, direction))
+	}
+
+	limit := DefaultLimit
+	if query.Limit != nil {
+		if *query.Limit <= 0 || *query.Limit > MaxLimit {
+			return nil, fmt.Errorf(
+				"invalid limit %d, expected a value between 1 and %d",
+				*query.Limit,
+				MaxLimit,
+			)
+		}
+		limit = *query.Limit
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	sb.WriteString(strings.Join(selections, ", "))
+	sb.WriteString(" FROM fact_hiring_process f")
+
+	for _, join := range joins {
+		if c.joins[join.Name] {
+			sb.WriteString(" ")
+			if join.Name == joinCandidates {
+				sb.WriteString(fmt.Sprintf(
+					join.SQL,
+					warehouse.DimCandidateTable(query.CandidateHistory),
+				))
+			} else {
+				sb.WriteString(join.SQL)
+			}
+		}
+	}
+
+	if len(where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(where, " AND "))
+	}
+
+	if len(query.Dimensions) > 0 {
+		groupBy := make([]string, len(query.Dimensions))
+		for i := range query.Dimensions {
+			groupBy[i] = fmt.Sprint(i + 1)
+		}
+
+		sb.WriteString(" GROUP BY ")
+		sb.WriteString(strings.Join(groupBy, ", "))
+
+		// keeps the order of the rows stable when no sort is given
+		if len(orderBy) == 0 {
+			orderBy = groupBy
+		}
+	}
+
+	if len(having) > 0 {
+		sb.WriteString(" HAVING ")
+		sb.WriteString(strings.Join(having, " AND "))
+	}
+
+	if len(orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(orderBy, ", "))
+	}
+
+	sb.WriteString(" LIMIT ")
+	sb.WriteString(c.arg(limit))
+
+	return &Statement{
+		SQL:     sb.String(),
+		Args:    c.args,
+		Columns: columns,
+	}, nil
+}
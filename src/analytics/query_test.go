@@ -0,0 +1,156 @@
+package analytics
+
+import (
+	"fmt"
+	"testing"
+
+	"api5back/src/model"
+	"api5back/src/warehouse"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	limit := 5
+
+	statement, err := Compile(model.AnalyticsQuery{
+		Dimensions: []string{"department", "date.month"},
+		Measures:   []string{"totalCandidatesHired", "averageHiringDays"},
+		Filters: []model.AnalyticsFilter{
+			{Field: "recruiter", Operator: "in", Values: []any{"Alice", "Bob"}},
+			{Field: "vacancy", Operator: "contains", Value: "engineer"},
+			{Field: "totalCandidatesHired", Operator: "gte", Value: 3},
+		},
+		Sort: []model.AnalyticsSort{
+			{Field: "totalCandidatesHired", Direction: "desc"},
+		},
+		Limit:        &limit,
+		AccessGroups: []int{2},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "SELECT dd.name AS \"department\", "+
+		"date_trunc('month', dt.date)::date AS \"date.month\", "+
+		"SUM(f.met_total_candidates_hired)::bigint AS \"totalCandidatesHired\", "+
+		"(SUM(cs.hiring_days)::float8 / NULLIF(SUM(cs.num_hired), 0)) AS \"averageHiringDays\" "+
+		"FROM fact_hiring_process f "+
+		"JOIN dim_process p ON p.id = f.dim_process_id "+
+		"JOIN dim_department dd ON dd.id = p.dim_department_id "+
+		"JOIN dim_vacancy v ON v.id = f.dim_vacancy_id "+
+		"JOIN dim_user u ON u.id = f.dim_user_id "+
+		"JOIN dim_datetime dt ON dt.id = f.dim_date_id "+
+		fmt.Sprintf(joins[5].SQL, warehouse.LatestDimCandidates)+" "+
+		"WHERE p.dim_department_id IN (SELECT d.id FROM dim_department d "+
+		"JOIN dim_department a ON a.db_id = d.db_id WHERE a.id IN ($1)) AND u.name IN ($2, $3) AND v.title ILIKE '%' || $4 || '%' ESCAPE '\\' "+
+		"GROUP BY 1, 2 "+
+		"HAVING SUM(f.met_total_candidates_hired)::bigint >= $5 "+
+		"ORDER BY \"totalCandidatesHired\" DESC "+
+		"LIMIT $6",
+		statement.SQL,
+	)
+	require.Equal(t, []any{2, "Alice", "Bob", "engineer", 3, 5}, statement.Args)
+	require.Equal(t, []model.AnalyticsColumn{
+		{Name: "department", Kind: FieldKindDimension, Type: FieldTypeString},
+		{Name: "date.month", Kind: FieldKindDimension, Type: FieldTypeDate},
+		{Name: "totalCandidatesHired", Kind: FieldKindMeasure, Type: FieldTypeNumber},
+		{Name: "averageHiringDays", Kind: FieldKindMeasure, Type: FieldTypeNumber},
+	}, statement.Columns)
+
+	escaped, err := Compile(model.AnalyticsQuery{
+		Measures: []string{"count"},
+		Filters: []model.AnalyticsFilter{
+			{Field: "vacancy", Operator: "contains", Value: `100%_sure\`},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []any{`100\%\_sure\\`, DefaultLimit}, escaped.Args)
+
+	history, err := Compile(model.AnalyticsQuery{
+		Measures:         []string{"hiredCandidateCount"},
+		CandidateHistory: true,
+	})
+	require.NoError(t, err)
+	require.Contains(t, history.SQL, "FROM dim_candidate c WHERE")
+}
+
+func TestCompileInvalidQueries(t *testing.T) {
+	limit := MaxLimit + 1
+
+	for i, testCase := range []struct {
+		Name  string
+		Query model.AnalyticsQuery
+	}{
+		{
+			Name:  "empty query",
+			Query: model.AnalyticsQuery{},
+		},
+		{
+			Name:  "unknown dimension",
+			Query: model.AnalyticsQuery{Dimensions: []string{"p.title; DROP TABLE dim_process"}},
+		},
+		{
+			Name:  "measure used as dimension",
+			Query: model.AnalyticsQuery{Dimensions: []string{"count"}},
+		},
+		{
+			Name:  "duplicated field",
+			Query: model.AnalyticsQuery{Measures: []string{"count", "count"}},
+		},
+		{
+			Name: "unknown operator",
+			Query: model.AnalyticsQuery{
+				Measures: []string{"count"},
+				Filters:  []model.AnalyticsFilter{{Field: "vacancy", Operator: "like", Value: "x"}},
+			},
+		},
+		{
+			Name: "contains a number",
+			Query: model.AnalyticsQuery{
+				Measures: []string{"count"},
+				Filters:  []model.AnalyticsFilter{{Field: "vacancy", Operator: "contains", Value: 1}},
+			},
+		},
+		{
+			Name: "contains on a number",
+			Query: model.AnalyticsQuery{
+				Measures: []string{"count"},
+				Filters:  []model.AnalyticsFilter{{Field: "numPositions", Operator: "contains", Value: 1}},
+			},
+		},
+		{
+			Name: "in without values",
+			Query: model.AnalyticsQuery{
+				Measures: []string{"count"},
+				Filters:  []model.AnalyticsFilter{{Field: "vacancy", Operator: "in"}},
+			},
+		},
+		{
+			Name: "sort by unselected field",
+			Query: model.AnalyticsQuery{
+				Measures: []string{"count"},
+				Sort:     []model.AnalyticsSort{{Field: "vacancy"}},
+			},
+		},
+		{
+			Name: "invalid sort direction",
+			Query: model.AnalyticsQuery{
+				Measures: []string{"count"},
+				Sort:     []model.AnalyticsSort{{Field: "count", Direction: "sideways"}},
+			},
+		},
+		{
+			Name: "limit above maximum",
+			Query: model.AnalyticsQuery{
+				Measures: []string{"count"},
+				Limit:    &limit,
+			},
+		},
+	} {
+		if testResult := t.Run(testCase.Name, func(t *testing.T) {
+			_, err := Compile(testCase.Query)
+			require.Error(t, err)
+		}); !testResult {
+			t.Errorf("Test case %d failed", i)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+)
+
+var monthNamesPt = [...]string{
+	"Janeiro", "Fevereiro", "Março", "Abril", "Maio", "Junho",
+	"Julho", "Agosto", "Setembro", "Outubro", "Novembro", "Dezembro",
+}
+
+var dayNamesPt = [...]string{
+	"Domingo", "Segunda-feira", "Terça-feira", "Quarta-feira",
+	"Quinta-feira", "Sexta-feira", "Sábado",
+}
+
+// Options configures a calendar. `FiscalYearStart` is the month the
+// fiscal year starts in, January when unset, and `Holidays` are the
+// holiday calendars observed, e.g. the national and a state one.
+// `HolidayDates` are single holidays observed besides the calendars,
+// such as the ones already loaded into the date dimension.
+type Options struct {
+	FiscalYearStart time.Month
+	Holidays        []HolidayCalendar
+	HolidayDates    []Holiday
+}
+
+// Day holds the attributes of a date of the calendar. `Weekday` goes
+// from 1, Monday, to 7, Sunday, as in ISO 8601, and the fiscal year is
+// named after the calendar year it starts in.
+type Day struct {
+	Date          time.Time
+	Year          int
+	Month         int
+	Day           int
+	Weekday       int
+	ISOYear       int
+	ISOWeek       int
+	Quarter       int
+	FiscalYear    int
+	FiscalQuarter int
+	MonthNamePt   string
+	MonthNameEn   string
+	DayNamePt     string
+	DayNameEn     string
+	IsWeekend     bool
+	IsHoliday     bool
+	// the names of every holiday of the date, joined by " / "
+	HolidayName string
+}
+
+// Calendar computes the attributes of dates, caching the holidays of
+// each year it is asked about. It is not safe for concurrent use.
+type Calendar struct {
+	options  Options
+	holidays map[int]map[time.Time][]string
+}
+
+func New(options Options) *Calendar {
+	if options.FiscalYearStart == 0 {
+		options.FiscalYearStart = time.January
+	}
+
+	return &Calendar{
+		options:  options,
+		holidays: make(map[int]map[time.Time][]string),
+	}
+}
+
+// Day returns the attributes of the date, ignoring its time of day.
+func (c *Calendar) Day(date time.Time) Day {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	isoYear, isoWeek := date.ISOWeek()
+
+	weekday := int(date.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+
+	// months since the start of the fiscal year
+	fiscalMonth := (int(date.Month()) - int(c.options.FiscalYearStart) + 12) % 12
+	fiscalYear := date.Year()
+	if date.Month() < c.options.FiscalYearStart {
+		fiscalYear--
+	}
+
+	holidayNames := c.holidaysOf(date.Year())[date]
+
+	return Day{
+		Date:          date,
+		Year:          date.Year(),
+		Month:         int(date.Month()),
+		Day:           date.Day(),
+		Weekday:       weekday,
+		ISOYear:       isoYear,
+		ISOWeek:       isoWeek,
+		Quarter:       (int(date.Month())-1)/3 + 1,
+		FiscalYear:    fiscalYear,
+		FiscalQuarter: fiscalMonth/3 + 1,
+		MonthNamePt:   monthNamesPt[date.Month()-1],
+		MonthNameEn:   date.Month().String(),
+		DayNamePt:     dayNamesPt[date.Weekday()],
+		DayNameEn:     date.Weekday().String(),
+		IsWeekend:     weekday >= 6,
+		IsHoliday:     len(holidayNames) > 0,
+		HolidayName:   strings.Join(holidayNames, " / "),
+	}
+}
+
+// IsBusinessDay reports whether the date is neither on a weekend nor a
+// holiday, ignoring its time of day.
+func (c *Calendar) IsBusinessDay(date time.Time) bool {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+
+	return len(c.holidaysOf(date.Year())[date]) == 0
+}
+
+// BusinessDaysBetween counts the business days after `from` up to and
+// including `to`, so that consecutive business days are one day apart
+// as consecutive dates are. It is negative when `to` is before `from`.
+func (c *Calendar) BusinessDaysBetween(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(from) {
+		return -c.BusinessDaysBetween(to, from)
+	}
+
+	days := 0
+	for date := from.AddDate(0, 0, 1); !date.After(to); date = date.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(date) {
+			days++
+		}
+	}
+
+	return days
+}
+
+func (c *Calendar) holidaysOf(year int) map[time.Time][]string {
+	if holidays, ok := c.holidays[year]; ok {
+		return holidays
+	}
+
+	holidays := make(map[time.Time][]string)
+	for _, holidayCalendar := range c.options.Holidays {
+		for _, holiday := range holidayCalendar.Holidays(year) {
+			if !containsName(holidays[holiday.Date], holiday.Name) {
+				holidays[holiday.Date] = append(holidays[holiday.Date], holiday.Name)
+			}
+		}
+	}
+
+	for _, holiday := range c.options.HolidayDates {
+		date := time.Date(holiday.Date.Year(), holiday.Date.Month(), holiday.Date.Day(), 0, 0, 0, 0, time.UTC)
+		if date.Year() == year && !containsName(holidays[date], holiday.Name) {
+			holidays[date] = append(holidays[date], holiday.Name)
+		}
+	}
+
+	c.holidays[year] = holidays
+	return holidays
+}
+
+func containsName(names []string, name string) bool {
+	for _, existing := range names {
+		if existing == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,140 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEaster(t *testing.T) {
+	for i, testCase := range []struct {
+		Year     int
+		Expected time.Time
+	}{
+		{Year: 2000, Expected: date(2000, time.April, 23)},
+		{Year: 2019, Expected: date(2019, time.April, 21)},
+		{Year: 2024, Expected: date(2024, time.March, 31)},
+		{Year: 2025, Expected: date(2025, time.April, 20)},
+		{Year: 2038, Expected: date(2038, time.April, 25)},
+	} {
+		if testResult := Easter(testCase.Year).Equal(testCase.Expected); !testResult {
+			t.Errorf("Test case %d failed", i)
+		}
+	}
+}
+
+func TestHolidays(t *testing.T) {
+	holidays := make(map[time.Time]string)
+	for _, holidayCalendar := range []HolidayCalendar{BrazilNational, BrazilOptional} {
+		for _, holiday := range holidayCalendar.Holidays(2024) {
+			holidays[holiday.Date] = holiday.Name
+		}
+	}
+
+	require.Equal(t, "Carnaval", holidays[date(2024, time.February, 12)])
+	require.Equal(t, "Carnaval", holidays[date(2024, time.February, 13)])
+	require.Equal(t, "Sexta-feira Santa", holidays[date(2024, time.March, 29)])
+	require.Equal(t, "Corpus Christi", holidays[date(2024, time.May, 30)])
+	require.Equal(t, "Natal", holidays[date(2024, time.December, 25)])
+	require.Contains(t, holidays, date(2024, time.November, 20))
+
+	for _, holiday := range BrazilNational.Holidays(2023) {
+		require.NotEqual(t, date(2023, time.November, 20), holiday.Date)
+	}
+}
+
+func TestCalendarDay(t *testing.T) {
+	calendar := New(Options{
+		FiscalYearStart: time.April,
+		Holidays: []HolidayCalendar{
+			BrazilNational,
+			BrazilStates["SP"],
+			{
+				Name: "company",
+				Fixed: []FixedHoliday{
+					{Month: time.July, Day: 9, Name: "Aniversário da empresa"},
+					{Month: time.December, Day: 25, Name: "Natal"},
+				},
+			},
+		},
+	})
+
+	require.Equal(t, Day{
+		Date:          date(2024, time.December, 30),
+		Year:          2024,
+		Month:         12,
+		Day:           30,
+		Weekday:       1,
+		ISOYear:       2025,
+		ISOWeek:       1,
+		Quarter:       4,
+		FiscalYear:    2024,
+		FiscalQuarter: 3,
+		MonthNamePt:   "Dezembro",
+		MonthNameEn:   "December",
+		DayNamePt:     "Segunda-feira",
+		DayNameEn:     "Monday",
+	}, calendar.Day(date(2024, time.December, 30)))
+
+	march := calendar.Day(time.Date(2024, time.March, 15, 13, 30, 0, 0, time.UTC))
+	require.Equal(t, date(2024, time.March, 15), march.Date)
+	require.Equal(t, 2023, march.FiscalYear)
+	require.Equal(t, 4, march.FiscalQuarter)
+	require.Equal(t, 1, march.Quarter)
+
+	april := calendar.Day(date(2024, time.April, 1))
+	require.Equal(t, 2024, april.FiscalYear)
+	require.Equal(t, 1, april.FiscalQuarter)
+
+	sunday := calendar.Day(date(2024, time.July, 7))
+	require.Equal(t, 7, sunday.Weekday)
+	require.Equal(t, "Domingo", sunday.DayNamePt)
+	require.True(t, sunday.IsWeekend)
+	require.False(t, sunday.IsHoliday)
+
+	july := calendar.Day(date(2024, time.July, 9))
+	require.True(t, july.IsHoliday)
+	require.Equal(t, "Revolução Constitucionalista / Aniversário da empresa", july.HolidayName)
+
+	christmas := calendar.Day(date(2024, time.December, 25))
+	require.Equal(t, "Natal", christmas.HolidayName)
+
+	require.Equal(t, 2023, New(Options{}).Day(date(2023, time.January, 1)).FiscalYear)
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	national := New(Options{Holidays: []HolidayCalendar{BrazilNational}})
+	dates := New(Options{HolidayDates: []Holiday{
+		{Date: time.Date(2024, time.June, 3, 12, 0, 0, 0, time.UTC), Name: "Recesso"},
+	}})
+
+	for i, testCase := range []struct {
+		Calendar *Calendar
+		From     time.Time
+		To       time.Time
+		Expected int
+	}{
+		{Calendar: national, From: date(2024, time.March, 4), To: date(2024, time.March, 4), Expected: 0},
+		{Calendar: national, From: date(2024, time.March, 4), To: date(2024, time.March, 8), Expected: 4},
+		{Calendar: national, From: date(2024, time.March, 8), To: date(2024, time.March, 11), Expected: 1},
+		{Calendar: national, From: date(2024, time.March, 25), To: date(2024, time.April, 1), Expected: 4},
+		{Calendar: national, From: date(2024, time.April, 1), To: date(2024, time.March, 25), Expected: -4},
+		{Calendar: national, From: date(2024, time.November, 14), To: date(2024, time.November, 21), Expected: 3},
+		{Calendar: dates, From: date(2024, time.May, 31), To: date(2024, time.June, 4), Expected: 1},
+		{Calendar: dates, From: date(2025, time.May, 30), To: date(2025, time.June, 3), Expected: 2},
+	} {
+		if testResult := testCase.Calendar.BusinessDaysBetween(testCase.From, testCase.To) == testCase.Expected; !testResult {
+			t.Errorf("Test case %d failed", i)
+		}
+	}
+
+	require.False(t, national.IsBusinessDay(date(2024, time.March, 29)))
+	require.False(t, national.IsBusinessDay(date(2024, time.March, 30)))
+	require.True(t, national.IsBusinessDay(date(2024, time.March, 28)))
+	require.True(t, dates.IsBusinessDay(date(2024, time.November, 20)))
+}
@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"time"
+)
+
+// FixedHoliday is a holiday on the same day of every year, from
+// `FirstYear` on when it is set.
+type FixedHoliday struct {
+	Month     time.Month `json:"month"`
+	Day       int        `json:"day"`
+	Name      string     `json:"name"`
+	FirstYear int        `json:"firstYear,omitempty"`
+}
+
+// EasterHoliday is a holiday `Offset` days after Easter Sunday, or
+// before it when negative.
+type EasterHoliday struct {
+	Offset int    `json:"offset"`
+	Name   string `json:"name"`
+}
+
+// HolidayCalendar is a set of holidays of a country or region, which
+// can be loaded from JSON to configure calendars other than the built
+// in ones.
+type HolidayCalendar struct {
+	Name   string          `json:"name"`
+	Fixed  []FixedHoliday  `json:"fixed"`
+	Easter []EasterHoliday `json:"easter"`
+}
+
+// Holiday is a date a holiday falls on.
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// Holidays returns the holidays of the calendar in the year, fixed ones
+// first, as dates in UTC.
+func (c HolidayCalendar) Holidays(year int) []Holiday {
+	var holidays []Holiday
+
+	for _, holiday := range c.Fixed {
+		if holiday.FirstYear != 0 && year < holiday.FirstYear {
+			continue
+		}
+
+		holidays = append(holidays, Holiday{
+			Date: time.Date(year, holiday.Month, holiday.Day, 0, 0, 0, 0, time.UTC),
+			Name: holiday.Name,
+		})
+	}
+
+	easter := Easter(year)
+	for _, holiday := range c.Easter {
+		holidays = append(holidays, Holiday{
+			Date: easter.AddDate(0, 0, holiday.Offset),
+			Name: holiday.Name,
+		})
+	}
+
+	return holidays
+}
+
+// Easter returns the date of Easter Sunday of the year in the Gregorian
+// calendar, by the anonymous Gregorian algorithm.
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// BrazilNational holds the national holidays of Brazil. Black
+// Consciousness Day is a national holiday since 2024.
+var BrazilNational = HolidayCalendar{
+	Name: "BR",
+	Fixed: []FixedHoliday{
+		{Month: time.January, Day: 1, Name: "Confraternização Universal"},
+		{Month: time.April, Day: 21, Name: "Tiradentes"},
+		{Month: time.May, Day: 1, Name: "Dia do Trabalho"},
+		{Month: time.September, Day: 7, Name: "Independência do Brasil"},
+		{Month: time.October, Day: 12, Name: "Nossa Senhora Aparecida"},
+		{Month: time.November, Day: 2, Name: "Finados"},
+		{Month: time.November, Day: 15, Name: "Proclamação da República"},
+		{Month: time.November, Day: 20, Name: "Dia Nacional de Zumbi e da Consciência Negra", FirstYear: 2024},
+		{Month: time.December, Day: 25, Name: "Natal"},
+	},
+	Easter: []EasterHoliday{
+		{Offset: -2, Name: "Sexta-feira Santa"},
+	},
+}
+
+// BrazilOptional holds the optional days off of Brazil, which most
+// employers observe although they are not national holidays.
+var BrazilOptional = HolidayCalendar{
+	Name: "BR-optional",
+	Easter: []EasterHoliday{
+		{Offset: -48, Name: "Carnaval"},
+		{Offset: -47, Name: "Carnaval"},
+		{Offset: 60, Name: "Corpus Christi"},
+	},
+}
+
+// BrazilStates holds the state holidays of Brazil, by the abbreviation
+// of the state.
+var BrazilStates = map[string]HolidayCalendar{
+	"BA": {
+		Name: "BA",
+		Fixed: []FixedHoliday{
+			{Month: time.July, Day: 2, Name: "Independência da Bahia"},
+		},
+	},
+	"DF": {
+		Name: "DF",
+		Fixed: []FixedHoliday{
+			{Month: time.November, Day: 30, Name: "Dia do Evangélico"},
+		},
+	},
+	"PR": {
+		Name: "PR",
+		Fixed: []FixedHoliday{
+			{Month: time.December, Day: 19, Name: "Emancipação Política do Paraná"},
+		},
+	},
+	"RJ": {
+		Name: "RJ",
+		Fixed: []FixedHoliday{
+			{Month: time.April, Day: 23, Name: "Dia de São Jorge"},
+		},
+	},
+	"SE": {
+		Name: "SE",
+		Fixed: []FixedHoliday{
+			{Month: time.July, Day: 8, Name: "Emancipação Política de Sergipe"},
+		},
+	},
+	"SP": {
+		Name: "SP",
+		Fixed: []FixedHoliday{
+			{Month: time.July, Day: 9, Name: "Revolução Constitucionalista"},
+		},
+	},
+}
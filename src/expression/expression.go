@@ -0,0 +1,320 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"api5back/ent/facthiringprocess"
+	"api5back/ent/predicate"
+	"api5back/src/model"
+	"api5back/src/processing"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// MaxDepth limits how deeply the logical operators can be nested.
+var MaxDepth = 10
+
+const (
+	OperatorAnd      = "and"
+	OperatorOr       = "or"
+	OperatorNot      = "not"
+	OperatorEq       = "eq"
+	OperatorNeq      = "neq"
+	OperatorGt       = "gt"
+	OperatorGte      = "gte"
+	OperatorLt       = "lt"
+	OperatorLte      = "lte"
+	OperatorIn       = "in"
+	OperatorBetween  = "between"
+	OperatorContains = "contains"
+)
+
+var comparisons = map[string]func(string, any) func(*sql.Selector){
+	OperatorEq:  sql.FieldEQ,
+	OperatorNeq: sql.FieldNEQ,
+	OperatorGt:  sql.FieldGT,
+	OperatorGte: sql.FieldGTE,
+	OperatorLt:  sql.FieldLT,
+	OperatorLte: sql.FieldLTE,
+}
+
+// orderedOperators are the operators that only make sense over fields
+// whose values have a natural order.
+var orderedOperators = []string{
+	OperatorGt,
+	OperatorGte,
+	OperatorLt,
+	OperatorLte,
+	OperatorBetween,
+}
+
+// LookupField finds a whitelisted field by name.
+func LookupField(name string) (Field, error) {
+	if field, ok := Fields[name]; ok {
+		return field, nil
+	}
+
+	return Field{}, fmt.Errorf("unknown field: %q", name)
+}
+
+// Compile validates the expression against the whitelisted fields and
+// translates it into a predicate over `FactHiringProcess`. Errors name
+// the path of the offending node, e.g. `and[1].or[0]`.
+func Compile(expression model.FilterExpression) (predicate.FactHiringProcess, error) {
+	return compile(expression, 1)
+}
+
+func compile(
+	expression model.FilterExpression,
+	depth int,
+) (predicate.FactHiringProcess, error) {
+	if depth > MaxDepth {
+		return nil, fmt.Errorf(
+			"expression is nested deeper than %d levels",
+			MaxDepth,
+		)
+	}
+
+	switch expression.Operator {
+	case OperatorAnd, OperatorOr, OperatorNot:
+		return compileLogical(expression, depth)
+	case "":
+		return nil, errors.New("expression requires an `op`")
+	default:
+		return compileComparison(expression)
+	}
+}
+
+func compileLogical(
+	expression model.FilterExpression,
+	depth int,
+) (predicate.FactHiringProcess, error) {
+	if expression.Field != "" || expression.Value != nil || len(expression.Values) > 0 {
+		return nil, fmt.Errorf(
+			"operator %q only takes `expressions`",
+			expression.Operator,
+		)
+	}
+
+	if len(expression.Expressions) == 0 {
+		return nil, fmt.Errorf(
+			"operator %q requires `expressions`",
+			expression.Operator,
+		)
+	}
+
+	if expression.Operator == OperatorNot && len(expression.Expressions) != 1 {
+		return nil, fmt.Errorf(
+			"operator %q requires exactly one expression, got %d",
+			expression.Operator,
+			len(expression.Expressions),
+		)
+	}
+
+	predicates := make([]predicate.FactHiringProcess, len(expression.Expressions))
+	for i, subexpression := range expression.Expressions {
+		p, err := compile(subexpression, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", expression.Operator, i, err)
+		}
+		predicates[i] = p
+	}
+
+	switch expression.Operator {
+	case OperatorAnd:
+		return facthiringprocess.And(predicates...), nil
+	case OperatorOr:
+		return facthiringprocess.Or(predicates...), nil
+	default:
+		return facthiringprocess.Not(predicates[0]), nil
+	}
+}
+
+func compileComparison(
+	expression model.FilterExpression,
+) (predicate.FactHiringProcess, error) {
+	operator := expression.Operator
+	if _, ok := comparisons[operator]; !ok &&
+		operator != OperatorIn &&
+		operator != OperatorBetween &&
+		operator != OperatorContains {
+		return nil, fmt.Errorf("unknown operator: %q", operator)
+	}
+
+	if len(expression.Expressions) > 0 {
+		return nil, fmt.Errorf("operator %q does not take `expressions`", operator)
+	}
+
+	if expression.Field == "" {
+		return nil, fmt.Errorf("operator %q requires a `field`", operator)
+	}
+
+	field, err := LookupField(expression.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(orderedOperators, operator) &&
+		(field.Type == FieldTypeString || field.Type == FieldTypeEnum) {
+		return nil, fmt.Errorf(
+			"operator %q is not supported by field %q",
+			operator,
+			field.Name,
+		)
+	}
+
+	switch operator {
+	case OperatorIn, OperatorBetween:
+		if expression.Value != nil {
+			return nil, fmt.Errorf(
+				"operator %q for field %q takes `values`, not a `value`",
+				operator,
+				field.Name,
+			)
+		}
+
+		if operator == OperatorIn && len(expression.Values) == 0 {
+			return nil, fmt.Errorf(
+				"operator %q for field %q requires `values`",
+				operator,
+				field.Name,
+			)
+		}
+
+		if operator == OperatorBetween && len(expression.Values) != 2 {
+			return nil, fmt.Errorf(
+				"operator %q for field %q requires exactly two `values`, got %d",
+				operator,
+				field.Name,
+				len(expression.Values),
+			)
+		}
+
+		values := make([]any, len(expression.Values))
+		for i, value := range expression.Values {
+			values[i], err = convert(field, value)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if operator == OperatorIn {
+			return field.scope(sql.FieldIn(field.Column, values...)), nil
+		}
+
+		return field.scope(func(s *sql.Selector) {
+			sql.FieldGTE(field.Column, values[0])(s)
+			sql.FieldLTE(field.Column, values[1])(s)
+		}), nil
+	}
+
+	if len(expression.Values) > 0 {
+		return nil, fmt.Errorf(
+			"operator %q for field %q takes a `value`, not `values`",
+			operator,
+			field.Name,
+		)
+	}
+
+	if expression.Value == nil {
+		return nil, fmt.Errorf(
+			"operator %q for field %q requires a `value`",
+			operator,
+			field.Name,
+		)
+	}
+
+	if operator == OperatorContains {
+		substring, ok := expression.Value.(string)
+		if field.Type != FieldTypeString || !ok {
+			return nil, fmt.Errorf(
+				"operator %q requires a string field and value, got field %q",
+				operator,
+				field.Name,
+			)
+		}
+
+		return field.scope(sql.FieldContainsFold(field.Column, substring)), nil
+	}
+
+	value, err := convert(field, expression.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return field.scope(comparisons[operator](field.Column, value)), nil
+}
+
+// convert checks that the JSON value matches the type of the field and
+// converts it into the value stored by the column.
+func convert(field Field, value any) (any, error) {
+	invalid := func() error {
+		return fmt.Errorf(
+			"invalid value %v for %s field %q",
+			value,
+			field.Type,
+			field.Name,
+		)
+	}
+
+	var number float64
+	isNumber := true
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case int:
+		number = float64(v)
+	default:
+		isNumber = false
+	}
+
+	switch field.Type {
+	case FieldTypeInteger:
+		if !isNumber || number != math.Trunc(number) {
+			return nil, invalid()
+		}
+		return int(number), nil
+	case FieldTypeFloat:
+		if !isNumber || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, invalid()
+		}
+		return number, nil
+	case FieldTypeString:
+		if _, ok := value.(string); !ok {
+			return nil, invalid()
+		}
+		return value, nil
+	case FieldTypeDate:
+		dateString, ok := value.(string)
+		if !ok {
+			return nil, invalid()
+		}
+		date, err := processing.ParseStringToPgtypeDate("2006-01-02", dateString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date for field %q: %w", field.Name, err)
+		}
+		return date, nil
+	case FieldTypeEnum:
+		if isNumber {
+			position := int(number)
+			if number != math.Trunc(number) || position < 1 || position > len(field.Enum) {
+				return nil, invalid()
+			}
+			return field.Enum[position-1], nil
+		}
+		if enumValue, ok := value.(string); ok && slices.Contains(field.Enum, enumValue) {
+			return enumValue, nil
+		}
+		return nil, fmt.Errorf(
+			"invalid value %v for field %q, expected one of %q",
+			value,
+			field.Name,
+			field.Enum,
+		)
+	default:
+		return nil, invalid()
+	}
+}
@@ -0,0 +1,203 @@
+package expression
+
+import (
+	"strings"
+	"testing"
+
+	"api5back/src/model"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func render(t *testing.T, expression model.FilterExpression) (string, []any) {
+	p, err := Compile(expression)
+	require.NoError(t, err)
+
+	selector := sql.Dialect(dialect.Postgres).
+		Select("*").
+		From(sql.Table("fact_hiring_process"))
+	p(selector)
+
+	return selector.Query()
+}
+
+func TestCompile(t *testing.T) {
+	query, args := render(t, model.FilterExpression{
+		Operator: OperatorAnd,
+		Expressions: []model.FilterExpression{
+			{
+				Operator: OperatorOr,
+				Expressions: []model.FilterExpression{
+					{Operator: OperatorEq, Field: "recruiter", Value: 1.0},
+					{Operator: OperatorEq, Field: "recruiter", Value: 2.0},
+				},
+			},
+			{
+				Operator: OperatorNot,
+				Expressions: []model.FilterExpression{
+					{Operator: OperatorEq, Field: "processStatus", Value: 3.0},
+				},
+			},
+			{Operator: OperatorGte, Field: "numPositions", Value: 3.0},
+			{Operator: OperatorGt, Field: "score", Value: 70.0},
+		},
+	})
+
+	require.Equal(t, `SELECT * FROM "fact_hiring_process" WHERE `+
+		`("fact_hiring_process"."dim_user_id" = $1 OR "fact_hiring_process"."dim_user_id" = $2) `+
+		`AND (NOT (EXISTS (SELECT "dim_process"."id" FROM "dim_process" `+
+		`WHERE "fact_hiring_process"."dim_process_id" = "dim_process"."id" AND "dim_process"."status" = $3))) `+
+		`AND EXISTS (SELECT "dim_vacancy"."id" FROM "dim_vacancy" `+
+		`WHERE "fact_hiring_process"."dim_vacancy_id" = "dim_vacancy"."id" AND "dim_vacancy"."num_positions" >= $4) `+
+		`AND EXISTS (SELECT "dim_vacancy"."id" FROM "dim_vacancy" `+
+		`WHERE "fact_hiring_process"."dim_vacancy_id" = "dim_vacancy"."id" `+
+		`AND EXISTS (SELECT "dim_candidate"."dim_vacancy_db_id" FROM "dim_candidate" `+
+		`WHERE ("dim_vacancy"."id" = "dim_candidate"."dim_vacancy_db_id" `+
+		`AND NOT EXISTS (SELECT "newer_dim_candidate"."id" FROM "dim_candidate" AS "newer_dim_candidate" `+
+		`WHERE "newer_dim_candidate"."db_id" = "dim_candidate"."db_id" `+
+		`AND "newer_dim_candidate"."dim_vacancy_db_id" = "dim_candidate"."dim_vacancy_db_id" `+
+		`AND "newer_dim_candidate"."id" > "dim_candidate"."id")) `+
+		`AND "dim_candidate"."score" > $5))`,
+		query,
+	)
+	require.Equal(t, []any{1, 2, "Closed", 3, 70.0}, args)
+}
+
+func TestCompileComparisons(t *testing.T) {
+	for i, testCase := range []struct {
+		Name             string
+		Expression       model.FilterExpression
+		ExpectedFragment string
+		ExpectedArgs     []any
+	}{
+		{
+			Name: "in",
+			Expression: model.FilterExpression{
+				Operator: OperatorIn, Field: "vacancyStatus",
+				Values: []any{"Open", 2.0},
+			},
+			ExpectedFragment: `"dim_vacancy"."status" IN ($1, $2)`,
+			ExpectedArgs:     []any{"Open", "In Analysis"},
+		},
+		{
+			Name: "between",
+			Expression: model.FilterExpression{
+				Operator: OperatorBetween, Field: "totalCandidatesHired",
+				Values: []any{1.0, 5.0},
+			},
+			ExpectedFragment: `"fact_hiring_process"."met_total_candidates_hired" >= $1 ` +
+				`AND "fact_hiring_process"."met_total_candidates_hired" <= $2`,
+			ExpectedArgs: []any{1, 5},
+		},
+		{
+			Name: "contains",
+			Expression: model.FilterExpression{
+				Operator: OperatorContains, Field: "vacancyTitle",
+				Value: "Engineer",
+			},
+			ExpectedFragment: `"dim_vacancy"."title" ILIKE $1`,
+			ExpectedArgs:     []any{"%engineer%"},
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			query, args := render(t, testCase.Expression)
+
+			if testResult := strings.Contains(query, testCase.ExpectedFragment); !testResult {
+				t.Errorf("Test case %d failed: %s", i, query)
+			}
+			require.Equal(t, testCase.ExpectedArgs, args)
+		})
+	}
+}
+
+func TestCompileInvalidExpressions(t *testing.T) {
+	nested := model.FilterExpression{Operator: OperatorEq, Field: "recruiter", Value: 1.0}
+	for range MaxDepth {
+		nested = model.FilterExpression{
+			Operator:    OperatorNot,
+			Expressions: []model.FilterExpression{nested},
+		}
+	}
+
+	for i, testCase := range []struct {
+		Name          string
+		Expression    model.FilterExpression
+		ExpectedError string
+	}{
+		{
+			Name:          "missing operator",
+			Expression:    model.FilterExpression{Field: "recruiter", Value: 1.0},
+			ExpectedError: "expression requires an `op`",
+		},
+		{
+			Name:          "unknown operator",
+			Expression:    model.FilterExpression{Operator: "like", Field: "recruiter", Value: 1.0},
+			ExpectedError: `unknown operator: "like"`,
+		},
+		{
+			Name:          "unknown field",
+			Expression:    model.FilterExpression{Operator: OperatorEq, Field: "salary", Value: 1.0},
+			ExpectedError: `unknown field: "salary"`,
+		},
+		{
+			Name: "path of a nested error",
+			Expression: model.FilterExpression{
+				Operator: OperatorAnd,
+				Expressions: []model.FilterExpression{
+					{Operator: OperatorEq, Field: "recruiter", Value: 1.0},
+					{
+						Operator: OperatorOr,
+						Expressions: []model.FilterExpression{
+							{Operator: OperatorEq, Field: "numPositions", Value: 1.5},
+						},
+					},
+				},
+			},
+			ExpectedError: `and[1]: or[0]: invalid value 1.5 for integer field "numPositions"`,
+		},
+		{
+			Name: "not with two expressions",
+			Expression: model.FilterExpression{
+				Operator:    OperatorNot,
+				Expressions: []model.FilterExpression{nested.Expressions[0], nested.Expressions[0]},
+			},
+			ExpectedError: `operator "not" requires exactly one expression, got 2`,
+		},
+		{
+			Name:          "ordered operator over an enum",
+			Expression:    model.FilterExpression{Operator: OperatorGte, Field: "processStatus", Value: 1.0},
+			ExpectedError: `operator "gte" is not supported by field "processStatus"`,
+		},
+		{
+			Name:          "between with one value",
+			Expression:    model.FilterExpression{Operator: OperatorBetween, Field: "score", Values: []any{1.0}},
+			ExpectedError: `operator "between" for field "score" requires exactly two ` + "`values`" + `, got 1`,
+		},
+		{
+			Name:          "unknown enum value",
+			Expression:    model.FilterExpression{Operator: OperatorEq, Field: "vacancyStatus", Value: "Paused"},
+			ExpectedError: `invalid value Paused for field "vacancyStatus", expected one of ["Open" "In Analysis" "Closed"]`,
+		},
+		{
+			Name:          "invalid date",
+			Expression:    model.FilterExpression{Operator: OperatorGte, Field: "openingDate", Value: "01/02/2024"},
+			ExpectedError: `invalid date for field "openingDate"`,
+		},
+		{
+			Name:          "too deep",
+			Expression:    nested,
+			ExpectedError: "nested deeper than",
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			_, err := Compile(testCase.Expression)
+
+			if testResult := err != nil &&
+				strings.Contains(err.Error(), testCase.ExpectedError); !testResult {
+				t.Errorf("Test case %d failed: %v", i, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package expression
+
+import (
+	"api5back/ent/dimcandidate"
+	"api5back/ent/dimprocess"
+	"api5back/ent/dimvacancy"
+	"api5back/ent/facthiringprocess"
+	"api5back/ent/predicate"
+	"api5back/src/property"
+	"api5back/src/warehouse"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	FieldTypeInteger = "integer"
+	FieldTypeFloat   = "float"
+	FieldTypeString  = "string"
+	FieldTypeDate    = "date"
+	FieldTypeEnum    = "enum"
+)
+
+// scope turns a predicate over the columns of a table into a predicate
+// over `FactHiringProcess`.
+type scope func(predicate func(*sql.Selector)) predicate.FactHiringProcess
+
+func factScope(p func(*sql.Selector)) predicate.FactHiringProcess {
+	return predicate.FactHiringProcess(p)
+}
+
+func processScope(p func(*sql.Selector)) predicate.FactHiringProcess {
+	return facthiringprocess.HasDimProcessWith(predicate.DimProcess(p))
+}
+
+func vacancyScope(p func(*sql.Selector)) predicate.FactHiringProcess {
+	return facthiringprocess.HasDimVacancyWith(predicate.DimVacancy(p))
+}
+
+// candidateScope matches the facts whose vacancy has at least one
+// candidate whose latest version satisfies the predicate.
+func candidateScope(p func(*sql.Selector)) predicate.FactHiringProcess {
+	return facthiringprocess.HasDimVacancyWith(
+		dimvacancy.HasDimCandidatesWith(
+			warehouse.LatestDimCandidate,
+			predicate.DimCandidate(p),
+		),
+	)
+}
+
+// Field is a whitelisted column reachable from `FactHiringProcess`.
+// Enum fields accept either one of `Enum` or its 1-based position, the
+// same values accepted by the legacy status filters.
+type Field struct {
+	Name   string
+	Type   string
+	Column string
+	Enum   []string
+	scope  scope
+}
+
+func field(name, fieldType, column string, scope scope) Field {
+	return Field{
+		Name:   name,
+		Type:   fieldType,
+		Column: column,
+		scope:  scope,
+	}
+}
+
+func enum(name, column string, values []string, scope scope) Field {
+	return Field{
+		Name:   name,
+		Type:   FieldTypeEnum,
+		Column: column,
+		Enum:   values,
+		scope:  scope,
+	}
+}
+
+func registry(fields ...Field) map[string]Field {
+	fieldsByName := make(map[string]Field)
+	for _, field := range fields {
+		fieldsByName[field.Name] = field
+	}
+
+	return fieldsByName
+}
+
+// Fields are the fields a filter expression can compare.
+var Fields = registry(
+	field("recruiter", FieldTypeInteger, facthiringprocess.FieldDimUserId, factScope),
+	field("process", FieldTypeInteger, facthiringprocess.FieldDimProcessId, factScope),
+	field("vacancy", FieldTypeInteger, facthiringprocess.FieldDimVacancyId, factScope),
+	field("totalCandidatesApplied", FieldTypeInteger, facthiringprocess.FieldMetTotalCandidatesApplied, factScope),
+	field("totalCandidatesInterviewed", FieldTypeInteger, facthiringprocess.FieldMetTotalCandidatesInterviewed, factScope),
+	field("totalCandidatesHired", FieldTypeInteger, facthiringprocess.FieldMetTotalCandidatesHired, factScope),
+	field("totalFeedbackPositive", FieldTypeInteger, facthiringprocess.FieldMetTotalFeedbackPositive, factScope),
+	field("totalFeedbackNeutral", FieldTypeInteger, facthiringprocess.FieldMetTotalNeutral, factScope),
+	field("totalFeedbackNegative", FieldTypeInteger, facthiringprocess.FieldMetTotalNegative, factScope),
+	field("department", FieldTypeInteger, dimprocess.FieldDimDepartmentId, processScope),
+	field("processTitle", FieldTypeString, dimprocess.FieldTitle, processScope),
+	field("processInitialDate", FieldTypeDate, dimprocess.FieldInitialDate, processScope),
+	field("processFinishDate", FieldTypeDate, dimprocess.FieldFinishDate, processScope),
+	enum("processStatus", dimprocess.FieldStatus, property.DimProcessStatus(0).Values(), processScope),
+	field("vacancyTitle", FieldTypeString, dimvacancy.FieldTitle, vacancyScope),
+	field("numPositions", FieldTypeInteger, dimvacancy.FieldNumPositions, vacancyScope),
+	field("location", FieldTypeString, dimvacancy.FieldLocation, vacancyScope),
+	field("openingDate", FieldTypeDate, dimvacancy.FieldOpeningDate, vacancyScope),
+	field("closingDate", FieldTypeDate, dimvacancy.FieldClosingDate, vacancyScope),
+	enum("vacancyStatus", dimvacancy.FieldStatus, property.DimVacancyStatus(0).Values(), vacancyScope),
+	field("score", FieldTypeFloat, dimcandidate.FieldScore, candidateScope),
+)
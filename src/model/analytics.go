@@ -0,0 +1,50 @@
+package model
+
+import "api5back/src/processing"
+
+// AnalyticsQuery represents a query over the hiring process star schema.
+// `Dimensions`, `Measures` and the fields of `Filters` and `Sort` must be
+// names whitelisted by the `analytics` package.
+type AnalyticsQuery struct {
+	Dimensions   []string          `json:"dimensions"`
+	Measures     []string          `json:"measures"`
+	Filters      []AnalyticsFilter `json:"filters"`
+	Sort         []AnalyticsSort   `json:"sort"`
+	Limit        *int              `json:"limit"`
+	AccessGroups []int             `json:"accessGroup"`
+	// when set, the candidate measures count every version loaded of
+	// each candidate instead of only its latest version
+	CandidateHistory bool `json:"candidateHistory"`
+}
+
+// AnalyticsFilter compares a dimension or measure to `Value`, or to
+// `Values` when the operator is "in".
+type AnalyticsFilter struct {
+	Field    string `json:"field" binding:"required"`
+	Operator string `json:"operator" binding:"required"`
+	Value    any    `json:"value"`
+	Values   []any  `json:"values"`
+}
+
+type AnalyticsSort struct {
+	Field     string `json:"field" binding:"required"`
+	Direction string `json:"direction"`
+}
+
+type AnalyticsColumn struct {
+	Name string `json:"name"`
+	// either "dimension" or "measure"
+	Kind string `json:"kind"`
+	// either "string", "number" or "date"
+	Type string `json:"type"`
+}
+
+type AnalyticsResult struct {
+	Columns []AnalyticsColumn `json:"columns"`
+	Rows    [][]any           `json:"rows"`
+}
+
+type MetricCatalog struct {
+	Version int                            `json:"version"`
+	Metrics []*processing.MetricDefinition `json:"metrics"`
+}
@@ -1,11 +1,26 @@
 package model
 
-import "api5back/src/processing"
+import (
+	"encoding/json"
+
+	"api5back/src/processing"
+)
 
 type DashboardMetrics struct {
 	VacancySummary    processing.VacancyStatusSummary      `json:"vacancyStatus"`
 	CardInfos         processing.CardInfos                 `json:"cards"`
 	AverageHiringTime processing.AverageHiringTimePerMonth `json:"averageHiringTime"`
+	HiringTime        processing.HiringTimeStatistics      `json:"hiringTime"`
+	Comparison        *DashboardComparison                 `json:"comparison,omitempty"`
+}
+
+// DashboardComparison holds the deltas between the dashboard metrics
+// of the requested `DateRange` and the period it is compared against.
+type DashboardComparison struct {
+	Period         processing.ComparisonPeriod               `json:"period"`
+	DateRange      DateRange                                 `json:"dateRange"`
+	CardInfos      processing.CardInfosComparison            `json:"cards"`
+	VacancySummary processing.VacancyStatusSummaryComparison `json:"vacancyStatus"`
 }
 
 type DashboardTableRow struct {
@@ -18,4 +33,47 @@ type DashboardTableRow struct {
 	NumHired          int      `json:"numHired"`
 	AverageHiringTime *float32 `json:"averageHiringTime"`
 	NumFeedback       int      `json:"numFeedback"`
+	// when set, only these columns are serialized
+	Columns []string `json:"-"`
+}
+
+// TableTotals aggregates the rows of the vacancy table. The competition
+// rate is weighted by the positions of each row and the average hiring
+// time by the hired candidates of each row. `ProcessId` and
+// `ProcessTitle` are only set on the subtotals of a process.
+type TableTotals struct {
+	ProcessId         *int     `json:"processId,omitempty"`
+	ProcessTitle      string   `json:"processTitle,omitempty"`
+	NumRows           int      `json:"numRows"`
+	NumPositions      int      `json:"numPositions"`
+	NumCandidates     int      `json:"numCandidates"`
+	CompetitionRate   *float32 `json:"competitionRate"`
+	NumInterviewed    int      `json:"numInterviewed"`
+	NumHired          int      `json:"numHired"`
+	AverageHiringTime *float32 `json:"averageHiringTime"`
+	NumFeedback       int      `json:"numFeedback"`
+}
+
+func (row DashboardTableRow) MarshalJSON() ([]byte, error) {
+	// the alias drops this method, avoiding an infinite recursion
+	type dashboardTableRow DashboardTableRow
+
+	data, err := json.Marshal(dashboardTableRow(row))
+	if err != nil || len(row.Columns) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]json.RawMessage, len(row.Columns))
+	for _, column := range row.Columns {
+		if value, ok := fields[column]; ok {
+			selected[column] = value
+		}
+	}
+
+	return json.Marshal(selected)
 }
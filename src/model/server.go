@@ -1,21 +1,42 @@
 package model
 
-// DateRange represents a date range with a start and end date.
+import "api5back/src/processing"
+
+// DateRange represents a date range with a start and end date. Both
+// dates are inclusive and either may be left empty for an open range.
+//
+// `DateField` picks the dates being filtered, see `processing.DateField`;
+// it defaults to the vacancy period. For the vacancy and process periods,
+// `Match` picks between `overlap` (the default), matching intervals that
+// share at least one day with the range, and `contains`, matching
+// intervals that lie entirely within it. Other fields match a single
+// date within the range and ignore `Match`.
 type DateRange struct {
-	StartDate string `json:"startDate" form:"startDate" time_format:"2024-10-01" default:""`
-	EndDate   string `json:"endDate" form:"endDate" time_format:"2024-10-01" default:""`
+	StartDate string               `json:"startDate" form:"startDate" time_format:"2024-10-01" default:""`
+	EndDate   string               `json:"endDate" form:"endDate" time_format:"2024-10-01" default:""`
+	DateField processing.DateField `json:"dateField,omitempty" form:"dateField" enums:"vacancyPeriod,processPeriod,processStart,candidateApply,candidateHire,loadDate" default:"vacancyPeriod"`
+	Match     processing.DateMatch `json:"match,omitempty" form:"match" enums:"overlap,contains" default:"overlap"`
 }
 
-// Page represents a page of items in paginated responses.
+// Page represents a page of items in paginated responses. The cursors
+// are empty when there is no page in their direction.
 type Page[T any] struct {
-	Items       []T `json:"items"`
-	NumMaxPages int `json:"numMaxPages"`
+	Items       []T     `json:"items"`
+	NumMaxPages int     `json:"numMaxPages"`
+	TotalItems  int     `json:"totalItems"`
+	NextCursor  *string `json:"nextCursor"`
+	PrevCursor  *string `json:"prevCursor"`
+	// aggregates of the whole filtered set, for the tables that have them
+	Totals    *TableTotals  `json:"totals,omitempty"`
+	Subtotals []TableTotals `json:"subtotals,omitempty"`
 }
 
-// PageRequest is the base type of request for a page of items.
+// PageRequest is the base type of request for a page of items. When
+// `Cursor` is set, it takes precedence over `Page`.
 type PageRequest struct {
-	Page     *int `json:"page" default:"1"`
-	PageSize *int `json:"pageSize" default:"10"`
+	Page     *int    `json:"page" default:"1"`
+	PageSize *int    `json:"pageSize" default:"10"`
+	Cursor   *string `json:"cursor"`
 }
 
 func (pr *PageRequest) GetPageRequest() *PageRequest {
@@ -37,9 +58,51 @@ type FactHiringProcessFilter struct {
 	ProcessStatus []int      `json:"processStatus"`
 	VacancyStatus []int      `json:"vacancyStatus"`
 	AccessGroups  []int      `json:"accessGroup"`
+	// width in days of each bucket of the hiring time histogram
+	HiringTimeBucketWidth *int `json:"hiringTimeBucketWidth"`
+	// unit the durations are counted in, `calendarDays` by default or
+	// `businessDays`, which leave out weekends and the holidays of the
+	// date dimension
+	DurationUnit processing.DurationUnit `json:"durationUnit" enums:"calendarDays,businessDays" default:"calendarDays"`
+	// when set, the metrics of the `DateRange` are also compared
+	// against the given period
+	Comparison *processing.ComparisonPeriod `json:"comparison"`
+	Deadline   *DeadlineFilter              `json:"deadline"`
+	// combined with the other fields of the filter with AND
+	Where *FilterExpression `json:"where"`
+	// when set, as `2006-01-02`, only the facts loaded up to the date
+	// whose process, department, vacancy and recruiter had a version
+	// valid at the date are queried, as the warehouse stood back then
+	AsOf string `json:"asOf"`
+	// when set, every version loaded of each candidate is counted, for
+	// audit reports, instead of only its latest version. Filters always
+	// match the latest versions.
+	CandidateHistory bool `json:"candidateHistory"`
 	*PageRequest
 }
 
+// FilterExpression is a node of a boolean filter over whitelisted fact
+// and dimension fields. The logical operators `and`, `or` and `not` take
+// `Expressions`, and the comparison operators take a `Field` and either
+// a `Value` or, for `in` and `between`, `Values`.
+type FilterExpression struct {
+	Operator    string             `json:"op"`
+	Field       string             `json:"field,omitempty"`
+	Value       any                `json:"value,omitempty"`
+	Values      []any              `json:"values,omitempty"`
+	Expressions []FilterExpression `json:"expressions,omitempty"`
+}
+
+// DeadlineFilter configures the rule that decides which processes are
+// approaching their deadline. The thresholds are fractions of the total
+// duration of a process, and `DepartmentThresholds` is keyed by the ID
+// of the `DimDepartment` of the process.
+type DeadlineFilter struct {
+	Threshold            *float64        `json:"threshold"`
+	DepartmentThresholds map[int]float64 `json:"departmentThresholds"`
+	ReferenceDate        string          `json:"referenceDate"`
+}
+
 // SuggestionsPageRequest represents a paginated query for suggestions.
 // It includes a list of department IDs for filtering.
 type SuggestionsPageRequest struct {
@@ -68,3 +131,102 @@ func (s *SuggestionsFilter) GetPageRequest() *PageRequest {
 	}
 	return s.PageRequest
 }
+
+// CandidateAgingFilter represents a filter for the candidates stuck in a
+// stage for at least `MinDays` at the `ReferenceDate`, which defaults to
+// the current date. `Stages` holds `DimCandidateStatus` values and
+// defaults to "In Analysis" and "Interview".
+type CandidateAgingFilter struct {
+	Stages        []int  `json:"stages"`
+	MinDays       int    `json:"minDays"`
+	ReferenceDate string `json:"referenceDate"`
+	FactHiringProcessFilter
+}
+
+// PositionBurndownFilter represents a filter for the position burndown.
+// `ReferenceDate`, as `2006-01-02`, is the date the hire rate is
+// measured up to and the projections start from, today by default.
+type PositionBurndownFilter struct {
+	ReferenceDate string `json:"referenceDate"`
+	FactHiringProcessFilter
+}
+
+// HiringForecastFilter represents a filter for the hiring forecast. The
+// open vacancies of the filtered facts are forecast from the history of
+// every vacancy within `AccessGroups`. `ReferenceDate`, as `2006-01-02`,
+// defaults to today; a past date backtests the forecast, leaving out
+// what happened after it. The other fields default to the defaults of
+// `processing.ForecastOptions`.
+type HiringForecastFilter struct {
+	ReferenceDate string `json:"referenceDate"`
+	MinSamples    *int   `json:"minSamples"`
+	HistoryWeeks  *int   `json:"historyWeeks"`
+	HorizonWeeks  *int   `json:"horizonWeeks"`
+	FactHiringProcessFilter
+}
+
+// VacancyTableFilter represents a filter for the vacancy table. `Search`
+// is matched against the process and vacancy titles, and `Columns`, when
+// set, restricts the columns of each row. Both `Sort` and `Columns` take
+// the JSON names of the columns of `DashboardTableRow`.
+type VacancyTableFilter struct {
+	Sort    []TableSort `json:"sort"`
+	Search  string      `json:"search"`
+	Columns []string    `json:"columns"`
+	// when set, the page also holds the totals of each process
+	Subtotals bool `json:"subtotals"`
+	FactHiringProcessFilter
+}
+
+// TableSort orders a table by a column, in "asc" or "desc" direction.
+type TableSort struct {
+	Column    string `json:"column"`
+	Direction string `json:"direction"`
+}
+
+// CohortFilter represents a filter for the cohort matrix of candidates.
+// `Granularity` defaults to "month" and `Horizons`, in days, default
+// to 7, 14, 30 and 60.
+type CohortFilter struct {
+	Granularity processing.CohortGranularity `json:"granularity"`
+	Horizons    []int                        `json:"horizons"`
+	FactHiringProcessFilter
+}
+
+// ScoreFilter represents a filter for the candidate score analytics.
+// `BandWidth` defaults to 10 score points and `HireRetention`, the
+// percentage of hires the suggested score threshold must keep, to 90.
+type ScoreFilter struct {
+	BandWidth     *float64 `json:"bandWidth"`
+	HireRetention *float64 `json:"hireRetention"`
+	FactHiringProcessFilter
+}
+
+// TimeInStageFilter represents a filter for the time candidates spend
+// in each status. `GroupBy` is "vacancy", the default, "recruiter" or
+// "department".
+type TimeInStageFilter struct {
+	GroupBy string `json:"groupBy" enums:"vacancy,recruiter,department" default:"vacancy"`
+	FactHiringProcessFilter
+}
+
+// FeedbackSentimentFilter represents a filter for the feedback sentiment
+// trend. `Granularity` defaults to "month".
+type FeedbackSentimentFilter struct {
+	Granularity processing.CohortGranularity `json:"granularity" enums:"month,week" default:"month"`
+	FactHiringProcessFilter
+}
+
+// PipelineTrendFilter represents a filter for the pipeline trend, read
+// from the daily pipeline snapshots. `Granularity` is "day", "week" or
+// "month", the default, and the dates, as `2006-01-02`, bound the dates
+// of the snapshots. `AccessGroups` restricts the departments like
+// `Departments` does.
+type PipelineTrendFilter struct {
+	Granularity  processing.CohortGranularity `json:"granularity" enums:"day,week,month" default:"month"`
+	StartDate    string                       `json:"startDate"`
+	EndDate      string                       `json:"endDate"`
+	Departments  []int                        `json:"departments"`
+	Recruiters   []int                        `json:"recruiters"`
+	AccessGroups []int                        `json:"accessGroup"`
+}
@@ -0,0 +1,63 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Cursor points to the item of a page boundary by the values of its sort
+// keys. A backward cursor requests the items before that item.
+type Cursor struct {
+	Values   []any `json:"v"`
+	Backward bool  `json:"b,omitempty"`
+}
+
+// EncodeCursor serializes the cursor into an opaque URL safe token.
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	invalid := func(err error) (Cursor, error) {
+		return Cursor{}, fmt.Errorf("%w: invalid cursor: %w", ErrInvalidPageRequest, err)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return invalid(err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var cursor Cursor
+	if err := decoder.Decode(&cursor); err != nil {
+		return invalid(err)
+	}
+
+	if len(cursor.Values) == 0 {
+		return invalid(fmt.Errorf("missing values"))
+	}
+
+	// numbers are restored as integers whenever possible, so they can be
+	// compared against integer columns
+	for i, value := range cursor.Values {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+
+		if integer, err := number.Int64(); err == nil {
+			cursor.Values[i] = integer
+		} else if float, err := number.Float64(); err == nil {
+			cursor.Values[i] = float
+		} else {
+			return invalid(err)
+		}
+	}
+
+	return cursor, nil
+}
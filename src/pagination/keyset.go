@@ -0,0 +1,155 @@
+package pagination
+
+import (
+	"fmt"
+	"slices"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// Key is a sort key of a keyset. `Expression` returns the SQL of the key
+// for the selector of the query. Empty values are always sorted last.
+type Key struct {
+	Expression func(s *sql.Selector) string
+	Descending bool
+}
+
+// IDKey sorts by the primary key of the queried table. Every keyset must
+// end with a unique key, so its order is total.
+var IDKey = Key{
+	Expression: func(s *sql.Selector) string {
+		return s.C("id")
+	},
+}
+
+// Keyset pages a query by the values of its keys, starting after (or,
+// for backward cursors, before) the item of the cursor.
+type Keyset struct {
+	Keys   []Key
+	Cursor *Cursor
+}
+
+func (k Keyset) backward() bool {
+	return k.Cursor != nil && k.Cursor.Backward
+}
+
+// Order sorts the query by the keys, reversed for backward cursors.
+func (k Keyset) Order() func(s *sql.Selector) {
+	return func(s *sql.Selector) {
+		for _, key := range k.Keys {
+			descending := key.Descending != k.backward()
+
+			direction, nulls := "ASC", "NULLS LAST"
+			if descending {
+				direction = "DESC"
+			}
+			if k.backward() {
+				nulls = "NULLS FIRST"
+			}
+
+			s.OrderExpr(sql.Expr(fmt.Sprintf(
+				"%s %s %s",
+				key.Expression(s),
+				direction,
+				nulls,
+			)))
+		}
+	}
+}
+
+// Where keeps the items after the cursor in the order of the keys. It
+// keeps every item when there is no cursor.
+func (k Keyset) Where() func(s *sql.Selector) {
+	return func(s *sql.Selector) {
+		if k.Cursor == nil {
+			return
+		}
+
+		if len(k.Cursor.Values) != len(k.Keys) {
+			s.AddError(fmt.Errorf(
+				"%w: the cursor does not match the sort",
+				ErrInvalidPageRequest,
+			))
+			return
+		}
+
+		// (k1 after v1) OR (k1 = v1 AND k2 after v2) OR ...
+		var alternatives, equalities []*sql.Predicate
+		for i, key := range k.Keys {
+			// the parentheses keep the expression from being quoted
+			expression := "(" + key.Expression(s) + ")"
+			value := k.Cursor.Values[i]
+
+			if after := k.after(key, expression, value); after != nil {
+				alternatives = append(
+					alternatives,
+					sql.And(append(slices.Clone(equalities), after)...),
+				)
+			}
+
+			if value == nil {
+				equalities = append(equalities, sql.IsNull(expression))
+			} else {
+				equalities = append(equalities, sql.EQ(expression, value))
+			}
+		}
+
+		if len(alternatives) == 0 {
+			s.Where(sql.False())
+			return
+		}
+
+		s.Where(sql.Or(alternatives...))
+	}
+}
+
+// after returns the condition of the values of a key that come after
+// the value of the cursor, or nil when none does.
+func (k Keyset) after(key Key, expression string, value any) *sql.Predicate {
+	// empty values are last, so they come after every value going
+	// forward and before every value going backward
+	if !k.backward() {
+		if value == nil {
+			return nil
+		}
+
+		if key.Descending {
+			return sql.Or(sql.LT(expression, value), sql.IsNull(expression))
+		}
+		return sql.Or(sql.GT(expression, value), sql.IsNull(expression))
+	}
+
+	if value == nil {
+		return sql.NotNull(expression)
+	}
+
+	if key.Descending {
+		return sql.GT(expression, value)
+	}
+	return sql.LT(expression, value)
+}
+
+// KeyColumn is the name under which `SelectKeys` selects the i-th key.
+func KeyColumn(i int) string {
+	return fmt.Sprintf("key_%d", i)
+}
+
+// SelectKeys appends the values of the keys to the selected columns, so
+// the cursors of the page can be built from its items.
+func (k Keyset) SelectKeys() func(s *sql.Selector) {
+	return func(s *sql.Selector) {
+		for i, key := range k.Keys {
+			s.AppendSelect(sql.As("("+key.Expression(s)+")", KeyColumn(i)))
+		}
+	}
+}
+
+// KeyValue converts a value scanned from a key column into a value that
+// survives the encoding of a cursor.
+func KeyValue(value any) any {
+	if bytes, ok := value.([]byte); ok {
+		return string(bytes)
+	}
+
+	return value
+}
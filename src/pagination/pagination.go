@@ -1,18 +1,28 @@
 package pagination
 
 import (
+	"cmp"
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
 	"api5back/src/model"
+	"api5back/src/processing"
 )
 
+// ErrInvalidPageRequest is returned when the page, page size or cursor
+// of a request are invalid.
+var ErrInvalidPageRequest = errors.New("invalid page request")
+
 func parsePageRequestError(err string) (int, int, error) {
-	return 0, 0, errors.New(err)
+	return 0, 0, fmt.Errorf("%w: %s", ErrInvalidPageRequest, err)
 }
 
 var (
 	DefaultPage     = 1
 	DefaultPageSize = 10
+	MaxPageSize     = 100
 )
 
 func ParsePageRequest(pageRequest model.PageRequester) (int, int, error) {
@@ -42,5 +52,263 @@ func ParsePageRequest(pageRequest model.PageRequester) (int, int, error) {
 		return parsePageRequestError("invalid page size")
 	}
 
+	if *pageSize > MaxPageSize {
+		return parsePageRequestError(
+			fmt.Sprintf("page size above the maximum of %d", MaxPageSize),
+		)
+	}
+
 	return *page, *pageSize, nil
 }
+
+// Request is a parsed page request. A nil `Cursor` falls back to offset
+// paging by `Page`.
+type Request struct {
+	Page     int
+	PageSize int
+	Cursor   *Cursor
+}
+
+func ParseRequest(pageRequest model.PageRequester) (Request, error) {
+	page, pageSize, err := ParsePageRequest(pageRequest)
+	if err != nil {
+		return Request{}, err
+	}
+
+	request := Request{
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	if pageRequest == nil {
+		return request, nil
+	}
+
+	pr := pageRequest.GetPageRequest()
+	if pr == nil || pr.Cursor == nil || *pr.Cursor == "" {
+		return request, nil
+	}
+
+	cursor, err := DecodeCursor(*pr.Cursor)
+	if err != nil {
+		return Request{}, err
+	}
+	request.Cursor = &cursor
+
+	return request, nil
+}
+
+// Offset is the number of items skipped by the request, always zero
+// for cursor requests.
+func (r Request) Offset() int {
+	if r.Cursor != nil {
+		return 0
+	}
+
+	offset, _ := processing.ParseOffsetAndTotalPages(r.Page, r.PageSize, 0)
+	return offset
+}
+
+// Limit is the number of items to fetch. One more item than the page
+// size is fetched to know whether there is another page.
+func (r Request) Limit() int {
+	return r.PageSize + 1
+}
+
+// Cursors holds the tokens of the pages around the current one.
+type Cursors struct {
+	Next *string
+	Prev *string
+}
+
+// Trim drops the extra item fetched by `Limit`, restores the order of a
+// backward page and builds the cursors from the keys of its boundaries.
+func Trim[T any](
+	items []T,
+	request Request,
+	key func(item T) []any,
+) ([]T, Cursors) {
+	hasMore := len(items) > request.PageSize
+	if hasMore {
+		items = items[:request.PageSize]
+	}
+
+	backward := request.Cursor != nil && request.Cursor.Backward
+	if backward {
+		reversed := make([]T, len(items))
+		for i, item := range items {
+			reversed[len(items)-1-i] = item
+		}
+		items = reversed
+	}
+
+	var cursors Cursors
+	if len(items) == 0 {
+		return items, cursors
+	}
+
+	hasNext, hasPrev := hasMore, request.Cursor != nil || request.Page > 1
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		next := EncodeCursor(Cursor{Values: key(items[len(items)-1])})
+		cursors.Next = &next
+	}
+	if hasPrev {
+		prev := EncodeCursor(Cursor{Values: key(items[0]), Backward: true})
+		cursors.Prev = &prev
+	}
+
+	return items, cursors
+}
+
+// NewPage builds the page of the request with its total counts.
+func NewPage[T any](
+	items []T,
+	request Request,
+	totalItems int,
+	cursors Cursors,
+) *model.Page[T] {
+	_, numMaxPages := processing.ParseOffsetAndTotalPages(
+		request.Page,
+		request.PageSize,
+		totalItems,
+	)
+
+	return &model.Page[T]{
+		Items:       items,
+		NumMaxPages: numMaxPages,
+		TotalItems:  totalItems,
+		NextCursor:  cursors.Next,
+		PrevCursor:  cursors.Prev,
+	}
+}
+
+// keyValue normalizes a value of a key, so values of items compare
+// against the values decoded from a cursor.
+func keyValue(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	default:
+		return nil, fmt.Errorf("unsupported key value of type %T", value)
+	}
+}
+
+// compareKeys compares two keys value by value. Empty values are sorted
+// last, like in `Keyset`.
+func compareKeys(a, b []any) (int, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("expected %d key values, got %d", len(a), len(b))
+	}
+
+	for i := range a {
+		x, err := keyValue(a[i])
+		if err != nil {
+			return 0, err
+		}
+		y, err := keyValue(b[i])
+		if err != nil {
+			return 0, err
+		}
+
+		var result int
+		switch {
+		case x == nil || y == nil:
+			switch {
+			case x != nil:
+				result = -1
+			case y != nil:
+				result = 1
+			}
+		default:
+			switch x := x.(type) {
+			case float64:
+				y, ok := y.(float64)
+				if !ok {
+					return 0, fmt.Errorf("key value %d is not a number", i)
+				}
+				result = cmp.Compare(x, y)
+			case string:
+				y, ok := y.(string)
+				if !ok {
+					return 0, fmt.Errorf("key value %d is not a string", i)
+				}
+				result = strings.Compare(x, y)
+			}
+		}
+
+		if result != 0 {
+			return result, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// PaginateRequest pages an in-memory slice sorted by `key`, ascending,
+// with empty values last. Every key must be unique. A cursor page starts
+// at the first item after (or, for backward cursors, ends at the last
+// item before) the key of the cursor, so cursors stay valid once their
+// item is no longer part of the slice.
+func PaginateRequest[T any](
+	items []T,
+	request Request,
+	key func(item T) []any,
+) (*model.Page[T], error) {
+	start, end := 0, len(items)
+
+	if request.Cursor == nil {
+		start = min(request.Offset(), len(items))
+		end = min(start+request.Limit(), len(items))
+	} else {
+		// the position of the first item after the cursor or, for
+		// backward cursors, of the first item not before it
+		position := len(items)
+		for i, item := range items {
+			result, err := compareKeys(key(item), request.Cursor.Values)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid cursor: %w", ErrInvalidPageRequest, err)
+			}
+
+			if result > 0 || (result == 0 && request.Cursor.Backward) {
+				position = i
+				break
+			}
+		}
+
+		if request.Cursor.Backward {
+			end = position
+			start = max(end-request.Limit(), 0)
+		} else {
+			start = position
+			end = min(start+request.Limit(), len(items))
+		}
+	}
+
+	page := items[start:end]
+	if request.Cursor != nil && request.Cursor.Backward {
+		// Trim expects the items of a backward page closest first
+		reversed := make([]T, len(page))
+		for i, item := range page {
+			reversed[len(page)-1-i] = item
+		}
+		page = reversed
+	}
+
+	page, cursors := Trim(page, request, key)
+	return NewPage(page, request, len(items), cursors), nil
+}
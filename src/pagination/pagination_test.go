@@ -1,11 +1,14 @@
 package pagination
 
 import (
+	"reflect"
 	"testing"
 
 	"api5back/src/model"
 	"api5back/src/processing"
 
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,6 +67,16 @@ func TestParsePageRequest(t *testing.T) {
 			ExpectedPage:     DefaultPage,
 			ExpectedPageSize: 20,
 		},
+		{
+			Name: "pageSize above the maximum",
+			PageRequest: &model.PageRequest{
+				Page:     nil,
+				PageSize: &[]int{MaxPageSize + 1}[0],
+			},
+			ExpectedError:    true,
+			ExpectedPage:     0,
+			ExpectedPageSize: 0,
+		},
 		{
 			Name: "negative page",
 			PageRequest: &model.PageRequest{
@@ -183,3 +196,212 @@ func TestParseOffsetAndTotalPages(t *testing.T) {
 		}
 	}
 }
+
+func TestParseRequest(t *testing.T) {
+	token := EncodeCursor(Cursor{Values: []any{"title", 7, 2.5}, Backward: true})
+
+	request, err := ParseRequest(&model.PageRequest{Cursor: &token})
+	require.NoError(t, err)
+	require.Equal(t, &Cursor{
+		Values:   []any{"title", int64(7), 2.5},
+		Backward: true,
+	}, request.Cursor)
+	require.Equal(t, 0, request.Offset())
+	require.Equal(t, DefaultPageSize+1, request.Limit())
+
+	invalid := "not a cursor"
+	_, err = ParseRequest(&model.PageRequest{Cursor: &invalid})
+	require.ErrorIs(t, err, ErrInvalidPageRequest)
+}
+
+func TestPaginateRequest(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	key := func(item int) []any { return []any{item} }
+
+	page, err := PaginateRequest(items, Request{Page: 1, PageSize: 2}, key)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, page.Items)
+	require.Equal(t, 5, page.TotalItems)
+	require.Equal(t, 3, page.NumMaxPages)
+	require.Nil(t, page.PrevCursor)
+	require.NotNil(t, page.NextCursor)
+
+	for i, testCase := range []struct {
+		Name          string
+		Cursor        string
+		ExpectedItems []int
+		ExpectedNext  bool
+		ExpectedPrev  bool
+	}{
+		{
+			Name:          "forward from the first page",
+			Cursor:        *page.NextCursor,
+			ExpectedItems: []int{3, 4},
+			ExpectedNext:  true,
+			ExpectedPrev:  true,
+		},
+		{
+			Name:          "forward to the last page",
+			Cursor:        EncodeCursor(Cursor{Values: []any{4}}),
+			ExpectedItems: []int{5},
+			ExpectedNext:  false,
+			ExpectedPrev:  true,
+		},
+		{
+			Name:          "backward to the first page",
+			Cursor:        EncodeCursor(Cursor{Values: []any{3}, Backward: true}),
+			ExpectedItems: []int{1, 2},
+			ExpectedNext:  true,
+			ExpectedPrev:  false,
+		},
+		{
+			Name:          "backward from the last page",
+			Cursor:        EncodeCursor(Cursor{Values: []any{5}, Backward: true}),
+			ExpectedItems: []int{3, 4},
+			ExpectedNext:  true,
+			ExpectedPrev:  true,
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			request, err := ParseRequest(&model.PageRequest{
+				PageSize: &[]int{2}[0],
+				Cursor:   &testCase.Cursor,
+			})
+			require.NoError(t, err)
+
+			page, err := PaginateRequest(items, request, key)
+			require.NoError(t, err)
+
+			if testResult := reflect.DeepEqual(testCase.ExpectedItems, page.Items) &&
+				testCase.ExpectedNext == (page.NextCursor != nil) &&
+				testCase.ExpectedPrev == (page.PrevCursor != nil); !testResult {
+				t.Errorf("Test case %d failed", i)
+			}
+		})
+	}
+
+	// cursors of items no longer in the slice seek to the next key
+	page, err = PaginateRequest([]int{1, 2, 4, 5}, Request{
+		PageSize: 2,
+		Cursor:   &Cursor{Values: []any{int64(3)}},
+	}, key)
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 5}, page.Items)
+
+	page, err = PaginateRequest([]int{1, 2, 4, 5}, Request{
+		PageSize: 2,
+		Cursor:   &Cursor{Values: []any{int64(3)}, Backward: true},
+	}, key)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, page.Items)
+
+	page, err = PaginateRequest(items, Request{
+		PageSize: 2,
+		Cursor:   &Cursor{Values: []any{int64(9)}},
+	}, key)
+	require.NoError(t, err)
+	require.Empty(t, page.Items)
+
+	for _, values := range [][]any{{"3"}, {int64(3), int64(1)}} {
+		_, err = PaginateRequest(items, Request{
+			PageSize: 2,
+			Cursor:   &Cursor{Values: values},
+		}, key)
+		require.ErrorIs(t, err, ErrInvalidPageRequest)
+	}
+}
+
+func TestPaginateRequestByCompositeKeys(t *testing.T) {
+	type item struct {
+		Title string
+		Id    int
+	}
+
+	// empty titles are sorted last
+	items := []item{{"a", 2}, {"b", 1}, {"b", 3}, {"", 4}}
+	key := func(item item) []any {
+		var title any
+		if item.Title != "" {
+			title = item.Title
+		}
+		return []any{title, item.Id}
+	}
+
+	var cursor *string
+	var visited []item
+	for {
+		request, err := ParseRequest(&model.PageRequest{
+			PageSize: &[]int{1}[0],
+			Cursor:   cursor,
+		})
+		require.NoError(t, err)
+
+		page, err := PaginateRequest(items, request, key)
+		require.NoError(t, err)
+
+		visited = append(visited, page.Items...)
+		if page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	require.Equal(t, items, visited)
+
+	page, err := PaginateRequest(items, Request{
+		PageSize: 2,
+		Cursor:   &Cursor{Values: []any{"b", int64(2)}},
+	}, key)
+	require.NoError(t, err)
+	require.Equal(t, []item{{"b", 3}, {"", 4}}, page.Items)
+}
+
+func TestKeyset(t *testing.T) {
+	title := Key{
+		Expression: func(s *sql.Selector) string { return s.C("title") },
+		Descending: true,
+	}
+
+	for i, testCase := range []struct {
+		Name          string
+		Cursor        *Cursor
+		ExpectedQuery string
+		ExpectedArgs  []any
+	}{
+		{
+			Name: "without cursor",
+			ExpectedQuery: `SELECT * FROM "t" ` +
+				`ORDER BY "t"."title" DESC NULLS LAST, "t"."id" ASC NULLS LAST`,
+		},
+		{
+			Name:   "forward",
+			Cursor: &Cursor{Values: []any{"b", int64(3)}},
+			ExpectedQuery: `SELECT * FROM "t" WHERE ` +
+				`("t"."title") < $1 OR ("t"."title") IS NULL OR ` +
+				`(("t"."title") = $2 AND (("t"."id") > $3 OR ("t"."id") IS NULL)) ` +
+				`ORDER BY "t"."title" DESC NULLS LAST, "t"."id" ASC NULLS LAST`,
+			ExpectedArgs: []any{"b", "b", int64(3)},
+		},
+		{
+			Name:   "backward from an empty value",
+			Cursor: &Cursor{Values: []any{nil, int64(3)}, Backward: true},
+			ExpectedQuery: `SELECT * FROM "t" WHERE ` +
+				`("t"."title") IS NOT NULL OR (("t"."title") IS NULL AND ("t"."id") < $1) ` +
+				`ORDER BY "t"."title" ASC NULLS FIRST, "t"."id" DESC NULLS FIRST`,
+			ExpectedArgs: []any{int64(3)},
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			keyset := Keyset{Keys: []Key{title, IDKey}, Cursor: testCase.Cursor}
+
+			selector := sql.Dialect(dialect.Postgres).Select("*").From(sql.Table("t"))
+			keyset.Where()(selector)
+			keyset.Order()(selector)
+			query, args := selector.Query()
+
+			if testResult := query == testCase.ExpectedQuery &&
+				reflect.DeepEqual(testCase.ExpectedArgs, args); !testResult {
+				t.Errorf("Test case %d failed: %s %v", i, query, args)
+			}
+		})
+	}
+}
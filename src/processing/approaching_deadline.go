@@ -0,0 +1,156 @@
+package processing
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+// DefaultDeadlineThreshold is the fraction of the total duration of a
+// process under which its remaining duration is considered at risk.
+var DefaultDeadlineThreshold = 0.2
+
+// DeadlineRule decides whether an in progress process is approaching
+// its deadline. Both the `ApproachingDeadline` card and the at-risk
+// process list are computed from it.
+type DeadlineRule struct {
+	Threshold            float64
+	DepartmentThresholds map[int]float64
+	ReferenceDate        time.Time
+}
+
+func NewDeadlineRule(referenceDate time.Time) DeadlineRule {
+	return DeadlineRule{
+		Threshold:     DefaultDeadlineThreshold,
+		ReferenceDate: referenceDate,
+	}
+}
+
+// ThresholdFor returns the threshold of the department of the process,
+// falling back to the rule's threshold.
+func (r DeadlineRule) ThresholdFor(process *ent.DimProcess) float64 {
+	if threshold, ok := r.DepartmentThresholds[process.DimDepartmentId]; ok {
+		return threshold
+	}
+
+	return r.Threshold
+}
+
+func (r DeadlineRule) IsApproachingDeadline(process *ent.DimProcess) bool {
+	// loaded statuses are zero based, see `property.DimProcessStatus.Scan`
+	if ProcessStatusOf(process) != property.DimProcessStatusInProgress {
+		return false
+	}
+
+	if process.InitialDate == nil || process.FinishDate == nil {
+		return false
+	}
+
+	totalDuration := process.FinishDate.Time.Sub(process.InitialDate.Time)
+	remainingDuration := process.FinishDate.Time.Sub(r.ReferenceDate)
+
+	return float64(remainingDuration) < float64(totalDuration)*r.ThresholdFor(process)
+}
+
+type AtRiskProcess struct {
+	FactId         int     `json:"factId"`
+	ProcessId      int     `json:"processId"`
+	ProcessTitle   string  `json:"processTitle"`
+	VacancyId      int     `json:"vacancyId"`
+	VacancyTitle   string  `json:"vacancyTitle"`
+	FinishDate     string  `json:"finishDate"`
+	RemainingDays  int     `json:"remainingDays"`
+	PercentElapsed float32 `json:"percentElapsed"`
+	OpenPositions  int     `json:"openPositions"`
+	Owner          string  `json:"owner"`
+}
+
+// GenerateAtRiskProcesses lists the processes and vacancies of the given
+// facts that are approaching their deadline, the closest ones first, then
+// by process, vacancy and fact.
+func GenerateAtRiskProcesses(
+	factHiringProcesses []*ent.FactHiringProcess,
+	rule DeadlineRule,
+) ([]AtRiskProcess, error) {
+	atRiskProcesses := []AtRiskProcess{}
+
+	for _, factHiringProcess := range factHiringProcesses {
+		process, err := factHiringProcess.
+			Edges.
+			DimProcessOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error getting `dim_process` with ID %d of factHiringProcess with ID %d: %+v",
+				factHiringProcess.DimProcessId,
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		if !rule.IsApproachingDeadline(process) {
+			continue
+		}
+
+		vacancy, err := factHiringProcess.
+			Edges.
+			DimVacancyOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error getting `dim_vacancy` with ID %d of factHiringProcess with ID %d: %+v",
+				factHiringProcess.DimVacancyId,
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		owner := ""
+		if user, err := factHiringProcess.Edges.DimUserOrErr(); err == nil {
+			owner = user.Name
+		}
+
+		totalDuration := process.FinishDate.Time.Sub(process.InitialDate.Time)
+		elapsedDuration := rule.ReferenceDate.Sub(process.InitialDate.Time)
+
+		percentElapsed := float32(100)
+		if totalDuration > 0 {
+			percentElapsed = float32(float64(elapsedDuration) / float64(totalDuration) * 100)
+		}
+
+		openPositions := vacancy.NumPositions - factHiringProcess.MetTotalCandidatesHired
+		if openPositions < 0 {
+			openPositions = 0
+		}
+
+		atRiskProcesses = append(atRiskProcesses, AtRiskProcess{
+			FactId:         factHiringProcess.ID,
+			ProcessId:      process.DbId,
+			ProcessTitle:   process.Title,
+			VacancyId:      vacancy.DbId,
+			VacancyTitle:   vacancy.Title,
+			FinishDate:     process.FinishDate.Time.Format("2006-01-02"),
+			RemainingDays:  int(process.FinishDate.Time.Sub(rule.ReferenceDate).Hours() / 24),
+			PercentElapsed: percentElapsed,
+			OpenPositions:  openPositions,
+			Owner:          owner,
+		})
+	}
+
+	sort.Slice(atRiskProcesses, func(i, j int) bool {
+		a, b := atRiskProcesses[i], atRiskProcesses[j]
+		if a.RemainingDays != b.RemainingDays {
+			return a.RemainingDays < b.RemainingDays
+		}
+		if a.ProcessId != b.ProcessId {
+			return a.ProcessId < b.ProcessId
+		}
+		if a.VacancyId != b.VacancyId {
+			return a.VacancyId < b.VacancyId
+		}
+		return a.FactId < b.FactId
+	})
+
+	return atRiskProcesses, nil
+}
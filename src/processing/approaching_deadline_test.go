@@ -0,0 +1,124 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProcess(
+	status property.DimProcessStatus,
+	departmentId int,
+) *ent.DimProcess {
+	return &ent.DimProcess{
+		// loaded statuses are zero based
+		Status:          status - 1,
+		DimDepartmentId: departmentId,
+		InitialDate: &pgtype.Date{
+			Time:  time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+			Valid: true,
+		},
+		FinishDate: &pgtype.Date{
+			Time:  time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC),
+			Valid: true,
+		},
+	}
+}
+
+func TestDeadlineRule(t *testing.T) {
+	for i, testCase := range []struct {
+		Name          string
+		Process       *ent.DimProcess
+		Rule          DeadlineRule
+		ExpectedValue bool
+	}{
+		{
+			Name:    "in progress with more than 20% remaining",
+			Process: newTestProcess(property.DimProcessStatusInProgress, 1),
+			Rule: NewDeadlineRule(
+				time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC),
+			),
+			ExpectedValue: false,
+		},
+		{
+			Name:    "in progress with less than 20% remaining",
+			Process: newTestProcess(property.DimProcessStatusInProgress, 1),
+			Rule: NewDeadlineRule(
+				time.Date(2024, 8, 28, 0, 0, 0, 0, time.UTC),
+			),
+			ExpectedValue: true,
+		},
+		{
+			Name:    "in progress past its deadline",
+			Process: newTestProcess(property.DimProcessStatusInProgress, 1),
+			Rule: NewDeadlineRule(
+				time.Date(2024, 9, 10, 0, 0, 0, 0, time.UTC),
+			),
+			ExpectedValue: true,
+		},
+		{
+			Name:    "open processes are never at risk",
+			Process: newTestProcess(property.DimProcessStatusOpen, 1),
+			Rule: NewDeadlineRule(
+				time.Date(2024, 8, 28, 0, 0, 0, 0, time.UTC),
+			),
+			ExpectedValue: false,
+		},
+		{
+			Name:    "department threshold overrides the default",
+			Process: newTestProcess(property.DimProcessStatusInProgress, 2),
+			Rule: DeadlineRule{
+				Threshold:            0.2,
+				DepartmentThresholds: map[int]float64{2: 0.5},
+				ReferenceDate:        time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC),
+			},
+			ExpectedValue: true,
+		},
+	} {
+		if testResult := t.Run(testCase.Name, func(t *testing.T) {
+			require.Equal(t,
+				testCase.ExpectedValue,
+				testCase.Rule.IsApproachingDeadline(testCase.Process),
+			)
+		}); !testResult {
+			t.Errorf("Test case %d failed", i)
+		}
+	}
+}
+
+func TestGenerateAtRiskProcesses(t *testing.T) {
+	// both facts point at versions of the same process and vacancy, so
+	// only the fact tells their rows apart
+	factHiringProcesses := []*ent.FactHiringProcess{}
+	for _, id := range []int{3, 4} {
+		process := newTestProcess(property.DimProcessStatusInProgress, 1)
+		process.ID, process.DbId = id, 1
+
+		factHiringProcesses = append(factHiringProcesses, &ent.FactHiringProcess{
+			ID: id,
+			Edges: ent.FactHiringProcessEdges{
+				DimProcess: process,
+				DimVacancy: &ent.DimVacancy{ID: id, DbId: 1, NumPositions: 2},
+			},
+		})
+	}
+
+	atRiskProcesses, err := GenerateAtRiskProcesses(
+		factHiringProcesses,
+		NewDeadlineRule(time.Date(2024, 8, 28, 0, 0, 0, 0, time.UTC)),
+	)
+	require.NoError(t, err)
+	require.Len(t, atRiskProcesses, 2)
+
+	for i, atRiskProcess := range atRiskProcesses {
+		require.Equal(t, factHiringProcesses[i].ID, atRiskProcess.FactId)
+		require.Equal(t, 1, atRiskProcess.ProcessId)
+		require.Equal(t, 1, atRiskProcess.VacancyId)
+		require.Equal(t, 2, atRiskProcess.OpenPositions)
+	}
+}
@@ -30,6 +30,7 @@ type Month struct {
 
 func GenerateAverageHiringTimePerMonth(
 	dimVacancies []*ent.DimVacancy,
+	days DayCounter,
 ) (AverageHiringTimePerMonth, error) {
 	monthsValues := [12]Month{}
 
@@ -47,8 +48,7 @@ func GenerateAverageHiringTimePerMonth(
 
 		for _, candidate := range candidates {
 			if candidate.Status == property.DimCandidateStatusHired {
-				interval := candidate.UpdatedAt.Time.Sub(candidate.ApplyDate.Time)
-				intervalDays := interval.Hours() / 24
+				intervalDays := days.Days(candidate.ApplyDate.Time, candidate.UpdatedAt.Time)
 				monthIndex := candidate.UpdatedAt.Time.Month() - 1
 				monthsValues[monthIndex].TotalDurationInDays += intervalDays
 				monthsValues[monthIndex].HiredCandidates++
@@ -78,55 +78,3 @@ func GenerateAverageHiringTimePerMonth(
 
 	return result, nil
 }
-
-func GenerateAverageHiringTimePerFactHiringProcess(
-	factHiringProcess *ent.FactHiringProcess,
-) (float32, error) {
-	vacancy, err := factHiringProcess.
-		Edges.
-		DimVacancyOrErr()
-	if err != nil {
-		return 0, fmt.Errorf(
-			"`DimVacancy` with ID %d of `FactHiringProcess` with ID %d not found: %w",
-			factHiringProcess.DimVacancyId,
-			factHiringProcess.ID,
-			err,
-		)
-	}
-
-	candidates, err := vacancy.
-		Edges.
-		DimCandidatesOrErr()
-	if err != nil {
-		return 0, fmt.Errorf(
-			"`DimCandidates` of `DimVacancy` with ID %d not found: %w",
-			vacancy.ID,
-			err,
-		)
-	}
-
-	hiredCandidates := 0.0
-	days := 0.0
-
-	for _, candidate := range candidates {
-		if candidate.Status == property.DimCandidateStatusHired {
-			interval := candidate.UpdatedAt.Time.Sub(candidate.ApplyDate.Time)
-			intervalDays := interval.Hours() / 24
-			hiredCandidates += 1
-			days += intervalDays
-
-		}
-	}
-
-	if hiredCandidates == 0 {
-		return 0, fmt.Errorf(
-			"no hired candidates found for `FactHiringProcess` ID %d and `DimVacancy` ID %d: %w",
-			factHiringProcess.ID,
-			vacancy.ID,
-			err,
-		)
-	}
-	result := float32(days / hiredCandidates)
-
-	return result, nil
-}
@@ -142,7 +142,7 @@ func TestAverageHiringTime(t *testing.T) {
 	}
 
 	if testResult := t.Run("Test GenerateAverageHiringTimePerMonth processing function", func(t *testing.T) {
-		months, err := GenerateAverageHiringTimePerMonth(dimVacancies)
+		months, err := GenerateAverageHiringTimePerMonth(dimVacancies, DayCounter{})
 		require.NoError(t, err)
 
 		assert.Equal(t, float32(0), months.January)
@@ -0,0 +1,223 @@
+package processing
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+// DefaultCandidateAgingStages are the stages a candidate can be stuck in.
+var DefaultCandidateAgingStages = []property.DimCandidateStatus{
+	property.DimCandidateStatusInAnalysis,
+	property.DimCandidateStatusInterview,
+}
+
+// CandidateAgingRule decides whether a candidate has been in one of the
+// given stages for at least `MinDays`, counted by `Days`, at the
+// reference date.
+type CandidateAgingRule struct {
+	Stages        []property.DimCandidateStatus
+	MinDays       int
+	ReferenceDate time.Time
+	Days          DayCounter
+}
+
+// DaysInStage counts the whole days since the last status change of the
+// candidate, which is its `updatedAt` or, when missing, its `applyDate`.
+func (r CandidateAgingRule) DaysInStage(candidate *ent.DimCandidate) int {
+	since := candidate.ApplyDate
+	if candidate.UpdatedAt != nil && candidate.UpdatedAt.Valid {
+		since = candidate.UpdatedAt
+	}
+
+	if since == nil || !since.Valid {
+		return 0
+	}
+
+	return int(r.Days.Days(since.Time, r.ReferenceDate))
+}
+
+func (r CandidateAgingRule) IsStuck(candidate *ent.DimCandidate) bool {
+	for _, stage := range r.Stages {
+		if candidate.Status == stage {
+			return r.DaysInStage(candidate) >= r.MinDays
+		}
+	}
+
+	return false
+}
+
+type AgingCandidate struct {
+	DimCandidateId int    `json:"dimCandidateId"`
+	CandidateId    int    `json:"candidateId"`
+	Name           string `json:"name"`
+	Stage          string `json:"stage"`
+	ApplyDate      string `json:"applyDate"`
+	DaysInStage    int    `json:"daysInStage"`
+	VacancyId      int    `json:"vacancyId"`
+	VacancyTitle   string `json:"vacancyTitle"`
+	RecruiterId    int    `json:"recruiterId"`
+	Recruiter      string `json:"recruiter"`
+}
+
+// GenerateAgingCandidates lists the candidates of the vacancies of the
+// given facts that are stuck in a stage, the oldest ones first.
+func GenerateAgingCandidates(
+	factHiringProcesses []*ent.FactHiringProcess,
+	rule CandidateAgingRule,
+) ([]AgingCandidate, error) {
+	agingCandidates := []AgingCandidate{}
+	visitedCandidates := make(map[int]bool)
+
+	for _, factHiringProcess := range factHiringProcesses {
+		vacancy, err := factHiringProcess.
+			Edges.
+			DimVacancyOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimVacancy` with ID %d of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.DimVacancyId,
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		candidates, err := vacancy.
+			Edges.
+			DimCandidatesOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimCandidates` of `DimVacancy` with ID %d not found: %w",
+				vacancy.ID,
+				err,
+			)
+		}
+
+		recruiterId, recruiter := 0, ""
+		if user, err := factHiringProcess.Edges.DimUserOrErr(); err == nil {
+			recruiterId, recruiter = user.DbId, user.Name
+		}
+
+		for _, candidate := range candidates {
+			if visitedCandidates[candidate.ID] || !rule.IsStuck(candidate) {
+				continue
+			}
+			visitedCandidates[candidate.ID] = true
+
+			applyDate := ""
+			if candidate.ApplyDate != nil && candidate.ApplyDate.Valid {
+				applyDate = candidate.ApplyDate.Time.Format("2006-01-02")
+			}
+
+			agingCandidates = append(agingCandidates, AgingCandidate{
+				DimCandidateId: candidate.ID,
+				CandidateId:    candidate.DbId,
+				Name:           candidate.Name,
+				Stage:          candidate.Status.String(),
+				ApplyDate:      applyDate,
+				DaysInStage:    rule.DaysInStage(candidate),
+				VacancyId:      vacancy.DbId,
+				VacancyTitle:   vacancy.Title,
+				RecruiterId:    recruiterId,
+				Recruiter:      recruiter,
+			})
+		}
+	}
+
+	sort.Slice(agingCandidates, func(i, j int) bool {
+		a, b := agingCandidates[i], agingCandidates[j]
+		if a.DaysInStage != b.DaysInStage {
+			return a.DaysInStage > b.DaysInStage
+		}
+		if a.VacancyId != b.VacancyId {
+			return a.VacancyId < b.VacancyId
+		}
+		if a.CandidateId != b.CandidateId {
+			return a.CandidateId < b.CandidateId
+		}
+		return a.DimCandidateId < b.DimCandidateId
+	})
+
+	return agingCandidates, nil
+}
+
+type CandidateAgingGroup struct {
+	Id                 int            `json:"id"`
+	Title              string         `json:"title"`
+	NumCandidates      int            `json:"numCandidates"`
+	NumCandidatesStage map[string]int `json:"numCandidatesStage"`
+	AverageDaysInStage float32        `json:"averageDaysInStage"`
+	MaxDaysInStage     int            `json:"maxDaysInStage"`
+}
+
+type CandidateAgingReport struct {
+	NumCandidates int                   `json:"numCandidates"`
+	ByVacancy     []CandidateAgingGroup `json:"byVacancy"`
+	ByRecruiter   []CandidateAgingGroup `json:"byRecruiter"`
+}
+
+func groupAgingCandidates(
+	agingCandidates []AgingCandidate,
+	groupKey func(AgingCandidate) (int, string),
+) []CandidateAgingGroup {
+	groups := []CandidateAgingGroup{}
+	groupIndexes := make(map[int]int)
+	totalDays := make(map[int]int)
+
+	for _, candidate := range agingCandidates {
+		id, title := groupKey(candidate)
+
+		index, ok := groupIndexes[id]
+		if !ok {
+			index = len(groups)
+			groupIndexes[id] = index
+			groups = append(groups, CandidateAgingGroup{
+				Id:                 id,
+				Title:              title,
+				NumCandidatesStage: make(map[string]int),
+			})
+		}
+
+		group := &groups[index]
+		group.NumCandidates++
+		group.NumCandidatesStage[candidate.Stage]++
+		if candidate.DaysInStage > group.MaxDaysInStage {
+			group.MaxDaysInStage = candidate.DaysInStage
+		}
+		totalDays[id] += candidate.DaysInStage
+	}
+
+	for i := range groups {
+		groups[i].AverageDaysInStage = float32(totalDays[groups[i].Id]) /
+			float32(groups[i].NumCandidates)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].NumCandidates > groups[j].NumCandidates
+	})
+
+	return groups
+}
+
+func GenerateCandidateAgingReport(
+	agingCandidates []AgingCandidate,
+) CandidateAgingReport {
+	return CandidateAgingReport{
+		NumCandidates: len(agingCandidates),
+		ByVacancy: groupAgingCandidates(
+			agingCandidates,
+			func(candidate AgingCandidate) (int, string) {
+				return candidate.VacancyId, candidate.VacancyTitle
+			},
+		),
+		ByRecruiter: groupAgingCandidates(
+			agingCandidates,
+			func(candidate AgingCandidate) (int, string) {
+				return candidate.RecruiterId, candidate.Recruiter
+			},
+		),
+	}
+}
@@ -0,0 +1,77 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandidateAgingRule(t *testing.T) {
+	rule := CandidateAgingRule{
+		Stages:        DefaultCandidateAgingStages,
+		MinDays:       10,
+		ReferenceDate: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	inAnalysis := &ent.DimCandidate{
+		Status: property.DimCandidateStatusInAnalysis,
+		ApplyDate: &pgtype.Date{
+			Time:  time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+			Valid: true,
+		},
+	}
+	require.Equal(t, 31, rule.DaysInStage(inAnalysis))
+	require.True(t, rule.IsStuck(inAnalysis))
+
+	recentInterview := newTestCandidate(
+		property.DimCandidateStatusInterview,
+		"2024-08-01",
+		"2024-08-25",
+	)
+	require.Equal(t, 7, rule.DaysInStage(recentInterview))
+	require.False(t, rule.IsStuck(recentInterview))
+
+	hired := newTestCandidate(
+		property.DimCandidateStatusHired,
+		"2024-06-01",
+		"2024-06-10",
+	)
+	require.False(t, rule.IsStuck(hired))
+
+	rule.Days = DayCounter{Unit: DurationUnitBusinessDays}
+	require.Equal(t, 21, rule.DaysInStage(inAnalysis))
+	require.Equal(t, 5, rule.DaysInStage(recentInterview))
+}
+
+func TestGenerateCandidateAgingReport(t *testing.T) {
+	report := GenerateCandidateAgingReport([]AgingCandidate{
+		{Stage: "Interview", DaysInStage: 30, VacancyId: 1, VacancyTitle: "A", RecruiterId: 1, Recruiter: "Alice"},
+		{Stage: "In Analysis", DaysInStage: 20, VacancyId: 2, VacancyTitle: "B", RecruiterId: 1, Recruiter: "Alice"},
+		{Stage: "In Analysis", DaysInStage: 10, VacancyId: 1, VacancyTitle: "A", RecruiterId: 2, Recruiter: "Bob"},
+	})
+
+	require.Equal(t, 3, report.NumCandidates)
+
+	require.Equal(t, []CandidateAgingGroup{
+		{
+			Id: 1, Title: "A", NumCandidates: 2,
+			NumCandidatesStage: map[string]int{"Interview": 1, "In Analysis": 1},
+			AverageDaysInStage: 20, MaxDaysInStage: 30,
+		},
+		{
+			Id: 2, Title: "B", NumCandidates: 1,
+			NumCandidatesStage: map[string]int{"In Analysis": 1},
+			AverageDaysInStage: 20, MaxDaysInStage: 20,
+		},
+	}, report.ByVacancy)
+
+	require.Len(t, report.ByRecruiter, 2)
+	require.Equal(t, "Alice", report.ByRecruiter[0].Title)
+	require.Equal(t, 2, report.ByRecruiter[0].NumCandidates)
+	require.Equal(t, float32(25), report.ByRecruiter[0].AverageDaysInStage)
+}
@@ -0,0 +1,318 @@
+package processing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+var (
+	DefaultScoreBandWidth = 10.0
+	DefaultHireRetention  = 90.0
+)
+
+// MaxScoreBands bounds the number of bands of a score distribution.
+const MaxScoreBands = 1000
+
+type ScoreBand struct {
+	Start         float64 `json:"start"`
+	End           float64 `json:"end"`
+	NumCandidates int     `json:"numCandidates"`
+	NumHired      int     `json:"numHired"`
+	HireRate      float32 `json:"hireRate"`
+}
+
+type ScoreDistribution struct {
+	Id            int         `json:"id"`
+	Title         string      `json:"title"`
+	NumCandidates int         `json:"numCandidates"`
+	Mean          float32     `json:"mean"`
+	Median        float32     `json:"median"`
+	Min           float32     `json:"min"`
+	Max           float32     `json:"max"`
+	Bands         []ScoreBand `json:"bands"`
+}
+
+type ScoreThreshold struct {
+	// percentage of the hires kept by the threshold
+	HireRetention float64 `json:"hireRetention"`
+	Score         float32 `json:"score"`
+	// share of all candidates whose score is at least the threshold
+	PassRate float32 `json:"passRate"`
+}
+
+type ScoreAnalytics struct {
+	Overall           ScoreDistribution   `json:"overall"`
+	ByVacancy         []ScoreDistribution `json:"byVacancy"`
+	ByProcess         []ScoreDistribution `json:"byProcess"`
+	MeanScoreByStatus map[string]float32  `json:"meanScoreByStatus"`
+	Threshold         *ScoreThreshold     `json:"threshold"`
+}
+
+// GenerateScoreDistribution summarizes the scores of the candidates and
+// splits them into bands of `bandWidth` with the hire rate of each one.
+// It fails when the highest score would need more than `MaxScoreBands`.
+func GenerateScoreDistribution(
+	id int,
+	title string,
+	candidates []*ent.DimCandidate,
+	bandWidth float64,
+) (ScoreDistribution, error) {
+	if bandWidth <= 0 {
+		return ScoreDistribution{}, errors.New("invalid score band width")
+	}
+
+	distribution := ScoreDistribution{
+		Id:    id,
+		Title: title,
+		Bands: []ScoreBand{},
+	}
+
+	if len(candidates) == 0 {
+		return distribution, nil
+	}
+
+	scores := make([]float64, len(candidates))
+	total := 0.0
+	for i, candidate := range candidates {
+		scores[i] = candidate.Score
+		total += candidate.Score
+	}
+	sort.Float64s(scores)
+
+	// the band count is bounded as a float first, since a tiny width
+	// overflows the int conversion
+	lastBand := math.Floor(math.Max(scores[len(scores)-1], 0) / bandWidth)
+	if lastBand >= MaxScoreBands {
+		return ScoreDistribution{}, fmt.Errorf(
+			"score band width %v splits the scores into more than %d bands",
+			bandWidth,
+			MaxScoreBands,
+		)
+	}
+
+	numBands := int(lastBand) + 1
+	bands := make([]ScoreBand, numBands)
+	for i := range bands {
+		bands[i] = ScoreBand{
+			Start: float64(i) * bandWidth,
+			End:   float64(i+1) * bandWidth,
+		}
+	}
+
+	for _, candidate := range candidates {
+		band := &bands[int(math.Floor(math.Max(candidate.Score, 0)/bandWidth))]
+		band.NumCandidates++
+		if candidate.Status == property.DimCandidateStatusHired {
+			band.NumHired++
+		}
+	}
+
+	for i := range bands {
+		if bands[i].NumCandidates > 0 {
+			bands[i].HireRate = float32(bands[i].NumHired) / float32(bands[i].NumCandidates)
+		}
+	}
+
+	distribution.NumCandidates = len(scores)
+	distribution.Mean = float32(total / float64(len(scores)))
+	distribution.Median = float32(percentile(scores, 50))
+	distribution.Min = float32(scores[0])
+	distribution.Max = float32(scores[len(scores)-1])
+	distribution.Bands = bands
+
+	return distribution, nil
+}
+
+func GenerateMeanScoreByStatus(
+	candidates []*ent.DimCandidate,
+) map[string]float32 {
+	totals := make(map[property.DimCandidateStatus]float64)
+	counts := make(map[property.DimCandidateStatus]int)
+
+	for _, candidate := range candidates {
+		totals[candidate.Status] += candidate.Score
+		counts[candidate.Status]++
+	}
+
+	meanScoreByStatus := make(map[string]float32)
+	for status, count := range counts {
+		meanScoreByStatus[status.String()] = float32(totals[status] / float64(count))
+	}
+
+	return meanScoreByStatus
+}
+
+// GenerateScoreThreshold finds the highest score that would have kept
+// at least `hireRetention` percent of the hired candidates. It returns
+// nil when there are no hired candidates.
+func GenerateScoreThreshold(
+	candidates []*ent.DimCandidate,
+	hireRetention float64,
+) (*ScoreThreshold, error) {
+	if hireRetention <= 0 || hireRetention > 100 {
+		return nil, fmt.Errorf(
+			"invalid hire retention %v, expected a percentage in (0, 100]",
+			hireRetention,
+		)
+	}
+
+	var hiredScores []float64
+	for _, candidate := range candidates {
+		if candidate.Status == property.DimCandidateStatusHired {
+			hiredScores = append(hiredScores, candidate.Score)
+		}
+	}
+
+	if len(hiredScores) == 0 {
+		return nil, nil
+	}
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(hiredScores)))
+
+	numKept := int(math.Ceil(hireRetention / 100 * float64(len(hiredScores))))
+	threshold := hiredScores[numKept-1]
+
+	numPassed := 0
+	for _, candidate := range candidates {
+		if candidate.Score >= threshold {
+			numPassed++
+		}
+	}
+
+	return &ScoreThreshold{
+		HireRetention: hireRetention,
+		Score:         float32(threshold),
+		PassRate:      float32(numPassed) / float32(len(candidates)),
+	}, nil
+}
+
+// GenerateScoreAnalytics computes the score analytics of the candidates
+// of the vacancies of the given facts, per vacancy and per process.
+func GenerateScoreAnalytics(
+	factHiringProcesses []*ent.FactHiringProcess,
+	bandWidth float64,
+	hireRetention float64,
+) (ScoreAnalytics, error) {
+	type group struct {
+		title      string
+		candidates []*ent.DimCandidate
+	}
+
+	var candidates []*ent.DimCandidate
+	var vacancyIds, processIds []int
+	vacancies := make(map[int]*group)
+	processes := make(map[int]*group)
+	visitedPairs := make(map[[2]int]bool)
+
+	for _, factHiringProcess := range factHiringProcesses {
+		process, err := factHiringProcess.
+			Edges.
+			DimProcessOrErr()
+		if err != nil {
+			return ScoreAnalytics{}, fmt.Errorf(
+				"`DimProcess` with ID %d of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.DimProcessId,
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		vacancy, err := factHiringProcess.
+			Edges.
+			DimVacancyOrErr()
+		if err != nil {
+			return ScoreAnalytics{}, fmt.Errorf(
+				"`DimVacancy` with ID %d of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.DimVacancyId,
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		pair := [2]int{process.DbId, vacancy.DbId}
+		if visitedPairs[pair] {
+			continue
+		}
+		visitedPairs[pair] = true
+
+		vacancyCandidates, err := vacancy.
+			Edges.
+			DimCandidatesOrErr()
+		if err != nil {
+			return ScoreAnalytics{}, fmt.Errorf(
+				"`DimCandidates` of `DimVacancy` with ID %d not found: %w",
+				vacancy.ID,
+				err,
+			)
+		}
+
+		if _, ok := vacancies[vacancy.DbId]; !ok {
+			vacancyIds = append(vacancyIds, vacancy.DbId)
+			vacancies[vacancy.DbId] = &group{
+				title:      vacancy.Title,
+				candidates: vacancyCandidates,
+			}
+			candidates = append(candidates, vacancyCandidates...)
+		}
+
+		if _, ok := processes[process.DbId]; !ok {
+			processIds = append(processIds, process.DbId)
+			processes[process.DbId] = &group{title: process.Title}
+		}
+		processes[process.DbId].candidates = append(
+			processes[process.DbId].candidates,
+			vacancyCandidates...,
+		)
+	}
+
+	overall, err := GenerateScoreDistribution(0, "", candidates, bandWidth)
+	if err != nil {
+		return ScoreAnalytics{}, err
+	}
+
+	byVacancy := []ScoreDistribution{}
+	for _, id := range vacancyIds {
+		distribution, err := GenerateScoreDistribution(
+			id,
+			vacancies[id].title,
+			vacancies[id].candidates,
+			bandWidth,
+		)
+		if err != nil {
+			return ScoreAnalytics{}, err
+		}
+		byVacancy = append(byVacancy, distribution)
+	}
+
+	byProcess := []ScoreDistribution{}
+	for _, id := range processIds {
+		distribution, err := GenerateScoreDistribution(
+			id,
+			processes[id].title,
+			processes[id].candidates,
+			bandWidth,
+		)
+		if err != nil {
+			return ScoreAnalytics{}, err
+		}
+		byProcess = append(byProcess, distribution)
+	}
+
+	threshold, err := GenerateScoreThreshold(candidates, hireRetention)
+	if err != nil {
+		return ScoreAnalytics{}, err
+	}
+
+	return ScoreAnalytics{
+		Overall:           overall,
+		ByVacancy:         byVacancy,
+		ByProcess:         byProcess,
+		MeanScoreByStatus: GenerateMeanScoreByStatus(candidates),
+		Threshold:         threshold,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package processing
+
+import (
+	"testing"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScoredCandidates() []*ent.DimCandidate {
+	return []*ent.DimCandidate{
+		{Score: 95, Status: property.DimCandidateStatusHired},
+		{Score: 80, Status: property.DimCandidateStatusHired},
+		{Score: 72, Status: property.DimCandidateStatusInterview},
+		{Score: 65, Status: property.DimCandidateStatusHired},
+		{Score: 40, Status: property.DimCandidateStatusRejected},
+		{Score: 15, Status: property.DimCandidateStatusRejected},
+	}
+}
+
+func TestGenerateScoreDistribution(t *testing.T) {
+	distribution, err := GenerateScoreDistribution(
+		1, "Vacancy",
+		newTestScoredCandidates(),
+		50,
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, 6, distribution.NumCandidates)
+	require.Equal(t, float32(61.166668), distribution.Mean)
+	require.Equal(t, float32(68.5), distribution.Median)
+	require.Equal(t, float32(15), distribution.Min)
+	require.Equal(t, float32(95), distribution.Max)
+	require.Equal(t, []ScoreBand{
+		{Start: 0, End: 50, NumCandidates: 2, NumHired: 0, HireRate: 0},
+		{Start: 50, End: 100, NumCandidates: 4, NumHired: 3, HireRate: 0.75},
+	}, distribution.Bands)
+
+	_, err = GenerateScoreDistribution(1, "Vacancy", nil, 0)
+	require.Error(t, err)
+
+	for _, bandWidth := range []float64{1e-7, 1e-300} {
+		_, err = GenerateScoreDistribution(1, "Vacancy", newTestScoredCandidates(), bandWidth)
+		require.Error(t, err)
+	}
+}
+
+func TestGenerateMeanScoreByStatus(t *testing.T) {
+	require.Equal(t, map[string]float32{
+		"Hired":     80,
+		"Interview": 72,
+		"Rejected":  27.5,
+	}, GenerateMeanScoreByStatus(newTestScoredCandidates()))
+}
+
+func TestGenerateScoreThreshold(t *testing.T) {
+	threshold, err := GenerateScoreThreshold(newTestScoredCandidates(), 60)
+	require.NoError(t, err)
+	require.Equal(t, &ScoreThreshold{
+		HireRetention: 60,
+		Score:         80,
+		PassRate:      float32(2) / float32(6),
+	}, threshold)
+
+	threshold, err = GenerateScoreThreshold(newTestScoredCandidates(), 100)
+	require.NoError(t, err)
+	require.Equal(t, float32(65), threshold.Score)
+
+	threshold, err = GenerateScoreThreshold(
+		[]*ent.DimCandidate{{Score: 50, Status: property.DimCandidateStatusRejected}},
+		90,
+	)
+	require.NoError(t, err)
+	require.Nil(t, threshold)
+
+	_, err = GenerateScoreThreshold(newTestScoredCandidates(), 0)
+	require.Error(t, err)
+}
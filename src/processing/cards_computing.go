@@ -2,7 +2,6 @@ package processing
 
 import (
 	"fmt"
-	"time"
 
 	"api5back/ent"
 	"api5back/src/property"
@@ -16,83 +15,38 @@ type CardInfos struct {
 	AverageHiringTime   int `json:"averageHiringTime" default:"0"`
 }
 
+// ComputingCardsInfo evaluates the card metrics of the catalog over the
+// facts, see `MetricProcessesByStatus`, `MetricApproachingDeadline` and
+// `MetricCardAverageHiringTime`.
 func ComputingCardsInfo(
 	factHiringProcesses []*ent.FactHiringProcess,
+	deadlineRule DeadlineRule,
+	days DayCounter,
 ) (CardInfos, error) {
-	if len(factHiringProcesses) == 0 {
-		return CardInfos{}, nil
-	}
-
-	countByStatus := make(map[property.DimProcessStatus]int)
-	approachingDeadline := 0
-	totalHiringTime := 0.0
-	totalCandidates := 0
-
-	for _, factHiringProcess := range factHiringProcesses {
-		process, err := factHiringProcess.
-			Edges.
-			DimProcessOrErr()
+	values := make(map[string]map[string]float64)
+	for _, metric := range []*MetricDefinition{
+		&MetricProcessesByStatus,
+		&MetricApproachingDeadline,
+		&MetricCardAverageHiringTime,
+	} {
+		value, err := metric.Aggregate(factHiringProcesses, days, deadlineRule)
 		if err != nil {
 			return CardInfos{}, fmt.Errorf(
-				"error getting `dim_process` with ID %d of factHiringProcess with ID %d: %+v",
-				factHiringProcess.DimProcessId,
-				factHiringProcess.ID,
+				"could not aggregate metric %q: %w",
+				metric.Id,
 				err,
 			)
 		}
-
-		countByStatus[property.DimProcessStatus(process.Status+1)]++
-
-		totalDuration := process.
-			FinishDate.
-			Time.
-			Sub(process.InitialDate.Time)
-
-		twentyPercentDuration := totalDuration * 20 / 100
-
-		if time.Until(process.FinishDate.Time) < twentyPercentDuration && process.Status == 1 {
-			approachingDeadline++
-		}
-
-		vacancy, err := factHiringProcess.
-			Edges.
-			DimVacancyOrErr()
-		if err != nil {
-			return CardInfos{}, fmt.Errorf(
-				"error getting `dim_vacancy` with ID %d of factHiringProcess with ID %d: %+v",
-				factHiringProcess.DimVacancyId,
-				factHiringProcess.ID,
-				err,
-			)
-		}
-
-		candidates, err := vacancy.
-			Edges.
-			DimCandidatesOrErr()
-		if err != nil {
-			return CardInfos{}, fmt.Errorf(
-				"error getting `dim_candidates` of `dim_vacancy` with ID %d of factHiringProcess with ID %d: %+v",
-				factHiringProcess.DimVacancyId,
-				factHiringProcess.ID,
-				err,
-			)
-		}
-
-		for _, candidate := range candidates {
-			if candidate.Status == property.DimCandidateStatusHired {
-				interval := candidate.UpdatedAt.Time.Sub(candidate.ApplyDate.Time)
-				intervalDays := interval.Hours() / 24
-				totalHiringTime += intervalDays
-				totalCandidates++
-			}
-		}
+		values[metric.Id] = value
 	}
 
+	processesByStatus := values[MetricProcessesByStatus.Id]
+
 	return CardInfos{
-		Open:                countByStatus[property.DimProcessStatusOpen],
-		InProgress:          countByStatus[property.DimProcessStatusInProgress],
-		Closed:              countByStatus[property.DimProcessStatusClosed],
-		ApproachingDeadline: approachingDeadline,
-		AverageHiringTime:   int(totalHiringTime / float64(totalCandidates)),
+		Open:                int(processesByStatus[property.DimProcessStatusOpen.String()]),
+		InProgress:          int(processesByStatus[property.DimProcessStatusInProgress.String()]),
+		Closed:              int(processesByStatus[property.DimProcessStatusClosed.String()]),
+		ApproachingDeadline: int(values[MetricApproachingDeadline.Id][MetricTotal]),
+		AverageHiringTime:   int(values[MetricCardAverageHiringTime.Id][MetricTotal]),
 	}, nil
 }
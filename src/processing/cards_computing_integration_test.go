@@ -104,7 +104,11 @@ func TestComputingCardInfo(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, factHiringProcesses)
 
-		cardInfos, err := ComputingCardsInfo(factHiringProcesses)
+		cardInfos, err := ComputingCardsInfo(
+			factHiringProcesses,
+			NewDeadlineRule(time.Now()),
+			DayCounter{},
+		)
 		require.NoError(t, err)
 
 		assert.Equal(t, 9, cardInfos.Open)
@@ -119,7 +123,11 @@ func TestComputingCardInfo(t *testing.T) {
 
 func TestComputingCardInfo_EmptyData(t *testing.T) {
 	// Chama a função com uma lista vazia
-	cardInfos, err := ComputingCardsInfo([]*ent.FactHiringProcess{})
+	cardInfos, err := ComputingCardsInfo(
+		[]*ent.FactHiringProcess{},
+		NewDeadlineRule(time.Now()),
+		DayCounter{},
+	)
 
 	// Verifica se não houve erro
 	assert.NoError(t, err)
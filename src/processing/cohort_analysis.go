@@ -0,0 +1,281 @@
+package processing
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+type CohortGranularity string
+
+const (
+	CohortGranularityMonth CohortGranularity = "month"
+	CohortGranularityWeek  CohortGranularity = "week"
+)
+
+// DefaultCohortHorizons are the number of days after applying at which
+// the share of each cohort that reached an outcome is measured.
+var DefaultCohortHorizons = []int{7, 14, 30, 60}
+
+type CohortRow struct {
+	Cohort        string `json:"cohort"`
+	NumCandidates int    `json:"numCandidates"`
+	// shares of the cohort, one per horizon, from 0 to 1
+	Interview []float32 `json:"interview"`
+	Hired     []float32 `json:"hired"`
+	Rejected  []float32 `json:"rejected"`
+}
+
+type CohortMatrix struct {
+	Granularity CohortGranularity `json:"granularity"`
+	Horizons    []int             `json:"horizons"`
+	Cohorts     []CohortRow       `json:"cohorts"`
+}
+
+// UniqueFactCandidates returns the candidates of the vacancies of the
+// given facts, without repeating vacancies shared by many facts.
+func UniqueFactCandidates(
+	factHiringProcesses []*ent.FactHiringProcess,
+) ([]*ent.DimCandidate, error) {
+	var candidates []*ent.DimCandidate
+	visitedVacancies := make(map[int]bool)
+
+	for _, factHiringProcess := range factHiringProcesses {
+		vacancy, err := factHiringProcess.
+			Edges.
+			DimVacancyOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimVacancy` with ID %d of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.DimVacancyId,
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		if visitedVacancies[vacancy.ID] {
+			continue
+		}
+		visitedVacancies[vacancy.ID] = true
+
+		vacancyCandidates, err := vacancy.
+			Edges.
+			DimCandidatesOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimCandidates` of `DimVacancy` with ID %d not found: %w",
+				vacancy.ID,
+				err,
+			)
+		}
+
+		candidates = append(candidates, vacancyCandidates...)
+	}
+
+	return candidates, nil
+}
+
+func cohortKey(
+	candidate *ent.DimCandidate,
+	granularity CohortGranularity,
+) (string, error) {
+	return periodKey(candidate.ApplyDate.Time, granularity)
+}
+
+// periodKey names the month, as `2006-01`, or the ISO week, as
+// `2006-W01`, of the date. Keys of the same granularity sort in time
+// order.
+func periodKey(
+	date time.Time,
+	granularity CohortGranularity,
+) (string, error) {
+	switch granularity {
+	case CohortGranularityMonth:
+		return date.Format("2006-01"), nil
+	case CohortGranularityWeek:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week), nil
+	default:
+		return "", fmt.Errorf("invalid cohort granularity: %q", granularity)
+	}
+}
+
+func ValidateCohortGranularity(granularity CohortGranularity) error {
+	switch granularity {
+	case CohortGranularityMonth, CohortGranularityWeek:
+		return nil
+	default:
+		return fmt.Errorf(
+			"invalid cohort granularity: %q, expected %q or %q",
+			granularity,
+			CohortGranularityMonth,
+			CohortGranularityWeek,
+		)
+	}
+}
+
+// reachedStatuses returns the outcomes reached by the candidate. Only
+// the current status of a candidate is stored, so hired candidates are
+// also considered to have reached the interview stage.
+func reachedStatuses(
+	candidate *ent.DimCandidate,
+) []property.DimCandidateStatus {
+	switch candidate.Status {
+	case property.DimCandidateStatusInterview:
+		return []property.DimCandidateStatus{
+			property.DimCandidateStatusInterview,
+		}
+	case property.DimCandidateStatusHired:
+		return []property.DimCandidateStatus{
+			property.DimCandidateStatusInterview,
+			property.DimCandidateStatusHired,
+		}
+	case property.DimCandidateStatusRejected:
+		return []property.DimCandidateStatus{
+			property.DimCandidateStatusRejected,
+		}
+	default:
+		return nil
+	}
+}
+
+type cohortCandidateKey struct {
+	dbId        int
+	vacancyDbId int
+}
+
+// firstStatusChanges returns, for each candidate, the date each status
+// was first changed to.
+func firstStatusChanges(
+	changes []*ent.FactCandidateStatusChange,
+) (map[cohortCandidateKey]map[property.DimCandidateStatus]time.Time, error) {
+	firstChanges := make(map[cohortCandidateKey]map[property.DimCandidateStatus]time.Time)
+
+	for _, change := range changes {
+		candidate, err := change.Edges.DimCandidateOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimCandidate` of `FactCandidateStatusChange` with ID %d not found: %w",
+				change.ID,
+				err,
+			)
+		}
+
+		key := cohortCandidateKey{candidate.DbId, candidate.DimVacancyDbId}
+		if firstChanges[key] == nil {
+			firstChanges[key] = make(map[property.DimCandidateStatus]time.Time)
+		}
+
+		if changedAt, ok := firstChanges[key][change.ToStatus]; !ok || change.ChangedAt.Before(changedAt) {
+			firstChanges[key][change.ToStatus] = change.ChangedAt
+		}
+	}
+
+	return firstChanges, nil
+}
+
+// GenerateCohortMatrix groups the candidates by application period and
+// computes, for each horizon, the share of the cohort that reached each
+// outcome within that many days of applying, counted by `days`. An
+// outcome is dated by the first status change to it. Without one, the
+// current status is dated by the `updatedAt` of the candidate, and so is
+// the interview of a hired candidate, as the latest it could have been.
+func GenerateCohortMatrix(
+	candidates []*ent.DimCandidate,
+	changes []*ent.FactCandidateStatusChange,
+	granularity CohortGranularity,
+	horizons []int,
+	days DayCounter,
+) (CohortMatrix, error) {
+	type cohortCounts struct {
+		numCandidates int
+		reached       map[property.DimCandidateStatus][]int
+	}
+
+	if err := ValidateCohortGranularity(granularity); err != nil {
+		return CohortMatrix{}, err
+	}
+
+	firstChanges, err := firstStatusChanges(changes)
+	if err != nil {
+		return CohortMatrix{}, err
+	}
+
+	counts := make(map[string]*cohortCounts)
+
+	for _, candidate := range candidates {
+		if candidate.ApplyDate == nil || !candidate.ApplyDate.Valid {
+			continue
+		}
+
+		key, err := cohortKey(candidate, granularity)
+		if err != nil {
+			return CohortMatrix{}, err
+		}
+
+		cohort, ok := counts[key]
+		if !ok {
+			cohort = &cohortCounts{
+				reached: make(map[property.DimCandidateStatus][]int),
+			}
+			counts[key] = cohort
+		}
+		cohort.numCandidates++
+
+		candidateChanges := firstChanges[cohortCandidateKey{candidate.DbId, candidate.DimVacancyDbId}]
+
+		for _, status := range reachedStatuses(candidate) {
+			reachedAt, ok := candidateChanges[status]
+			if !ok {
+				if candidate.UpdatedAt == nil || !candidate.UpdatedAt.Valid {
+					continue
+				}
+				reachedAt = candidate.UpdatedAt.Time
+			}
+
+			elapsed := days.Days(candidate.ApplyDate.Time, reachedAt)
+
+			if cohort.reached[status] == nil {
+				cohort.reached[status] = make([]int, len(horizons))
+			}
+
+			for i, horizon := range horizons {
+				if elapsed <= float64(horizon) {
+					cohort.reached[status][i]++
+				}
+			}
+		}
+	}
+
+	shares := func(cohort *cohortCounts, status property.DimCandidateStatus) []float32 {
+		result := make([]float32, len(horizons))
+		for i, reached := range cohort.reached[status] {
+			result[i] = float32(reached) / float32(cohort.numCandidates)
+		}
+		return result
+	}
+
+	rows := []CohortRow{}
+	for key, cohort := range counts {
+		rows = append(rows, CohortRow{
+			Cohort:        key,
+			NumCandidates: cohort.numCandidates,
+			Interview:     shares(cohort, property.DimCandidateStatusInterview),
+			Hired:         shares(cohort, property.DimCandidateStatusHired),
+			Rejected:      shares(cohort, property.DimCandidateStatusRejected),
+		})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Cohort < rows[j].Cohort
+	})
+
+	return CohortMatrix{
+		Granularity: granularity,
+		Horizons:    horizons,
+		Cohorts:     rows,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCohortMatrix(t *testing.T) {
+	candidates := []*ent.DimCandidate{
+		newTestCandidate(property.DimCandidateStatusHired, "2024-07-01", "2024-07-20"),
+		newTestCandidate(property.DimCandidateStatusInterview, "2024-07-10", "2024-07-15"),
+		newTestCandidate(property.DimCandidateStatusRejected, "2024-07-20", "2024-09-10"),
+		{
+			Status:    property.DimCandidateStatusInAnalysis,
+			ApplyDate: newTestCandidate(0, "2024-07-25", "2024-07-25").ApplyDate,
+		},
+		newTestCandidate(property.DimCandidateStatusHired, "2024-08-05", "2024-08-10"),
+	}
+
+	cohortMatrix, err := GenerateCohortMatrix(
+		candidates,
+		nil,
+		CohortGranularityMonth,
+		DefaultCohortHorizons,
+		DayCounter{},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, CohortMatrix{
+		Granularity: CohortGranularityMonth,
+		Horizons:    DefaultCohortHorizons,
+		Cohorts: []CohortRow{
+			{
+				Cohort:        "2024-07",
+				NumCandidates: 4,
+				Interview:     []float32{0.25, 0.25, 0.5, 0.5},
+				Hired:         []float32{0, 0, 0.25, 0.25},
+				Rejected:      []float32{0, 0, 0, 0.25},
+			},
+			{
+				Cohort:        "2024-08",
+				NumCandidates: 1,
+				Interview:     []float32{1, 1, 1, 1},
+				Hired:         []float32{1, 1, 1, 1},
+				Rejected:      []float32{0, 0, 0, 0},
+			},
+		},
+	}, cohortMatrix)
+
+	cohortMatrix, err = GenerateCohortMatrix(
+		candidates,
+		nil,
+		CohortGranularityWeek,
+		DefaultCohortHorizons,
+		DayCounter{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "2024-W27", cohortMatrix.Cohorts[0].Cohort)
+
+	// the hire of the 1st of July takes 19 calendar but 14 business days
+	cohortMatrix, err = GenerateCohortMatrix(
+		candidates,
+		nil,
+		CohortGranularityMonth,
+		DefaultCohortHorizons,
+		DayCounter{Unit: DurationUnitBusinessDays},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []float32{0, 0.25, 0.25, 0.25}, cohortMatrix.Cohorts[0].Hired)
+
+	_, err = GenerateCohortMatrix(candidates, nil, "year", DefaultCohortHorizons, DayCounter{})
+	require.Error(t, err)
+
+	_, err = GenerateCohortMatrix(nil, nil, "year", DefaultCohortHorizons, DayCounter{})
+	require.Error(t, err)
+}
+
+func TestCohortMatrixDatesStatusChanges(t *testing.T) {
+	// hired on the 20th of July after being interviewed on the 5th
+	candidate := newTestCandidate(property.DimCandidateStatusHired, "2024-07-01", "2024-07-20")
+	candidate.ID, candidate.DbId, candidate.DimVacancyDbId = 1, 1, 1
+
+	changes := []*ent.FactCandidateStatusChange{}
+	for _, change := range []struct {
+		Status    property.DimCandidateStatus
+		ChangedAt string
+	}{
+		{property.DimCandidateStatusInterview, "2024-07-09"},
+		{property.DimCandidateStatusInterview, "2024-07-05"},
+		{property.DimCandidateStatusHired, "2024-07-20"},
+	} {
+		changedAt, _ := time.Parse(time.DateOnly, change.ChangedAt)
+		changes = append(changes, &ent.FactCandidateStatusChange{
+			ToStatus:  change.Status,
+			ChangedAt: changedAt,
+			Edges:     ent.FactCandidateStatusChangeEdges{DimCandidate: candidate},
+		})
+	}
+
+	cohortMatrix, err := GenerateCohortMatrix(
+		[]*ent.DimCandidate{candidate},
+		changes,
+		CohortGranularityMonth,
+		DefaultCohortHorizons,
+		DayCounter{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []float32{1, 1, 1, 1}, cohortMatrix.Cohorts[0].Interview)
+	require.Equal(t, []float32{0, 0, 1, 1}, cohortMatrix.Cohorts[0].Hired)
+
+	// without status changes the interview is dated by the hire
+	cohortMatrix, err = GenerateCohortMatrix(
+		[]*ent.DimCandidate{candidate},
+		nil,
+		CohortGranularityMonth,
+		DefaultCohortHorizons,
+		DayCounter{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []float32{0, 0, 1, 1}, cohortMatrix.Cohorts[0].Interview)
+}
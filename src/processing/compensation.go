@@ -0,0 +1,191 @@
+package processing
+
+import (
+	"fmt"
+	"sort"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+type OfferAcceptance struct {
+	NumOffers   int `json:"numOffers"`
+	NumAccepted int `json:"numAccepted"`
+	NumDeclined int `json:"numDeclined"`
+	NumPending  int `json:"numPending"`
+	// accepted over answered offers, nil when no offer was answered
+	AcceptanceRate *float64 `json:"acceptanceRate"`
+}
+
+// OfferToAcceptanceTime summarizes the days from sending each accepted
+// offer to its acceptance.
+type OfferToAcceptanceTime struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+}
+
+type InitialSalaryGroup struct {
+	Name     string `json:"name"`
+	NumHired int    `json:"numHired"`
+	// nil when nobody was hired
+	AverageSalaryInitial *float64 `json:"averageSalaryInitial"`
+}
+
+type CompensationWidgets struct {
+	OfferAcceptance                  OfferAcceptance       `json:"offerAcceptance"`
+	OfferToAcceptanceTime            OfferToAcceptanceTime `json:"offerToAcceptanceTime"`
+	AverageSalaryInitialByDepartment []InitialSalaryGroup  `json:"averageSalaryInitialByDepartment"`
+	AverageSalaryInitialByLocation   []InitialSalaryGroup  `json:"averageSalaryInitialByLocation"`
+}
+
+// GenerateOfferAcceptance counts the offers by status. The acceptance
+// rate leaves out the offers still waiting for an answer.
+func GenerateOfferAcceptance(offers []*ent.DimOffer) OfferAcceptance {
+	acceptance := OfferAcceptance{
+		NumOffers: len(offers),
+	}
+
+	for _, offer := range offers {
+		switch offer.Status {
+		case property.DimOfferStatusAccepted:
+			acceptance.NumAccepted++
+		case property.DimOfferStatusDeclined:
+			acceptance.NumDeclined++
+		default:
+			acceptance.NumPending++
+		}
+	}
+
+	if answered := acceptance.NumAccepted + acceptance.NumDeclined; answered > 0 {
+		rate := float64(acceptance.NumAccepted) / float64(answered)
+		acceptance.AcceptanceRate = &rate
+	}
+
+	return acceptance
+}
+
+// GenerateOfferToAcceptanceTime summarizes the days from `sentDate` to
+// `acceptedDate` of the accepted offers, counted by `days`.
+func GenerateOfferToAcceptanceTime(
+	offers []*ent.DimOffer,
+	days DayCounter,
+) OfferToAcceptanceTime {
+	var durations []float64
+	for _, offer := range offers {
+		if offer.Status != property.DimOfferStatusAccepted ||
+			offer.SentDate == nil || offer.AcceptedDate == nil {
+			continue
+		}
+
+		durations = append(durations, days.Days(
+			offer.SentDate.Time,
+			offer.AcceptedDate.Time,
+		))
+	}
+
+	if len(durations) == 0 {
+		return OfferToAcceptanceTime{}
+	}
+
+	sort.Float64s(durations)
+
+	total := 0.0
+	for _, duration := range durations {
+		total += duration
+	}
+
+	return OfferToAcceptanceTime{
+		Count:  len(durations),
+		Mean:   total / float64(len(durations)),
+		Median: percentile(durations, 50),
+	}
+}
+
+type initialSalaryTotals struct {
+	numHired         int
+	sumSalaryInitial int
+}
+
+// GenerateAverageSalaryInitial divides the `metSumSalaryInitial` of the
+// facts by their `metTotalCandidatesHired`, by the department of their
+// process and by the location of their vacancy, each sorted by name.
+func GenerateAverageSalaryInitial(
+	factHiringProcesses []*ent.FactHiringProcess,
+) (byDepartment, byLocation []InitialSalaryGroup, err error) {
+	departments := make(map[string]*initialSalaryTotals)
+	locations := make(map[string]*initialSalaryTotals)
+
+	for _, factHiringProcess := range factHiringProcesses {
+		process, err := factHiringProcess.Edges.DimProcessOrErr()
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"`DimProcess` of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		department, err := process.Edges.DimDepartmentOrErr()
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"`DimDepartment` of `DimProcess` with ID %d not found: %w",
+				process.ID,
+				err,
+			)
+		}
+
+		vacancy, err := factHiringProcess.Edges.DimVacancyOrErr()
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"`DimVacancy` of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		addSalaryInitial(departments, department.Name, factHiringProcess)
+		addSalaryInitial(locations, vacancy.Location, factHiringProcess)
+	}
+
+	return initialSalaryGroups(departments), initialSalaryGroups(locations), nil
+}
+
+func addSalaryInitial(
+	totalsByName map[string]*initialSalaryTotals,
+	name string,
+	factHiringProcess *ent.FactHiringProcess,
+) {
+	totals, ok := totalsByName[name]
+	if !ok {
+		totals = &initialSalaryTotals{}
+		totalsByName[name] = totals
+	}
+
+	totals.numHired += factHiringProcess.MetTotalCandidatesHired
+	totals.sumSalaryInitial += factHiringProcess.MetSumSalaryInitial
+}
+
+func initialSalaryGroups(
+	totalsByName map[string]*initialSalaryTotals,
+) []InitialSalaryGroup {
+	groups := make([]InitialSalaryGroup, 0, len(totalsByName))
+	for name, totals := range totalsByName {
+		group := InitialSalaryGroup{
+			Name:     name,
+			NumHired: totals.numHired,
+		}
+		if totals.numHired > 0 {
+			average := float64(totals.sumSalaryInitial) / float64(totals.numHired)
+			group.AverageSalaryInitial = &average
+		}
+
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups
+}
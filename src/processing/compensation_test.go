@@ -0,0 +1,95 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/calendar"
+	"api5back/src/property"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func offerDate(day int) *pgtype.Date {
+	return &pgtype.Date{Time: time.Date(2024, 7, day, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func TestGenerateOfferWidgets(t *testing.T) {
+	offers := []*ent.DimOffer{
+		{Status: property.DimOfferStatusAccepted, SentDate: offerDate(1), AcceptedDate: offerDate(3)},
+		{Status: property.DimOfferStatusAccepted, SentDate: offerDate(1), AcceptedDate: offerDate(5)},
+		{Status: property.DimOfferStatusAccepted, SentDate: offerDate(10), AcceptedDate: offerDate(22)},
+		{Status: property.DimOfferStatusDeclined, SentDate: offerDate(1), DeclinedDate: offerDate(2)},
+		{Status: property.DimOfferStatusSent, SentDate: offerDate(20)},
+	}
+
+	acceptance := GenerateOfferAcceptance(offers)
+	require.Equal(t, 5, acceptance.NumOffers)
+	require.Equal(t, 3, acceptance.NumAccepted)
+	require.Equal(t, 1, acceptance.NumDeclined)
+	require.Equal(t, 1, acceptance.NumPending)
+	require.NotNil(t, acceptance.AcceptanceRate)
+	require.Equal(t, 0.75, *acceptance.AcceptanceRate)
+
+	require.Nil(t, GenerateOfferAcceptance(offers[4:]).AcceptanceRate)
+
+	require.Equal(t, OfferToAcceptanceTime{
+		Count:  3,
+		Mean:   6,
+		Median: 4,
+	}, GenerateOfferToAcceptanceTime(offers, DayCounter{}))
+	require.Equal(t, OfferToAcceptanceTime{}, GenerateOfferToAcceptanceTime(offers[3:], DayCounter{}))
+
+	businessDays := GenerateOfferToAcceptanceTime(offers, DayCounter{
+		Unit:     DurationUnitBusinessDays,
+		Calendar: calendar.New(calendar.Options{}),
+	})
+	require.Equal(t, 3, businessDays.Count)
+	require.Equal(t, 4.0, businessDays.Median)
+	require.InDelta(t, 14.0/3, businessDays.Mean, 1e-9)
+}
+
+func TestGenerateAverageSalaryInitial(t *testing.T) {
+	engineering := &ent.DimProcess{
+		ID:    1,
+		Edges: ent.DimProcessEdges{DimDepartment: &ent.DimDepartment{Name: "Engineering"}},
+	}
+	sales := &ent.DimProcess{
+		ID:    2,
+		Edges: ent.DimProcessEdges{DimDepartment: &ent.DimDepartment{Name: "Sales"}},
+	}
+	newFact := func(process *ent.DimProcess, location string, hired, salary int) *ent.FactHiringProcess {
+		return &ent.FactHiringProcess{
+			MetTotalCandidatesHired: hired,
+			MetSumSalaryInitial:     salary,
+			Edges: ent.FactHiringProcessEdges{
+				DimProcess: process,
+				DimVacancy: &ent.DimVacancy{Location: location},
+			},
+		}
+	}
+
+	byDepartment, byLocation, err := GenerateAverageSalaryInitial([]*ent.FactHiringProcess{
+		newFact(sales, "São Paulo", 2, 8000),
+		newFact(engineering, "Curitiba", 1, 7000),
+		newFact(engineering, "São Paulo", 3, 15000),
+		newFact(sales, "Sergipe", 0, 0),
+	})
+	require.NoError(t, err)
+
+	average := func(value float64) *float64 { return &value }
+	require.Equal(t, []InitialSalaryGroup{
+		{Name: "Engineering", NumHired: 4, AverageSalaryInitial: average(5500)},
+		{Name: "Sales", NumHired: 2, AverageSalaryInitial: average(4000)},
+	}, byDepartment)
+	require.Equal(t, []InitialSalaryGroup{
+		{Name: "Curitiba", NumHired: 1, AverageSalaryInitial: average(7000)},
+		{Name: "Sergipe", NumHired: 0},
+		{Name: "São Paulo", NumHired: 5, AverageSalaryInitial: average(4600)},
+	}, byLocation)
+
+	_, _, err = GenerateAverageSalaryInitial([]*ent.FactHiringProcess{{ID: 1}})
+	require.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package processing
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DateField selects which dates of a hiring process a `DateRange`
+// filters on.
+type DateField string
+
+const (
+	// the vacancy is open during the range, from `openingDate` to
+	// `closingDate`; the default
+	DateFieldVacancyPeriod DateField = "vacancyPeriod"
+	// the process runs during the range, from `initialDate` to
+	// `finishDate`
+	DateFieldProcessPeriod DateField = "processPeriod"
+	// the process started during the range, by its `initialDate`
+	DateFieldProcessStart DateField = "processStart"
+	// a candidate of the vacancy applied during the range, by its
+	// `applyDate`
+	DateFieldCandidateApply DateField = "candidateApply"
+	// a candidate of the vacancy was hired during the range, by the
+	// `updatedAt` of the hired candidate
+	DateFieldCandidateHire DateField = "candidateHire"
+	// the fact was loaded during the range, by its `DimDatetime`
+	DateFieldLoadDate DateField = "loadDate"
+)
+
+var dateFields = []DateField{
+	DateFieldVacancyPeriod,
+	DateFieldProcessPeriod,
+	DateFieldProcessStart,
+	DateFieldCandidateApply,
+	DateFieldCandidateHire,
+	DateFieldLoadDate,
+}
+
+// IsPeriod reports whether the field spans an interval of two dates
+// rather than a single date.
+func (field DateField) IsPeriod() bool {
+	return field == DateFieldVacancyPeriod || field == DateFieldProcessPeriod
+}
+
+// DateMatch selects how an interval field is matched against the range.
+// Single date fields always match when the date is within the range.
+type DateMatch string
+
+const (
+	// the interval shares at least one day with the range; the default
+	DateMatchOverlap DateMatch = "overlap"
+	// the interval lies entirely within the range
+	DateMatchContains DateMatch = "contains"
+)
+
+// DateBounds is a validated date range. Either bound is nil when the
+// range is open on that side.
+type DateBounds struct {
+	Field DateField
+	Match DateMatch
+	Start *pgtype.Date
+	End   *pgtype.Date
+}
+
+// ParseDateBounds validates the field, the match and both dates of a
+// range, given as `2006-01-02`, and rejects ranges that end before they
+// start. Empty values fall back to the defaults or an open bound.
+func ParseDateBounds(
+	field DateField,
+	match DateMatch,
+	startDate, endDate string,
+) (DateBounds, error) {
+	bounds := DateBounds{
+		Field: field,
+		Match: match,
+	}
+
+	if bounds.Field == "" {
+		bounds.Field = DateFieldVacancyPeriod
+	}
+
+	if !isDateField(bounds.Field) {
+		return DateBounds{}, fmt.Errorf(
+			"invalid date field: %q, expected one of %q",
+			field,
+			dateFields,
+		)
+	}
+
+	switch bounds.Match {
+	case "":
+		bounds.Match = DateMatchOverlap
+	case DateMatchOverlap, DateMatchContains:
+	default:
+		return DateBounds{}, fmt.Errorf(
+			"invalid date match: %q, expected %q or %q",
+			match,
+			DateMatchOverlap,
+			DateMatchContains,
+		)
+	}
+
+	if startDate != "" {
+		start, err := ParseStringToPgtypeDate("2006-01-02", startDate)
+		if err != nil {
+			return DateBounds{}, fmt.Errorf("could not parse `StartDate`: %w", err)
+		}
+		bounds.Start = &start
+	}
+
+	if endDate != "" {
+		end, err := ParseStringToPgtypeDate("2006-01-02", endDate)
+		if err != nil {
+			return DateBounds{}, fmt.Errorf("could not parse `EndDate`: %w", err)
+		}
+		bounds.End = &end
+	}
+
+	if bounds.Start != nil && bounds.End != nil &&
+		bounds.End.Time.Before(bounds.Start.Time) {
+		return DateBounds{}, fmt.Errorf(
+			"end date %s is before start date %s",
+			endDate,
+			startDate,
+		)
+	}
+
+	return bounds, nil
+}
+
+func isDateField(field DateField) bool {
+	for _, dateField := range dateFields {
+		if field == dateField {
+			return true
+		}
+	}
+
+	return false
+}
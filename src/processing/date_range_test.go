@@ -0,0 +1,88 @@
+package processing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateBounds(t *testing.T) {
+	for i, testCase := range []struct {
+		Name          string
+		Field         DateField
+		Match         DateMatch
+		Start         string
+		End           string
+		ExpectedError bool
+		ExpectedField DateField
+		ExpectedMatch DateMatch
+	}{
+		{
+			Name:  "defaults to overlapping vacancy periods",
+			Start: "2024-07-01", End: "2024-07-31",
+			ExpectedField: DateFieldVacancyPeriod,
+			ExpectedMatch: DateMatchOverlap,
+		},
+		{
+			Name:          "open range with a selected field",
+			Field:         DateFieldCandidateHire,
+			Start:         "2024-07-01",
+			ExpectedField: DateFieldCandidateHire,
+			ExpectedMatch: DateMatchOverlap,
+		},
+		{
+			Name:  "single day range",
+			Field: DateFieldProcessPeriod,
+			Match: DateMatchContains,
+			Start: "2024-07-01", End: "2024-07-01",
+			ExpectedField: DateFieldProcessPeriod,
+			ExpectedMatch: DateMatchContains,
+		},
+		{
+			Name:  "inverted range",
+			Start: "2024-07-31", End: "2024-07-01",
+			ExpectedError: true,
+		},
+		{
+			Name:          "invalid start date",
+			Start:         "2024-02-30",
+			ExpectedError: true,
+		},
+		{
+			Name:          "invalid end date",
+			End:           "31/07/2024",
+			ExpectedError: true,
+		},
+		{
+			Name:          "unknown field",
+			Field:         "closingDate",
+			ExpectedError: true,
+		},
+		{
+			Name:          "unknown match",
+			Match:         "within",
+			ExpectedError: true,
+		},
+	} {
+		if testResult := t.Run(testCase.Name, func(t *testing.T) {
+			bounds, err := ParseDateBounds(
+				testCase.Field,
+				testCase.Match,
+				testCase.Start,
+				testCase.End,
+			)
+			if testCase.ExpectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.ExpectedField, bounds.Field)
+			require.Equal(t, testCase.ExpectedMatch, bounds.Match)
+			require.Equal(t, testCase.Start != "", bounds.Start != nil)
+			require.Equal(t, testCase.End != "", bounds.End != nil)
+		}); !testResult {
+			t.Errorf("Test case %d failed", i)
+		}
+	}
+}
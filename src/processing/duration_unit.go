@@ -0,0 +1,59 @@
+package processing
+
+import (
+	"fmt"
+	"time"
+
+	"api5back/src/calendar"
+)
+
+// DurationUnit selects how the days of a duration are counted.
+type DurationUnit string
+
+const (
+	// every date counts; the default
+	DurationUnitCalendarDays DurationUnit = "calendarDays"
+	// weekends and holidays are left out, as the SLA is defined
+	DurationUnitBusinessDays DurationUnit = "businessDays"
+)
+
+// ParseDurationUnit validates a duration unit, falling back to calendar
+// days when it is empty.
+func ParseDurationUnit(unit DurationUnit) (DurationUnit, error) {
+	switch unit {
+	case "":
+		return DurationUnitCalendarDays, nil
+	case DurationUnitCalendarDays, DurationUnitBusinessDays:
+		return unit, nil
+	default:
+		return "", fmt.Errorf(
+			"invalid duration unit: %q, expected %q or %q",
+			unit,
+			DurationUnitCalendarDays,
+			DurationUnitBusinessDays,
+		)
+	}
+}
+
+// DayCounter counts the days of durations in a unit. Business days are
+// counted by the calendar, which holds the holidays observed, or leave
+// out only weekends when it is nil. The zero value counts calendar
+// days.
+type DayCounter struct {
+	Unit     DurationUnit
+	Calendar *calendar.Calendar
+}
+
+// Days returns the days from `from` to `to`, negative when `to` is
+// before `from`.
+func (counter DayCounter) Days(from, to time.Time) float64 {
+	if counter.Unit == DurationUnitBusinessDays {
+		days := counter.Calendar
+		if days == nil {
+			days = calendar.New(calendar.Options{})
+		}
+		return float64(days.BusinessDaysBetween(from, to))
+	}
+
+	return to.Sub(from).Hours() / 24
+}
@@ -0,0 +1,46 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/src/calendar"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDurationUnit(t *testing.T) {
+	for i, testCase := range []struct {
+		Unit          DurationUnit
+		Expected      DurationUnit
+		ExpectedError bool
+	}{
+		{Unit: "", Expected: DurationUnitCalendarDays},
+		{Unit: DurationUnitCalendarDays, Expected: DurationUnitCalendarDays},
+		{Unit: DurationUnitBusinessDays, Expected: DurationUnitBusinessDays},
+		{Unit: "weeks", ExpectedError: true},
+	} {
+		unit, err := ParseDurationUnit(testCase.Unit)
+		if testResult := (err != nil) == testCase.ExpectedError && unit == testCase.Expected; !testResult {
+			t.Errorf("Test case %d failed", i)
+		}
+	}
+}
+
+func TestDayCounter(t *testing.T) {
+	// from Friday to the Tuesday after Good Friday, a week later
+	from := time.Date(2024, time.March, 22, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 2, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, 11.0, DayCounter{}.Days(from, to))
+	require.Equal(t, 7.0, DayCounter{Unit: DurationUnitBusinessDays}.Days(from, to))
+
+	businessDays := DayCounter{
+		Unit: DurationUnitBusinessDays,
+		Calendar: calendar.New(calendar.Options{
+			Holidays: []calendar.HolidayCalendar{calendar.BrazilNational},
+		}),
+	}
+	require.Equal(t, 6.0, businessDays.Days(from, to))
+	require.Equal(t, -6.0, businessDays.Days(to, from))
+}
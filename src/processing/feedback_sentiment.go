@@ -0,0 +1,79 @@
+package processing
+
+import (
+	"sort"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+type FeedbackSentimentPeriod struct {
+	Period    string  `json:"period"`
+	Count     int     `json:"count"`
+	MeanScore float64 `json:"meanScore"`
+	Positive  int     `json:"positive"`
+	Neutral   int     `json:"neutral"`
+	Negative  int     `json:"negative"`
+}
+
+type FeedbackSentimentTrend struct {
+	Granularity CohortGranularity         `json:"granularity"`
+	Periods     []FeedbackSentimentPeriod `json:"periods"`
+}
+
+// GenerateFeedbackSentimentTrend groups the feedbacks by the period of
+// their date and counts them by sentiment. Periods without feedbacks
+// are left out.
+func GenerateFeedbackSentimentTrend(
+	feedbacks []*ent.DimFeedback,
+	granularity CohortGranularity,
+) (FeedbackSentimentTrend, error) {
+	if _, err := periodKey(time.Time{}, granularity); err != nil {
+		return FeedbackSentimentTrend{}, err
+	}
+
+	periods := make(map[string]*FeedbackSentimentPeriod)
+	for _, feedback := range feedbacks {
+		if feedback.Date == nil || !feedback.Date.Valid {
+			continue
+		}
+
+		key, err := periodKey(feedback.Date.Time, granularity)
+		if err != nil {
+			return FeedbackSentimentTrend{}, err
+		}
+
+		period, ok := periods[key]
+		if !ok {
+			period = &FeedbackSentimentPeriod{Period: key}
+			periods[key] = period
+		}
+
+		period.Count++
+		period.MeanScore += feedback.SentimentScore
+		switch property.FeedbackSentimentOf(feedback.SentimentScore) {
+		case property.FeedbackSentimentPositive:
+			period.Positive++
+		case property.FeedbackSentimentNegative:
+			period.Negative++
+		default:
+			period.Neutral++
+		}
+	}
+
+	trend := FeedbackSentimentTrend{
+		Granularity: granularity,
+		Periods:     []FeedbackSentimentPeriod{},
+	}
+	for _, period := range periods {
+		period.MeanScore /= float64(period.Count)
+		trend.Periods = append(trend.Periods, *period)
+	}
+
+	sort.Slice(trend.Periods, func(i, j int) bool {
+		return trend.Periods[i].Period < trend.Periods[j].Period
+	})
+
+	return trend, nil
+}
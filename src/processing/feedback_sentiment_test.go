@@ -0,0 +1,55 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func feedbackOn(month time.Month, day int, score float64) *ent.DimFeedback {
+	return &ent.DimFeedback{
+		Date:           &pgtype.Date{Time: time.Date(2024, month, day, 0, 0, 0, 0, time.UTC), Valid: true},
+		SentimentScore: score,
+	}
+}
+
+func TestGenerateFeedbackSentimentTrend(t *testing.T) {
+	feedbacks := []*ent.DimFeedback{
+		feedbackOn(8, 20, -0.5),
+		feedbackOn(7, 1, 0.8),
+		feedbackOn(7, 15, 0),
+		feedbackOn(7, 31, 0.4),
+		{SentimentScore: 1},
+	}
+
+	trend, err := GenerateFeedbackSentimentTrend(feedbacks, CohortGranularityMonth)
+	require.NoError(t, err)
+	require.Len(t, trend.Periods, 2)
+
+	july := trend.Periods[0]
+	require.Equal(t, "2024-07", july.Period)
+	require.Equal(t, 3, july.Count)
+	require.InDelta(t, 0.4, july.MeanScore, 1e-9)
+	require.Equal(t, 2, july.Positive)
+	require.Equal(t, 1, july.Neutral)
+	require.Equal(t, 0, july.Negative)
+
+	august := trend.Periods[1]
+	require.Equal(t, "2024-08", august.Period)
+	require.Equal(t, 1, august.Negative)
+
+	weekly, err := GenerateFeedbackSentimentTrend(feedbacks, CohortGranularityWeek)
+	require.NoError(t, err)
+	require.Equal(t, "2024-W27", weekly.Periods[0].Period)
+
+	_, err = GenerateFeedbackSentimentTrend(feedbacks, "year")
+	require.Error(t, err)
+
+	empty, err := GenerateFeedbackSentimentTrend(nil, CohortGranularityMonth)
+	require.NoError(t, err)
+	require.Empty(t, empty.Periods)
+}
@@ -0,0 +1,545 @@
+package processing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+const (
+	DefaultForecastMinSamples   = 5
+	DefaultForecastHistoryWeeks = 12
+	DefaultForecastHorizonWeeks = 4
+)
+
+// ForecastConfidenceLevel is the share of the similar vacancies whose
+// time to fill lies within the interval of a forecast, from the 10th to
+// the 90th percentile.
+const ForecastConfidenceLevel = 0.8
+
+// ForecastBasis names the vacancies a fill date was estimated from,
+// from the most to the least similar.
+type ForecastBasis string
+
+const (
+	// same department and location, sharing a title keyword
+	ForecastBasisDepartmentLocationKeywords ForecastBasis = "departmentLocationKeywords"
+	// same department, sharing a title keyword
+	ForecastBasisDepartmentKeywords ForecastBasis = "departmentKeywords"
+	// same department
+	ForecastBasisDepartment ForecastBasis = "department"
+	// every filled vacancy
+	ForecastBasisAll ForecastBasis = "all"
+	// fewer than the minimum samples were filled, so there is no
+	// estimate
+	ForecastBasisInsufficientHistory ForecastBasis = "insufficientHistory"
+)
+
+// ForecastOptions configures a forecast. Vacancies are estimated from
+// the first basis with at least `MinSamples` filled vacancies, and the
+// hires per week of the `HorizonWeeks` from the week of the reference
+// date are forecast from the `HistoryWeeks` before it.
+type ForecastOptions struct {
+	ReferenceDate time.Time
+	MinSamples    int
+	HistoryWeeks  int
+	HorizonWeeks  int
+}
+
+// VacancyForecast estimates when the last position of an open vacancy
+// will be filled. The dates are nil when there is not enough history,
+// or when the vacancy has been open for longer than every similar
+// vacancy took to fill, which is flagged as `Overdue`.
+type VacancyForecast struct {
+	VacancyId    int           `json:"vacancyId"`
+	VacancyTitle string        `json:"vacancyTitle"`
+	Department   string        `json:"department"`
+	Location     string        `json:"location"`
+	OpeningDate  string        `json:"openingDate"`
+	NumPositions int           `json:"numPositions"`
+	NumHired     int           `json:"numHired"`
+	Basis        ForecastBasis `json:"basis"`
+	// filled vacancies of the basis that took longer to fill than the
+	// vacancy has been open
+	NumSamples       int     `json:"numSamples"`
+	Overdue          bool    `json:"overdue"`
+	ExpectedFillDate *string `json:"expectedFillDate"`
+	LowerFillDate    *string `json:"lowerFillDate"`
+	UpperFillDate    *string `json:"upperFillDate"`
+}
+
+type WeeklyHires struct {
+	Week      string `json:"week"`
+	StartDate string `json:"startDate"`
+	NumHires  int    `json:"numHires"`
+}
+
+type WeeklyHiresForecast struct {
+	Week      string  `json:"week"`
+	StartDate string  `json:"startDate"`
+	Expected  float64 `json:"expected"`
+	Lower     float64 `json:"lower"`
+	Upper     float64 `json:"upper"`
+}
+
+// DepartmentHiresForecast forecasts the hires of each week as the mean
+// of the weekly hires of the history, within the 10th and 90th
+// percentiles of them, widened to hold the mean when the history is
+// skewed.
+type DepartmentHiresForecast struct {
+	DepartmentId  int                   `json:"departmentId"`
+	Department    string                `json:"department"`
+	ExpectedHires float64               `json:"expectedHires"`
+	History       []WeeklyHires         `json:"history"`
+	Forecast      []WeeklyHiresForecast `json:"forecast"`
+}
+
+type HiringForecast struct {
+	ReferenceDate   string                    `json:"referenceDate"`
+	ConfidenceLevel float64                   `json:"confidenceLevel"`
+	Vacancies       []VacancyForecast         `json:"vacancies"`
+	Departments     []DepartmentHiresForecast `json:"departments"`
+}
+
+// forecastVacancy is what a forecast knows of a vacancy. `hires` are
+// sorted, and `closing` is the closing date of a closed vacancy, when
+// known.
+type forecastVacancy struct {
+	id           int
+	title        string
+	departmentId int
+	department   string
+	location     string
+	keywords     map[string]bool
+	opening      time.Time
+	numPositions int
+	hires        []time.Time
+	closed       bool
+	closing      *time.Time
+}
+
+// asOf returns the vacancy as it was at the reference date, so back
+// dated forecasts only see what was known then: the hires after the
+// date are left out, and the vacancy is open until its closing date.
+// It returns false when the vacancy opened after the date.
+func (vacancy forecastVacancy) asOf(referenceDate time.Time) (forecastVacancy, bool) {
+	if vacancy.opening.After(referenceDate) {
+		return forecastVacancy{}, false
+	}
+
+	numHires := sort.Search(len(vacancy.hires), func(i int) bool {
+		return vacancy.hires[i].After(referenceDate)
+	})
+	vacancy.hires = vacancy.hires[:numHires]
+
+	if vacancy.closed && vacancy.closing != nil && vacancy.closing.After(referenceDate) {
+		vacancy.closed = false
+	}
+
+	return vacancy, true
+}
+
+func vacanciesAsOf(vacancies []forecastVacancy, referenceDate time.Time) []forecastVacancy {
+	var result []forecastVacancy
+	for _, vacancy := range vacancies {
+		if vacancy, ok := vacancy.asOf(referenceDate); ok {
+			result = append(result, vacancy)
+		}
+	}
+
+	return result
+}
+
+// fillDays returns the days from the opening of the vacancy to the hire
+// of its last position, or false when it was not filled.
+func (vacancy forecastVacancy) fillDays() (float64, bool) {
+	if vacancy.numPositions <= 0 || len(vacancy.hires) < vacancy.numPositions {
+		return 0, false
+	}
+
+	return vacancy.hires[vacancy.numPositions-1].Sub(vacancy.opening).Hours() / 24, true
+}
+
+func (vacancy forecastVacancy) sharesKeyword(other forecastVacancy) bool {
+	for keyword := range vacancy.keywords {
+		if other.keywords[keyword] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// titleStopwords are left out of the title keywords.
+var titleStopwords = map[string]bool{
+	"and": true, "com": true, "das": true, "dos": true, "for": true,
+	"para": true, "the": true,
+}
+
+// titleKeywords splits a title into its lower case words of three or
+// more letters, leaving out the stopwords.
+func titleKeywords(title string) map[string]bool {
+	keywords := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= 3 && !titleStopwords[word] {
+			keywords[word] = true
+		}
+	}
+
+	return keywords
+}
+
+// GenerateHiringForecast estimates the fill date of each vacancy of the
+// facts open at the reference date, and the weekly hires of their
+// departments, from the vacancies of `history` as they were at that
+// date, so a past date backtests the forecast. Hires are dated by the
+// `updatedAt` of the hired candidates. Vacancies are told apart by their
+// `dbId`, taking their latest version, so the facts of every load can
+// be passed.
+func GenerateHiringForecast(
+	factHiringProcesses []*ent.FactHiringProcess,
+	history []*ent.FactHiringProcess,
+	options ForecastOptions,
+) (HiringForecast, error) {
+	vacancies, err := forecastVacanciesOf(factHiringProcesses)
+	if err != nil {
+		return HiringForecast{}, err
+	}
+
+	historyVacancies, err := forecastVacanciesOf(history)
+	if err != nil {
+		return HiringForecast{}, err
+	}
+
+	return generateHiringForecast(vacancies, historyVacancies, options), nil
+}
+
+func forecastVacanciesOf(
+	factHiringProcesses []*ent.FactHiringProcess,
+) ([]forecastVacancy, error) {
+	var vacancies []forecastVacancy
+	indexByDbId := make(map[int]int)
+
+	for _, factHiringProcess := range factHiringProcesses {
+		process, err := factHiringProcess.Edges.DimProcessOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimProcess` of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		vacancy, err := factHiringProcess.Edges.DimVacancyOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimVacancy` of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		if vacancy.OpeningDate == nil || !vacancy.OpeningDate.Valid {
+			continue
+		}
+
+		index, visited := indexByDbId[vacancy.DbId]
+		if visited && vacancies[index].id >= vacancy.ID {
+			continue
+		}
+
+		candidates, err := vacancy.Edges.DimCandidatesOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimCandidates` of `DimVacancy` with ID %d not found: %w",
+				vacancy.ID,
+				err,
+			)
+		}
+
+		department := ""
+		if dimDepartment, err := process.Edges.DimDepartmentOrErr(); err == nil {
+			department = dimDepartment.Name
+		}
+
+		record := newForecastVacancy(vacancy, candidates, process.DimDepartmentId, department)
+		if visited {
+			vacancies[index] = record
+		} else {
+			indexByDbId[vacancy.DbId] = len(vacancies)
+			vacancies = append(vacancies, record)
+		}
+	}
+
+	return vacancies, nil
+}
+
+func newForecastVacancy(
+	vacancy *ent.DimVacancy,
+	candidates []*ent.DimCandidate,
+	departmentId int,
+	department string,
+) forecastVacancy {
+	record := forecastVacancy{
+		id:           vacancy.ID,
+		title:        vacancy.Title,
+		departmentId: departmentId,
+		department:   department,
+		location:     vacancy.Location,
+		keywords:     titleKeywords(vacancy.Title),
+		opening:      vacancy.OpeningDate.Time,
+		numPositions: vacancy.NumPositions,
+		closed:       VacancyStatusOf(vacancy) == property.DimVacancyStatusClosed,
+	}
+
+	if record.closed && vacancy.ClosingDate != nil && vacancy.ClosingDate.Valid {
+		closing := vacancy.ClosingDate.Time
+		record.closing = &closing
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Status == property.DimCandidateStatusHired &&
+			candidate.UpdatedAt != nil && candidate.UpdatedAt.Valid {
+			record.hires = append(record.hires, candidate.UpdatedAt.Time)
+		}
+	}
+
+	sort.Slice(record.hires, func(i, j int) bool {
+		return record.hires[i].Before(record.hires[j])
+	})
+
+	return record
+}
+
+func generateHiringForecast(
+	vacancies []forecastVacancy,
+	history []forecastVacancy,
+	options ForecastOptions,
+) HiringForecast {
+	referenceDate := time.Date(
+		options.ReferenceDate.Year(), options.ReferenceDate.Month(), options.ReferenceDate.Day(),
+		0, 0, 0, 0, time.UTC,
+	)
+
+	vacancies = vacanciesAsOf(vacancies, referenceDate)
+	history = vacanciesAsOf(history, referenceDate)
+
+	forecast := HiringForecast{
+		ReferenceDate:   referenceDate.Format(time.DateOnly),
+		ConfidenceLevel: ForecastConfidenceLevel,
+		Vacancies:       []VacancyForecast{},
+		Departments:     []DepartmentHiresForecast{},
+	}
+
+	departments := make(map[int]string)
+	for _, vacancy := range vacancies {
+		departments[vacancy.departmentId] = vacancy.department
+
+		if vacancy.closed || len(vacancy.hires) >= vacancy.numPositions {
+			continue
+		}
+
+		forecast.Vacancies = append(
+			forecast.Vacancies,
+			forecastFillDate(vacancy, history, options.MinSamples, referenceDate),
+		)
+	}
+
+	sort.SliceStable(forecast.Vacancies, func(i, j int) bool {
+		a, b := forecast.Vacancies[i], forecast.Vacancies[j]
+		if a.OpeningDate != b.OpeningDate {
+			return a.OpeningDate < b.OpeningDate
+		}
+		return a.VacancyId < b.VacancyId
+	})
+
+	for departmentId, department := range departments {
+		forecast.Departments = append(
+			forecast.Departments,
+			forecastDepartmentHires(departmentId, department, history, options, referenceDate),
+		)
+	}
+
+	sort.SliceStable(forecast.Departments, func(i, j int) bool {
+		a, b := forecast.Departments[i], forecast.Departments[j]
+		if a.Department != b.Department {
+			return a.Department < b.Department
+		}
+		return a.DepartmentId < b.DepartmentId
+	})
+
+	return forecast
+}
+
+// forecastFillDate estimates the fill date of the vacancy from the time
+// to fill of the first basis with enough filled vacancies, counting only
+// the ones that took longer than the vacancy has been open so far.
+func forecastFillDate(
+	vacancy forecastVacancy,
+	history []forecastVacancy,
+	minSamples int,
+	referenceDate time.Time,
+) VacancyForecast {
+	result := VacancyForecast{
+		VacancyId:    vacancy.id,
+		VacancyTitle: vacancy.title,
+		Department:   vacancy.department,
+		Location:     vacancy.location,
+		OpeningDate:  vacancy.opening.Format(time.DateOnly),
+		NumPositions: vacancy.numPositions,
+		NumHired:     len(vacancy.hires),
+		Basis:        ForecastBasisInsufficientHistory,
+	}
+
+	bases := []struct {
+		basis   ForecastBasis
+		similar func(other forecastVacancy) bool
+	}{
+		{ForecastBasisDepartmentLocationKeywords, func(other forecastVacancy) bool {
+			return other.departmentId == vacancy.departmentId &&
+				other.location == vacancy.location &&
+				other.sharesKeyword(vacancy)
+		}},
+		{ForecastBasisDepartmentKeywords, func(other forecastVacancy) bool {
+			return other.departmentId == vacancy.departmentId && other.sharesKeyword(vacancy)
+		}},
+		{ForecastBasisDepartment, func(other forecastVacancy) bool {
+			return other.departmentId == vacancy.departmentId
+		}},
+		{ForecastBasisAll, func(forecastVacancy) bool {
+			return true
+		}},
+	}
+
+	var samples []float64
+	for _, basis := range bases {
+		samples = nil
+		for _, other := range history {
+			if other.id == vacancy.id || !basis.similar(other) {
+				continue
+			}
+			if days, filled := other.fillDays(); filled {
+				samples = append(samples, days)
+			}
+		}
+
+		if len(samples) >= minSamples && len(samples) > 0 {
+			result.Basis = basis.basis
+			break
+		}
+	}
+
+	if result.Basis == ForecastBasisInsufficientHistory {
+		return result
+	}
+
+	elapsedDays := referenceDate.Sub(vacancy.opening).Hours() / 24
+	var remaining []float64
+	for _, days := range samples {
+		if days > elapsedDays {
+			remaining = append(remaining, days)
+		}
+	}
+
+	result.NumSamples = len(remaining)
+	if len(remaining) == 0 {
+		result.Overdue = true
+		return result
+	}
+
+	sort.Float64s(remaining)
+	fillDate := func(p float64) *string {
+		days := int(math.Round(percentile(remaining, p)))
+		date := vacancy.opening.AddDate(0, 0, days).Format(time.DateOnly)
+		return &date
+	}
+	result.ExpectedFillDate = fillDate(50)
+	result.LowerFillDate = fillDate(10)
+	result.UpperFillDate = fillDate(90)
+
+	return result
+}
+
+// weekStart returns the Monday of the ISO week of the date.
+func weekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+func forecastDepartmentHires(
+	departmentId int,
+	department string,
+	history []forecastVacancy,
+	options ForecastOptions,
+	referenceDate time.Time,
+) DepartmentHiresForecast {
+	result := DepartmentHiresForecast{
+		DepartmentId: departmentId,
+		Department:   department,
+		History:      []WeeklyHires{},
+		Forecast:     []WeeklyHiresForecast{},
+	}
+
+	currentWeek := weekStart(referenceDate)
+	firstWeek := currentWeek.AddDate(0, 0, -7*options.HistoryWeeks)
+
+	counts := make([]int, options.HistoryWeeks)
+	for _, vacancy := range history {
+		if vacancy.departmentId != departmentId {
+			continue
+		}
+
+		for _, hire := range vacancy.hires {
+			if hire.Before(firstWeek) || !hire.Before(currentWeek) {
+				continue
+			}
+			counts[int(hire.Sub(firstWeek).Hours()/24)/7]++
+		}
+	}
+
+	total := 0.0
+	sortedCounts := make([]float64, len(counts))
+	for i, count := range counts {
+		start := firstWeek.AddDate(0, 0, 7*i)
+		week, _ := periodKey(start, CohortGranularityWeek)
+		result.History = append(result.History, WeeklyHires{
+			Week:      week,
+			StartDate: start.Format(time.DateOnly),
+			NumHires:  count,
+		})
+
+		total += float64(count)
+		sortedCounts[i] = float64(count)
+	}
+	sort.Float64s(sortedCounts)
+
+	expected := 0.0
+	if len(counts) > 0 {
+		expected = total / float64(len(counts))
+	}
+	lower := math.Min(percentile(sortedCounts, 10), expected)
+	upper := math.Max(percentile(sortedCounts, 90), expected)
+
+	for i := 0; i < options.HorizonWeeks; i++ {
+		start := currentWeek.AddDate(0, 0, 7*i)
+		week, _ := periodKey(start, CohortGranularityWeek)
+		result.Forecast = append(result.Forecast, WeeklyHiresForecast{
+			Week:      week,
+			StartDate: start.Format(time.DateOnly),
+			Expected:  expected,
+			Lower:     lower,
+			Upper:     upper,
+		})
+	}
+	result.ExpectedHires = expected * float64(options.HorizonWeeks)
+
+	return result
+}
@@ -0,0 +1,290 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func forecastDate(month time.Month, day int) time.Time {
+	return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// forecastRecord builds a vacancy opened at `opening` whose candidates
+// were hired the given days after it.
+func forecastRecord(
+	id, departmentId int,
+	location, title string,
+	opening time.Time,
+	numPositions int,
+	hireDays ...int,
+) forecastVacancy {
+	vacancy := forecastVacancy{
+		id:           id,
+		title:        title,
+		departmentId: departmentId,
+		department:   map[int]string{1: "Engineering", 2: "Sales"}[departmentId],
+		location:     location,
+		keywords:     titleKeywords(title),
+		opening:      opening,
+		numPositions: numPositions,
+	}
+	for _, days := range hireDays {
+		vacancy.hires = append(vacancy.hires, opening.AddDate(0, 0, days))
+	}
+	return vacancy
+}
+
+// seededForecastHistory is a fixed history of filled vacancies, all of
+// them opened on the first day of 2024.
+func seededForecastHistory() []forecastVacancy {
+	opening := forecastDate(time.January, 1)
+	history := []forecastVacancy{}
+	for i, days := range []int{10, 20, 30, 40, 50} {
+		history = append(history, forecastRecord(100+i, 1, "São Paulo", "Backend Developer", opening, 1, days))
+	}
+	return append(
+		history,
+		forecastRecord(110, 1, "Rio de Janeiro", "Backend Engineer", opening, 1, 60),
+		forecastRecord(111, 1, "Rio de Janeiro", "Backend Engineer", opening, 1, 70),
+		forecastRecord(112, 1, "São Paulo", "Data Analyst", opening, 1, 100),
+		forecastRecord(113, 1, "São Paulo", "Data Analyst", opening, 2, 15),
+		forecastRecord(120, 2, "São Paulo", "Account Executive", opening, 1, 5),
+		forecastRecord(121, 2, "São Paulo", "Account Executive", opening, 1, 6),
+		forecastRecord(122, 2, "São Paulo", "Account Executive", opening, 1, 7),
+	)
+}
+
+func TestTitleKeywords(t *testing.T) {
+	require.Equal(t, map[string]bool{
+		"desenvolvedor": true,
+		"backend":       true,
+		"sênior":        true,
+	}, titleKeywords("Desenvolvedor(a) Backend Sênior para Go"))
+}
+
+func TestForecastFillDate(t *testing.T) {
+	history := seededForecastHistory()
+	opening := forecastDate(time.June, 3)
+	date := func(value string) *string {
+		return &value
+	}
+
+	for i, testCase := range []struct {
+		Name          string
+		Vacancy       forecastVacancy
+		MinSamples    int
+		ReferenceDate time.Time
+		Expected      VacancyForecast
+	}{
+		{
+			Name:          "same department, location and keywords",
+			Vacancy:       forecastRecord(1, 1, "São Paulo", "Senior Backend Developer", opening, 1),
+			MinSamples:    5,
+			ReferenceDate: opening,
+			Expected: VacancyForecast{
+				Basis:            ForecastBasisDepartmentLocationKeywords,
+				NumSamples:       5,
+				ExpectedFillDate: date("2024-07-03"),
+				LowerFillDate:    date("2024-06-17"),
+				UpperFillDate:    date("2024-07-19"),
+			},
+		},
+		{
+			Name:          "only the vacancies slower than the time open so far",
+			Vacancy:       forecastRecord(1, 1, "São Paulo", "Senior Backend Developer", opening, 1),
+			MinSamples:    5,
+			ReferenceDate: forecastDate(time.June, 28),
+			Expected: VacancyForecast{
+				Basis:            ForecastBasisDepartmentLocationKeywords,
+				NumSamples:       3,
+				ExpectedFillDate: date("2024-07-13"),
+				LowerFillDate:    date("2024-07-05"),
+				UpperFillDate:    date("2024-07-21"),
+			},
+		},
+		{
+			Name:          "same department and keywords in another location",
+			Vacancy:       forecastRecord(1, 1, "Curitiba", "Backend Engineer", opening, 1),
+			MinSamples:    5,
+			ReferenceDate: opening,
+			Expected: VacancyForecast{
+				Basis:            ForecastBasisDepartmentKeywords,
+				NumSamples:       7,
+				ExpectedFillDate: date("2024-07-13"),
+				LowerFillDate:    date("2024-06-19"),
+				UpperFillDate:    date("2024-08-06"),
+			},
+		},
+		{
+			Name:          "same department without shared keywords",
+			Vacancy:       forecastRecord(1, 1, "Curitiba", "Designer", opening, 1),
+			MinSamples:    5,
+			ReferenceDate: opening,
+			Expected: VacancyForecast{
+				Basis:            ForecastBasisDepartment,
+				NumSamples:       8,
+				ExpectedFillDate: date("2024-07-18"),
+				LowerFillDate:    date("2024-06-20"),
+				UpperFillDate:    date("2024-08-21"),
+			},
+		},
+		{
+			Name:          "every vacancy when the department has too few",
+			Vacancy:       forecastRecord(1, 2, "Curitiba", "Designer", opening, 1),
+			MinSamples:    5,
+			ReferenceDate: opening,
+			Expected: VacancyForecast{
+				Basis:            ForecastBasisAll,
+				NumSamples:       11,
+				ExpectedFillDate: date("2024-07-03"),
+				LowerFillDate:    date("2024-06-09"),
+				UpperFillDate:    date("2024-08-12"),
+			},
+		},
+		{
+			Name:          "too few filled vacancies",
+			Vacancy:       forecastRecord(1, 1, "São Paulo", "Backend Developer", opening, 1),
+			MinSamples:    20,
+			ReferenceDate: opening,
+			Expected: VacancyForecast{
+				Basis: ForecastBasisInsufficientHistory,
+			},
+		},
+		{
+			Name:          "open for longer than every similar vacancy",
+			Vacancy:       forecastRecord(1, 1, "São Paulo", "Backend Developer", forecastDate(time.January, 1), 1),
+			MinSamples:    5,
+			ReferenceDate: forecastDate(time.December, 31),
+			Expected: VacancyForecast{
+				Basis:   ForecastBasisDepartmentLocationKeywords,
+				Overdue: true,
+			},
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			result := forecastFillDate(testCase.Vacancy, history, testCase.MinSamples, testCase.ReferenceDate)
+
+			result.VacancyId, result.VacancyTitle, result.Department = 0, "", ""
+			result.Location, result.OpeningDate, result.NumPositions = "", "", 0
+			if testResult := assert.Equal(t, testCase.Expected, result); !testResult {
+				t.Errorf("Test case %d failed", i)
+			}
+		})
+	}
+}
+
+func TestForecastDepartmentHires(t *testing.T) {
+	history := []forecastVacancy{
+		{
+			departmentId: 2,
+			hires: []time.Time{
+				forecastDate(time.May, 5),
+				forecastDate(time.May, 7),
+				forecastDate(time.May, 8),
+				forecastDate(time.May, 21),
+				forecastDate(time.June, 3),
+			},
+		},
+		{
+			departmentId: 1,
+			hires:        []time.Time{forecastDate(time.May, 14)},
+		},
+	}
+
+	result := forecastDepartmentHires(2, "Sales", history, ForecastOptions{
+		HistoryWeeks: 4,
+		HorizonWeeks: 2,
+	}, forecastDate(time.June, 5))
+
+	require.Equal(t, []WeeklyHires{
+		{Week: "2024-W19", StartDate: "2024-05-06", NumHires: 2},
+		{Week: "2024-W20", StartDate: "2024-05-13", NumHires: 0},
+		{Week: "2024-W21", StartDate: "2024-05-20", NumHires: 1},
+		{Week: "2024-W22", StartDate: "2024-05-27", NumHires: 0},
+	}, result.History)
+
+	require.Len(t, result.Forecast, 2)
+	require.Equal(t, "2024-W23", result.Forecast[0].Week)
+	require.Equal(t, "2024-06-10", result.Forecast[1].StartDate)
+	require.Equal(t, 0.75, result.Forecast[0].Expected)
+	require.Equal(t, 0.0, result.Forecast[0].Lower)
+	require.InDelta(t, 1.7, result.Forecast[0].Upper, 1e-9)
+	require.Equal(t, 1.5, result.ExpectedHires)
+}
+
+func TestGenerateHiringForecast(t *testing.T) {
+	history := seededForecastHistory()
+	opening := forecastDate(time.June, 3)
+
+	closed := forecastRecord(3, 1, "São Paulo", "Backend Developer", opening, 1)
+	closed.closed = true
+
+	vacancies := []forecastVacancy{
+		forecastRecord(2, 2, "São Paulo", "Account Executive", opening, 1),
+		forecastRecord(1, 1, "São Paulo", "Backend Developer", forecastDate(time.May, 1), 2, 3),
+		closed,
+		forecastRecord(4, 1, "São Paulo", "Backend Developer", opening, 1, 2),
+	}
+	options := ForecastOptions{
+		ReferenceDate: forecastDate(time.June, 5),
+		MinSamples:    DefaultForecastMinSamples,
+		HistoryWeeks:  DefaultForecastHistoryWeeks,
+		HorizonWeeks:  DefaultForecastHorizonWeeks,
+	}
+
+	forecast := generateHiringForecast(vacancies, history, options)
+
+	require.Equal(t, "2024-06-05", forecast.ReferenceDate)
+	require.Equal(t, ForecastConfidenceLevel, forecast.ConfidenceLevel)
+	require.Len(t, forecast.Vacancies, 2)
+	require.Equal(t, 1, forecast.Vacancies[0].VacancyId)
+	require.Equal(t, 1, forecast.Vacancies[0].NumHired)
+	require.Equal(t, 2, forecast.Vacancies[1].VacancyId)
+	require.Equal(t, ForecastBasisAll, forecast.Vacancies[1].Basis)
+
+	require.Len(t, forecast.Departments, 2)
+	require.Equal(t, "Engineering", forecast.Departments[0].Department)
+	require.Equal(t, "Sales", forecast.Departments[1].Department)
+	require.Len(t, forecast.Departments[0].History, DefaultForecastHistoryWeeks)
+	require.Len(t, forecast.Departments[0].Forecast, DefaultForecastHorizonWeeks)
+
+	require.Equal(t, forecast, generateHiringForecast(vacancies, history, options))
+}
+
+func TestBackdatedHiringForecast(t *testing.T) {
+	history := seededForecastHistory()
+	closing := forecastDate(time.March, 1)
+
+	closed := forecastRecord(3, 1, "São Paulo", "Backend Developer", forecastDate(time.January, 1), 1, 45)
+	closed.closed = true
+	closed.closing = &closing
+
+	vacancies := []forecastVacancy{
+		// filled on the 10th of February, after the reference date
+		forecastRecord(1, 1, "São Paulo", "Backend Developer", forecastDate(time.January, 1), 1, 40),
+		// opened after the reference date
+		forecastRecord(2, 1, "São Paulo", "Backend Developer", forecastDate(time.February, 1), 1),
+		closed,
+	}
+
+	forecast := generateHiringForecast(vacancies, history, ForecastOptions{
+		ReferenceDate: forecastDate(time.January, 31),
+		MinSamples:    3,
+		HistoryWeeks:  DefaultForecastHistoryWeeks,
+		HorizonWeeks:  DefaultForecastHorizonWeeks,
+	})
+
+	require.Len(t, forecast.Vacancies, 2)
+	for _, vacancy := range forecast.Vacancies {
+		require.Zero(t, vacancy.NumHired)
+		// only the vacancies filled by the 31st of January, in 10, 20
+		// and 30 days, are samples, and all of them were faster
+		require.Equal(t, ForecastBasisDepartmentLocationKeywords, vacancy.Basis)
+		require.True(t, vacancy.Overdue)
+	}
+	require.Equal(t, 1, forecast.Vacancies[0].VacancyId)
+	require.Equal(t, 3, forecast.Vacancies[1].VacancyId)
+}
@@ -0,0 +1,131 @@
+package processing
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+var DefaultHiringTimeBucketWidth = 7
+
+type HiringTimeHistogramBucket struct {
+	StartInDays float64 `json:"startInDays"`
+	EndInDays   float64 `json:"endInDays"`
+	Count       int     `json:"count"`
+}
+
+type HiringTimeStatistics struct {
+	Count     int                         `json:"count" default:"0"`
+	Mean      float32                     `json:"mean" default:"0"`
+	Median    float32                     `json:"median" default:"0"`
+	P75       float32                     `json:"p75" default:"0"`
+	P90       float32                     `json:"p90" default:"0"`
+	Min       float32                     `json:"min" default:"0"`
+	Max       float32                     `json:"max" default:"0"`
+	Histogram []HiringTimeHistogramBucket `json:"histogram"`
+}
+
+// HiredCandidateDurations returns the hiring duration in days of
+// each hired candidate, from `applyDate` to `updatedAt`, counted by
+// `days`.
+func HiredCandidateDurations(
+	candidates []*ent.DimCandidate,
+	days DayCounter,
+) []float64 {
+	var durations []float64
+
+	for _, candidate := range candidates {
+		if candidate.Status != property.DimCandidateStatusHired {
+			continue
+		}
+
+		if candidate.ApplyDate == nil || candidate.UpdatedAt == nil {
+			continue
+		}
+
+		durations = append(durations, days.Days(
+			candidate.ApplyDate.Time,
+			candidate.UpdatedAt.Time,
+		))
+	}
+
+	return durations
+}
+
+// percentile interpolates linearly between the closest ranks
+// of an already sorted slice of values.
+func percentile(sortedValues []float64, p float64) float64 {
+	if len(sortedValues) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sortedValues)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	if lower == upper {
+		return sortedValues[lower]
+	}
+
+	weight := rank - float64(lower)
+	return sortedValues[lower]*(1-weight) + sortedValues[upper]*weight
+}
+
+func GenerateHiringTimeStatistics(
+	durations []float64,
+	bucketWidth int,
+) (HiringTimeStatistics, error) {
+	if bucketWidth <= 0 {
+		return HiringTimeStatistics{}, errors.New(
+			"invalid histogram bucket width",
+		)
+	}
+
+	if len(durations) == 0 {
+		return HiringTimeStatistics{
+			Histogram: []HiringTimeHistogramBucket{},
+		}, nil
+	}
+
+	sortedDurations := make([]float64, len(durations))
+	copy(sortedDurations, durations)
+	sort.Float64s(sortedDurations)
+
+	total := 0.0
+	for _, duration := range sortedDurations {
+		total += duration
+	}
+
+	minDuration := sortedDurations[0]
+	maxDuration := sortedDurations[len(sortedDurations)-1]
+
+	width := float64(bucketWidth)
+	numBuckets := int(math.Floor(math.Max(maxDuration, 0)/width)) + 1
+
+	histogram := make([]HiringTimeHistogramBucket, numBuckets)
+	for i := range histogram {
+		histogram[i] = HiringTimeHistogramBucket{
+			StartInDays: float64(i) * width,
+			EndInDays:   float64(i+1) * width,
+		}
+	}
+
+	for _, duration := range sortedDurations {
+		bucketIndex := int(math.Floor(math.Max(duration, 0) / width))
+		histogram[bucketIndex].Count++
+	}
+
+	return HiringTimeStatistics{
+		Count:     len(sortedDurations),
+		Mean:      float32(total / float64(len(sortedDurations))),
+		Median:    float32(percentile(sortedDurations, 50)),
+		P75:       float32(percentile(sortedDurations, 75)),
+		P90:       float32(percentile(sortedDurations, 90)),
+		Min:       float32(minDuration),
+		Max:       float32(maxDuration),
+		Histogram: histogram,
+	}, nil
+}
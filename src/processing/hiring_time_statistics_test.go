@@ -0,0 +1,113 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/calendar"
+	"api5back/src/property"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCandidate(
+	status property.DimCandidateStatus,
+	applyDate string,
+	updatedAt string,
+) *ent.DimCandidate {
+	apply, _ := time.Parse(time.DateOnly, applyDate)
+	updated, _ := time.Parse(time.DateOnly, updatedAt)
+
+	return &ent.DimCandidate{
+		Status:    status,
+		ApplyDate: &pgtype.Date{Time: apply, Valid: true},
+		UpdatedAt: &pgtype.Date{Time: updated, Valid: true},
+	}
+}
+
+func TestHiredCandidateDurations(t *testing.T) {
+	candidates := []*ent.DimCandidate{
+		newTestCandidate(property.DimCandidateStatusHired, "2024-01-01", "2024-01-11"),
+		newTestCandidate(property.DimCandidateStatusRejected, "2024-01-01", "2024-01-05"),
+		newTestCandidate(property.DimCandidateStatusHired, "2024-02-01", "2024-02-03"),
+		{Status: property.DimCandidateStatusHired},
+	}
+
+	require.Equal(t, []float64{10, 2}, HiredCandidateDurations(candidates, DayCounter{}))
+
+	businessDays := DayCounter{
+		Unit: DurationUnitBusinessDays,
+		Calendar: calendar.New(calendar.Options{
+			Holidays: []calendar.HolidayCalendar{calendar.BrazilNational},
+		}),
+	}
+	require.Equal(t, []float64{8, 1}, HiredCandidateDurations(candidates, businessDays))
+}
+
+func TestGenerateHiringTimeStatistics(t *testing.T) {
+	for i, testCase := range []struct {
+		Name          string
+		Durations     []float64
+		BucketWidth   int
+		ExpectedError bool
+		Expected      HiringTimeStatistics
+	}{
+		{
+			Name:        "no hired candidates should not divide by zero",
+			Durations:   nil,
+			BucketWidth: 7,
+			Expected: HiringTimeStatistics{
+				Histogram: []HiringTimeHistogramBucket{},
+			},
+		},
+		{
+			Name:          "invalid bucket width",
+			Durations:     []float64{1, 2},
+			BucketWidth:   0,
+			ExpectedError: true,
+		},
+		{
+			Name:        "single duration",
+			Durations:   []float64{3},
+			BucketWidth: 7,
+			Expected: HiringTimeStatistics{
+				Count: 1, Mean: 3, Median: 3, P75: 3, P90: 3, Min: 3, Max: 3,
+				Histogram: []HiringTimeHistogramBucket{
+					{StartInDays: 0, EndInDays: 7, Count: 1},
+				},
+			},
+		},
+		{
+			Name:        "slow hire skews mean but not median",
+			Durations:   []float64{30, 2, 4, 6, 8},
+			BucketWidth: 10,
+			Expected: HiringTimeStatistics{
+				Count: 5, Mean: 10, Median: 6, P75: 8, P90: 21.2, Min: 2, Max: 30,
+				Histogram: []HiringTimeHistogramBucket{
+					{StartInDays: 0, EndInDays: 10, Count: 4},
+					{StartInDays: 10, EndInDays: 20, Count: 0},
+					{StartInDays: 20, EndInDays: 30, Count: 0},
+					{StartInDays: 30, EndInDays: 40, Count: 1},
+				},
+			},
+		},
+	} {
+		if testResult := t.Run(testCase.Name, func(t *testing.T) {
+			statistics, err := GenerateHiringTimeStatistics(
+				testCase.Durations,
+				testCase.BucketWidth,
+			)
+			if testCase.ExpectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.Expected, statistics)
+		}); !testResult {
+			t.Errorf("Test case %d failed", i)
+		}
+	}
+}
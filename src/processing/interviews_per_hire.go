@@ -0,0 +1,124 @@
+package processing
+
+import (
+	"fmt"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+type VacancyInterviewsPerHire struct {
+	VacancyId     int    `json:"vacancyId"`
+	VacancyTitle  string `json:"vacancyTitle"`
+	NumInterviews int    `json:"numInterviews"`
+	NumHired      int    `json:"numHired"`
+	// nil when nobody was hired
+	InterviewsPerHire *float64 `json:"interviewsPerHire"`
+}
+
+type InterviewsPerHire struct {
+	NumInterviews int `json:"numInterviews"`
+	NumHired      int `json:"numHired"`
+	// nil when nobody was hired
+	InterviewsPerHire *float64                   `json:"interviewsPerHire"`
+	Vacancies         []VacancyInterviewsPerHire `json:"vacancies"`
+}
+
+func interviewsPerHire(numInterviews, numHired int) *float64 {
+	if numHired == 0 {
+		return nil
+	}
+
+	ratio := float64(numInterviews) / float64(numHired)
+	return &ratio
+}
+
+// GenerateInterviewsPerHire divides the interviews held, leaving out the
+// ones the candidate did not show up to, by the candidates hired, for
+// each vacancy of the facts and overall.
+func GenerateInterviewsPerHire(
+	factHiringProcesses []*ent.FactHiringProcess,
+) (InterviewsPerHire, error) {
+	vacancies := []VacancyInterviewsPerHire{}
+	visitedVacancies := make(map[int]bool)
+
+	for _, factHiringProcess := range factHiringProcesses {
+		vacancy, err := factHiringProcess.Edges.DimVacancyOrErr()
+		if err != nil {
+			return InterviewsPerHire{}, fmt.Errorf(
+				"`DimVacancy` of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		if visitedVacancies[vacancy.ID] {
+			continue
+		}
+		visitedVacancies[vacancy.ID] = true
+
+		interviews, err := vacancy.Edges.DimInterviewsOrErr()
+		if err != nil {
+			return InterviewsPerHire{}, fmt.Errorf(
+				"`DimInterviews` of `DimVacancy` with ID %d not found: %w",
+				vacancy.ID,
+				err,
+			)
+		}
+
+		candidates, err := vacancy.Edges.DimCandidatesOrErr()
+		if err != nil {
+			return InterviewsPerHire{}, fmt.Errorf(
+				"`DimCandidates` of `DimVacancy` with ID %d not found: %w",
+				vacancy.ID,
+				err,
+			)
+		}
+
+		vacancies = append(
+			vacancies,
+			vacancyInterviewsPerHire(vacancy, interviews, candidates),
+		)
+	}
+
+	return summarizeInterviewsPerHire(vacancies), nil
+}
+
+func vacancyInterviewsPerHire(
+	vacancy *ent.DimVacancy,
+	interviews []*ent.DimInterview,
+	candidates []*ent.DimCandidate,
+) VacancyInterviewsPerHire {
+	row := VacancyInterviewsPerHire{
+		VacancyId:    vacancy.ID,
+		VacancyTitle: vacancy.Title,
+	}
+	for _, interview := range interviews {
+		if interview.Outcome != property.DimInterviewOutcomeNoShow {
+			row.NumInterviews++
+		}
+	}
+	for _, candidate := range candidates {
+		if candidate.Status == property.DimCandidateStatusHired {
+			row.NumHired++
+		}
+	}
+	row.InterviewsPerHire = interviewsPerHire(row.NumInterviews, row.NumHired)
+
+	return row
+}
+
+func summarizeInterviewsPerHire(
+	vacancies []VacancyInterviewsPerHire,
+) InterviewsPerHire {
+	result := InterviewsPerHire{
+		Vacancies: vacancies,
+	}
+	for _, vacancy := range vacancies {
+		result.NumInterviews += vacancy.NumInterviews
+		result.NumHired += vacancy.NumHired
+	}
+	result.InterviewsPerHire = interviewsPerHire(result.NumInterviews, result.NumHired)
+
+	return result
+}
@@ -0,0 +1,56 @@
+package processing
+
+import (
+	"testing"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterviewsPerHire(t *testing.T) {
+	withHires := vacancyInterviewsPerHire(
+		&ent.DimVacancy{ID: 1, Title: "Go Developer"},
+		[]*ent.DimInterview{
+			{Outcome: property.DimInterviewOutcomeApproved},
+			{Outcome: property.DimInterviewOutcomeRejected},
+			{Outcome: property.DimInterviewOutcomePending},
+			{Outcome: property.DimInterviewOutcomeNoShow},
+		},
+		[]*ent.DimCandidate{
+			{Status: property.DimCandidateStatusHired},
+			{Status: property.DimCandidateStatusInterview},
+			{Status: property.DimCandidateStatusHired},
+		},
+	)
+	require.Equal(t, 3, withHires.NumInterviews)
+	require.Equal(t, 2, withHires.NumHired)
+	require.NotNil(t, withHires.InterviewsPerHire)
+	require.Equal(t, 1.5, *withHires.InterviewsPerHire)
+
+	withoutHires := vacancyInterviewsPerHire(
+		&ent.DimVacancy{ID: 2, Title: "Data Analyst"},
+		[]*ent.DimInterview{
+			{Outcome: property.DimInterviewOutcomePending},
+		},
+		[]*ent.DimCandidate{
+			{Status: property.DimCandidateStatusInterview},
+		},
+	)
+	require.Equal(t, 1, withoutHires.NumInterviews)
+	require.Nil(t, withoutHires.InterviewsPerHire)
+
+	summary := summarizeInterviewsPerHire(
+		[]VacancyInterviewsPerHire{withHires, withoutHires},
+	)
+	require.Equal(t, 4, summary.NumInterviews)
+	require.Equal(t, 2, summary.NumHired)
+	require.Equal(t, 2.0, *summary.InterviewsPerHire)
+	require.Len(t, summary.Vacancies, 2)
+
+	require.Nil(t, summarizeInterviewsPerHire(nil).InterviewsPerHire)
+
+	_, err := GenerateInterviewsPerHire([]*ent.FactHiringProcess{{ID: 1}})
+	require.Error(t, err)
+}
@@ -0,0 +1,344 @@
+package processing
+
+import (
+	"fmt"
+	"math"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+// MetricCatalogVersion must be incremented whenever the definition of a
+// metric of the catalog changes.
+const MetricCatalogVersion = 2
+
+const (
+	MetricAggregationSum   = "sum"
+	MetricAggregationCount = "count"
+	MetricAggregationMean  = "mean"
+	MetricAggregationNone  = "none"
+)
+
+// MetricDefinition is the single source of truth of what a number shown
+// by the dashboard means. `Evaluate`, when set, computes the metric for
+// a single `FactHiringProcess` with its edges loaded, counting the days
+// of durations with the given counter. `Aggregate`, when set, computes
+// the metric over all the facts instead, by group for the metrics
+// grouped by a dimension and under `MetricTotal` otherwise.
+type MetricDefinition struct {
+	Id          string `json:"id"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	Unit        string `json:"unit"`
+	Formula     string `json:"formula"`
+	Aggregation string `json:"aggregation"`
+	// name of the `analytics` measure that computes the metric, if any
+	Measure   string                                                                                               `json:"measure,omitempty"`
+	Evaluate  func(fact *ent.FactHiringProcess, days DayCounter) (*float64, error)                                 `json:"-"`
+	Aggregate func(facts []*ent.FactHiringProcess, days DayCounter, rule DeadlineRule) (map[string]float64, error) `json:"-"`
+}
+
+// MetricTotal is the group of the aggregate of the metrics that are not
+// grouped by a dimension.
+const MetricTotal = ""
+
+// ProcessStatusOf returns the status of a loaded `DimProcess`. Statuses
+// are scanned zero based, see `property.DimProcessStatus.Scan`.
+func ProcessStatusOf(process *ent.DimProcess) property.DimProcessStatus {
+	return property.DimProcessStatus(process.Status + 1)
+}
+
+// VacancyStatusOf returns the status of a loaded `DimVacancy`. Statuses
+// are scanned zero based, see `property.DimVacancyStatus.Scan`.
+func VacancyStatusOf(vacancy *ent.DimVacancy) property.DimVacancyStatus {
+	return property.DimVacancyStatus(vacancy.Status + 1)
+}
+
+func floatMetric(value float64) (*float64, error) {
+	return &value, nil
+}
+
+func factProcess(fact *ent.FactHiringProcess) (*ent.DimProcess, error) {
+	process, err := fact.Edges.DimProcessOrErr()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"`DimProcess` with ID %d of `FactHiringProcess` with ID %d not found: %w",
+			fact.DimProcessId,
+			fact.ID,
+			err,
+		)
+	}
+
+	return process, nil
+}
+
+func factVacancy(fact *ent.FactHiringProcess) (*ent.DimVacancy, error) {
+	vacancy, err := fact.Edges.DimVacancyOrErr()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"`DimVacancy` with ID %d of `FactHiringProcess` with ID %d not found: %w",
+			fact.DimVacancyId,
+			fact.ID,
+			err,
+		)
+	}
+
+	return vacancy, nil
+}
+
+func factCandidates(fact *ent.FactHiringProcess) ([]*ent.DimCandidate, error) {
+	vacancy, err := factVacancy(fact)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := vacancy.Edges.DimCandidatesOrErr()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"`DimCandidates` of `DimVacancy` with ID %d not found: %w",
+			vacancy.ID,
+			err,
+		)
+	}
+
+	return candidates, nil
+}
+
+var MetricNumPositions = MetricDefinition{
+	Id:          "numPositions",
+	Label:       "Positions",
+	Description: "Number of positions offered by the vacancy.",
+	Unit:        "positions",
+	Formula:     "dim_vacancy.numPositions",
+	Aggregation: MetricAggregationSum,
+	Measure:     "totalPositions",
+	Evaluate: func(fact *ent.FactHiringProcess, _ DayCounter) (*float64, error) {
+		vacancy, err := factVacancy(fact)
+		if err != nil {
+			return nil, err
+		}
+		return floatMetric(float64(vacancy.NumPositions))
+	},
+}
+
+var MetricNumCandidates = MetricDefinition{
+	Id:          "numCandidates",
+	Label:       "Candidates",
+	Description: "Number of candidates that applied to the vacancy.",
+	Unit:        "candidates",
+	Formula:     "fact_hiring_process.metTotalCandidatesApplied",
+	Aggregation: MetricAggregationSum,
+	Measure:     "totalCandidatesApplied",
+	Evaluate: func(fact *ent.FactHiringProcess, _ DayCounter) (*float64, error) {
+		return floatMetric(float64(fact.MetTotalCandidatesApplied))
+	},
+}
+
+var MetricCompetitionRate = MetricDefinition{
+	Id:          "competitionRate",
+	Label:       "Competition rate",
+	Description: "Candidates per position of the vacancy, empty when the vacancy has no positions.",
+	Unit:        "candidates per position",
+	Formula:     "fact_hiring_process.metTotalCandidatesApplied / dim_vacancy.numPositions",
+	Aggregation: MetricAggregationNone,
+	Evaluate: func(fact *ent.FactHiringProcess, _ DayCounter) (*float64, error) {
+		vacancy, err := factVacancy(fact)
+		if err != nil {
+			return nil, err
+		}
+		if vacancy.NumPositions <= 0 {
+			return nil, nil
+		}
+		return floatMetric(
+			float64(fact.MetTotalCandidatesApplied) / float64(vacancy.NumPositions),
+		)
+	},
+}
+
+var MetricNumInterviewed = MetricDefinition{
+	Id:          "numInterviewed",
+	Label:       "Interviewed",
+	Description: "Number of candidates interviewed for the vacancy.",
+	Unit:        "candidates",
+	Formula:     "fact_hiring_process.metTotalCandidatesInterviewed",
+	Aggregation: MetricAggregationSum,
+	Measure:     "totalCandidatesInterviewed",
+	Evaluate: func(fact *ent.FactHiringProcess, _ DayCounter) (*float64, error) {
+		return floatMetric(float64(fact.MetTotalCandidatesInterviewed))
+	},
+}
+
+var MetricNumHired = MetricDefinition{
+	Id:          "numHired",
+	Label:       "Hired",
+	Description: "Number of candidates hired for the vacancy.",
+	Unit:        "candidates",
+	Formula:     "fact_hiring_process.metTotalCandidatesHired",
+	Aggregation: MetricAggregationSum,
+	Measure:     "totalCandidatesHired",
+	Evaluate: func(fact *ent.FactHiringProcess, _ DayCounter) (*float64, error) {
+		return floatMetric(float64(fact.MetTotalCandidatesHired))
+	},
+}
+
+var MetricAverageHiringTime = MetricDefinition{
+	Id:          "averageHiringTime",
+	Label:       "Average hiring time",
+	Description: "Mean number of calendar or business days between the application and the hiring of the hired candidates, empty when no one was hired.",
+	Unit:        "days",
+	Formula:     "mean(dim_candidate.updatedAt - dim_candidate.applyDate) where dim_candidate.status = 'Hired'",
+	Aggregation: MetricAggregationMean,
+	Measure:     "averageHiringDays",
+	Evaluate: func(fact *ent.FactHiringProcess, days DayCounter) (*float64, error) {
+		candidates, err := factCandidates(fact)
+		if err != nil {
+			return nil, err
+		}
+
+		durations := HiredCandidateDurations(candidates, days)
+		if len(durations) == 0 {
+			return nil, nil
+		}
+
+		statistics, err := GenerateHiringTimeStatistics(
+			durations,
+			DefaultHiringTimeBucketWidth,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return floatMetric(float64(statistics.Mean))
+	},
+}
+
+var MetricNumFeedback = MetricDefinition{
+	Id:          "numFeedback",
+	Label:       "Feedback",
+	Description: "Number of feedbacks given to the candidates of the vacancy.",
+	Unit:        "feedbacks",
+	Formula:     "fact_hiring_process.metTotalFeedbackPositive + metTotalNeutral + metTotalNegative",
+	Aggregation: MetricAggregationSum,
+	Evaluate: func(fact *ent.FactHiringProcess, _ DayCounter) (*float64, error) {
+		return floatMetric(float64(
+			fact.MetTotalFeedbackPositive + fact.MetTotalNeutral + fact.MetTotalNegative,
+		))
+	},
+}
+
+var MetricProcessesByStatus = MetricDefinition{
+	Id:          "processesByStatus",
+	Label:       "Processes by status",
+	Description: "Number of hiring processes in each status: Open, In Progress and Closed.",
+	Unit:        "processes",
+	Formula:     "count(fact_hiring_process) group by dim_process.status",
+	Aggregation: MetricAggregationCount,
+	Aggregate: func(facts []*ent.FactHiringProcess, _ DayCounter, _ DeadlineRule) (map[string]float64, error) {
+		countByStatus := make(map[string]float64)
+		for _, fact := range facts {
+			process, err := factProcess(fact)
+			if err != nil {
+				return nil, err
+			}
+			countByStatus[ProcessStatusOf(process).String()]++
+		}
+		return countByStatus, nil
+	},
+}
+
+var MetricVacanciesByStatus = MetricDefinition{
+	Id:          "vacanciesByStatus",
+	Label:       "Vacancies by status",
+	Description: "Number of vacancies in each status: Open, In Analysis and Closed.",
+	Unit:        "vacancies",
+	Formula:     "count(fact_hiring_process) group by dim_vacancy.status",
+	Aggregation: MetricAggregationCount,
+	Aggregate: func(facts []*ent.FactHiringProcess, _ DayCounter, _ DeadlineRule) (map[string]float64, error) {
+		countByStatus := make(map[string]float64)
+		for _, fact := range facts {
+			vacancy, err := factVacancy(fact)
+			if err != nil {
+				return nil, err
+			}
+			countByStatus[VacancyStatusOf(vacancy).String()]++
+		}
+		return countByStatus, nil
+	},
+}
+
+var MetricApproachingDeadline = MetricDefinition{
+	Id:          "approachingDeadline",
+	Label:       "Approaching deadline",
+	Description: "Number of in progress processes with less than the deadline threshold (20% by default) of their duration remaining at the reference date.",
+	Unit:        "processes",
+	Formula:     "count(fact_hiring_process) where dim_process.status = 'In Progress' and (dim_process.finishDate - referenceDate) < threshold * (dim_process.finishDate - dim_process.initialDate)",
+	Aggregation: MetricAggregationCount,
+	Aggregate: func(facts []*ent.FactHiringProcess, _ DayCounter, rule DeadlineRule) (map[string]float64, error) {
+		count := 0.0
+		for _, fact := range facts {
+			process, err := factProcess(fact)
+			if err != nil {
+				return nil, err
+			}
+			if rule.IsApproachingDeadline(process) {
+				count++
+			}
+		}
+		return map[string]float64{MetricTotal: count}, nil
+	},
+}
+
+var MetricCardAverageHiringTime = MetricDefinition{
+	Id:          "cardAverageHiringTime",
+	Label:       "Average hiring time",
+	Description: "Mean number of calendar or business days between the application and the hiring of all hired candidates, truncated to whole days and zero when no one was hired.",
+	Unit:        "days",
+	Formula:     "trunc(mean(dim_candidate.updatedAt - dim_candidate.applyDate)) where dim_candidate.status = 'Hired'",
+	Aggregation: MetricAggregationMean,
+	Measure:     "averageHiringDays",
+	Aggregate: func(facts []*ent.FactHiringProcess, days DayCounter, _ DeadlineRule) (map[string]float64, error) {
+		totalHiringTime := 0.0
+		totalCandidates := 0
+		for _, fact := range facts {
+			candidates, err := factCandidates(fact)
+			if err != nil {
+				return nil, err
+			}
+			for _, duration := range HiredCandidateDurations(candidates, days) {
+				totalHiringTime += duration
+				totalCandidates++
+			}
+		}
+
+		if totalCandidates == 0 {
+			return map[string]float64{MetricTotal: 0}, nil
+		}
+		return map[string]float64{
+			MetricTotal: math.Trunc(totalHiringTime / float64(totalCandidates)),
+		}, nil
+	},
+}
+
+// MetricCatalog lists every metric shown by the dashboard and its table.
+var MetricCatalog = []*MetricDefinition{
+	&MetricNumPositions,
+	&MetricNumCandidates,
+	&MetricCompetitionRate,
+	&MetricNumInterviewed,
+	&MetricNumHired,
+	&MetricAverageHiringTime,
+	&MetricNumFeedback,
+	&MetricProcessesByStatus,
+	&MetricVacanciesByStatus,
+	&MetricApproachingDeadline,
+	&MetricCardAverageHiringTime,
+}
+
+func LookupMetric(id string) (*MetricDefinition, error) {
+	for _, metric := range MetricCatalog {
+		if metric.Id == id {
+			return metric, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown metric: %q", id)
+}
@@ -0,0 +1,111 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricCatalog(t *testing.T) {
+	t.Run("ids are unique and every metric is documented", func(t *testing.T) {
+		ids := make(map[string]bool)
+		for _, metric := range MetricCatalog {
+			require.False(t, ids[metric.Id], "duplicated metric %q", metric.Id)
+			ids[metric.Id] = true
+
+			require.NotEmpty(t, metric.Label)
+			require.NotEmpty(t, metric.Description)
+			require.NotEmpty(t, metric.Unit)
+			require.NotEmpty(t, metric.Formula)
+			require.NotEmpty(t, metric.Aggregation)
+			require.True(t, metric.Evaluate != nil || metric.Aggregate != nil, "metric %q has no evaluator", metric.Id)
+		}
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		metric, err := LookupMetric("competitionRate")
+		require.NoError(t, err)
+		require.Same(t, &MetricCompetitionRate, metric)
+
+		_, err = LookupMetric("unknown")
+		require.Error(t, err)
+	})
+
+	for i, testCase := range []struct {
+		Name     string
+		Metric   *MetricDefinition
+		Fact     *ent.FactHiringProcess
+		Expected float64
+	}{
+		{
+			Name:   "number of candidates",
+			Metric: &MetricNumCandidates,
+			Fact: &ent.FactHiringProcess{
+				MetTotalCandidatesApplied: 12,
+			},
+			Expected: 12,
+		},
+		{
+			Name:   "number of feedbacks sums every sentiment",
+			Metric: &MetricNumFeedback,
+			Fact: &ent.FactHiringProcess{
+				MetTotalFeedbackPositive: 3,
+				MetTotalNeutral:          2,
+				MetTotalNegative:         1,
+			},
+			Expected: 6,
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			value, err := testCase.Metric.Evaluate(testCase.Fact, DayCounter{})
+			require.NoError(t, err)
+
+			if testResult := value != nil && *value == testCase.Expected; !testResult {
+				t.Errorf("Test case %d failed", i)
+			}
+		})
+	}
+
+	t.Run("aggregates count the facts by status and deadline", func(t *testing.T) {
+		fact := func(processStatus property.DimProcessStatus, vacancyStatus property.DimVacancyStatus) *ent.FactHiringProcess {
+			return &ent.FactHiringProcess{
+				Edges: ent.FactHiringProcessEdges{
+					DimProcess: newTestProcess(processStatus, 1),
+					// statuses are scanned zero based
+					DimVacancy: &ent.DimVacancy{Status: vacancyStatus - 1},
+				},
+			}
+		}
+		facts := []*ent.FactHiringProcess{
+			fact(property.DimProcessStatusInProgress, property.DimVacancyStatusOpen),
+			fact(property.DimProcessStatusInProgress, property.DimVacancyStatusClosed),
+			fact(property.DimProcessStatusOpen, property.DimVacancyStatusOpen),
+		}
+		rule := NewDeadlineRule(time.Date(2024, 8, 28, 0, 0, 0, 0, time.UTC))
+
+		processesByStatus, err := MetricProcessesByStatus.Aggregate(facts, DayCounter{}, rule)
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{"In Progress": 2, "Open": 1}, processesByStatus)
+
+		vacanciesByStatus, err := MetricVacanciesByStatus.Aggregate(facts, DayCounter{}, rule)
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{"Open": 2, "Closed": 1}, vacanciesByStatus)
+
+		approachingDeadline, err := MetricApproachingDeadline.Aggregate(facts, DayCounter{}, rule)
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{MetricTotal: 2}, approachingDeadline)
+
+		cards, err := ComputingCardsInfo(facts, rule, DayCounter{})
+		require.Error(t, err, "the candidates of the vacancies are not loaded")
+		require.Zero(t, cards)
+	})
+
+	t.Run("metrics over unloaded edges fail", func(t *testing.T) {
+		_, err := MetricCompetitionRate.Evaluate(&ent.FactHiringProcess{}, DayCounter{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,125 @@
+package processing
+
+import (
+	"fmt"
+	"time"
+)
+
+type ComparisonPeriod string
+
+const (
+	// the period of equal length that ends the day before the current one
+	ComparisonPeriodPrevious ComparisonPeriod = "previousPeriod"
+	// the same period one year before the current one
+	ComparisonPeriodLastYear ComparisonPeriod = "lastYear"
+)
+
+// ShiftPeriod returns the start and end dates of the period that the
+// inclusive range [start, end] is compared against.
+func ShiftPeriod(
+	comparison ComparisonPeriod,
+	start, end time.Time,
+) (time.Time, time.Time, error) {
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf(
+			"end date %s is before start date %s",
+			end.Format(time.DateOnly),
+			start.Format(time.DateOnly),
+		)
+	}
+
+	switch comparison {
+	case ComparisonPeriodPrevious:
+		length := end.Sub(start)
+		previousEnd := start.AddDate(0, 0, -1)
+		return previousEnd.Add(-length), previousEnd, nil
+	case ComparisonPeriodLastYear:
+		return start.AddDate(-1, 0, 0), end.AddDate(-1, 0, 0), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf(
+			"invalid comparison period: %q",
+			comparison,
+		)
+	}
+}
+
+type MetricDelta struct {
+	Current  float64 `json:"current"`
+	Previous float64 `json:"previous"`
+	Absolute float64 `json:"absolute"`
+	// nil when the previous value is zero
+	Percentage *float64 `json:"percentage"`
+}
+
+func ComputeMetricDelta(current, previous float64) MetricDelta {
+	delta := MetricDelta{
+		Current:  current,
+		Previous: previous,
+		Absolute: current - previous,
+	}
+
+	if previous != 0 {
+		percentage := (current - previous) / previous * 100
+		delta.Percentage = &percentage
+	}
+
+	return delta
+}
+
+type CardInfosComparison struct {
+	Open                MetricDelta `json:"open"`
+	InProgress          MetricDelta `json:"inProgress"`
+	Closed              MetricDelta `json:"closed"`
+	ApproachingDeadline MetricDelta `json:"approachingDeadline"`
+	AverageHiringTime   MetricDelta `json:"averageHiringTime"`
+}
+
+func CompareCardInfos(current, previous CardInfos) CardInfosComparison {
+	return CardInfosComparison{
+		Open: ComputeMetricDelta(
+			float64(current.Open),
+			float64(previous.Open),
+		),
+		InProgress: ComputeMetricDelta(
+			float64(current.InProgress),
+			float64(previous.InProgress),
+		),
+		Closed: ComputeMetricDelta(
+			float64(current.Closed),
+			float64(previous.Closed),
+		),
+		ApproachingDeadline: ComputeMetricDelta(
+			float64(current.ApproachingDeadline),
+			float64(previous.ApproachingDeadline),
+		),
+		AverageHiringTime: ComputeMetricDelta(
+			float64(current.AverageHiringTime),
+			float64(previous.AverageHiringTime),
+		),
+	}
+}
+
+type VacancyStatusSummaryComparison struct {
+	Open      MetricDelta `json:"open"`
+	Analyzing MetricDelta `json:"analyzing"`
+	Closed    MetricDelta `json:"closed"`
+}
+
+func CompareVacancyStatusSummary(
+	current, previous VacancyStatusSummary,
+) VacancyStatusSummaryComparison {
+	return VacancyStatusSummaryComparison{
+		Open: ComputeMetricDelta(
+			float64(current.Open),
+			float64(previous.Open),
+		),
+		Analyzing: ComputeMetricDelta(
+			float64(current.Analyzing),
+			float64(previous.Analyzing),
+		),
+		Closed: ComputeMetricDelta(
+			float64(current.Closed),
+			float64(previous.Closed),
+		),
+	}
+}
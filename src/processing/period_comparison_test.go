@@ -0,0 +1,82 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShiftPeriod(t *testing.T) {
+	for i, testCase := range []struct {
+		Name          string
+		Comparison    ComparisonPeriod
+		Start         string
+		End           string
+		ExpectedError bool
+		ExpectedStart string
+		ExpectedEnd   string
+	}{
+		{
+			Name:       "previous period of one month",
+			Comparison: ComparisonPeriodPrevious,
+			Start:      "2024-07-01", End: "2024-07-31",
+			ExpectedStart: "2024-05-31",
+			ExpectedEnd:   "2024-06-30",
+		},
+		{
+			Name:       "previous period of a single day",
+			Comparison: ComparisonPeriodPrevious,
+			Start:      "2024-03-01", End: "2024-03-01",
+			ExpectedStart: "2024-02-29",
+			ExpectedEnd:   "2024-02-29",
+		},
+		{
+			Name:       "same period last year",
+			Comparison: ComparisonPeriodLastYear,
+			Start:      "2024-07-01", End: "2024-07-31",
+			ExpectedStart: "2023-07-01",
+			ExpectedEnd:   "2023-07-31",
+		},
+		{
+			Name:       "inverted range",
+			Comparison: ComparisonPeriodPrevious,
+			Start:      "2024-07-31", End: "2024-07-01",
+			ExpectedError: true,
+		},
+		{
+			Name:       "invalid comparison",
+			Comparison: "nextYear",
+			Start:      "2024-07-01", End: "2024-07-31",
+			ExpectedError: true,
+		},
+	} {
+		if testResult := t.Run(testCase.Name, func(t *testing.T) {
+			start, _ := time.Parse(time.DateOnly, testCase.Start)
+			end, _ := time.Parse(time.DateOnly, testCase.End)
+
+			shiftedStart, shiftedEnd, err := ShiftPeriod(testCase.Comparison, start, end)
+			if testCase.ExpectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.ExpectedStart, shiftedStart.Format(time.DateOnly))
+			require.Equal(t, testCase.ExpectedEnd, shiftedEnd.Format(time.DateOnly))
+		}); !testResult {
+			t.Errorf("Test case %d failed", i)
+		}
+	}
+}
+
+func TestComputeMetricDelta(t *testing.T) {
+	delta := ComputeMetricDelta(15, 10)
+	require.Equal(t, float64(5), delta.Absolute)
+	require.NotNil(t, delta.Percentage)
+	require.Equal(t, float64(50), *delta.Percentage)
+
+	delta = ComputeMetricDelta(3, 0)
+	require.Equal(t, float64(3), delta.Absolute)
+	require.Nil(t, delta.Percentage)
+}
@@ -0,0 +1,106 @@
+package processing
+
+import (
+	"sort"
+	"time"
+
+	"api5back/ent"
+)
+
+// PipelineGranularityDay samples every snapshot of the pipeline trend,
+// in addition to the month and week granularities of the cohorts.
+const PipelineGranularityDay CohortGranularity = "day"
+
+type PipelineCounts struct {
+	NumOpenProcesses        int `json:"numOpenProcesses"`
+	NumInProgressProcesses  int `json:"numInProgressProcesses"`
+	NumClosedProcesses      int `json:"numClosedProcesses"`
+	NumOpenVacancies        int `json:"numOpenVacancies"`
+	NumInAnalysisVacancies  int `json:"numInAnalysisVacancies"`
+	NumClosedVacancies      int `json:"numClosedVacancies"`
+	NumCandidatesInAnalysis int `json:"numCandidatesInAnalysis"`
+	NumCandidatesInterview  int `json:"numCandidatesInterview"`
+	NumCandidatesHired      int `json:"numCandidatesHired"`
+	NumCandidatesRejected   int `json:"numCandidatesRejected"`
+}
+
+func (counts *PipelineCounts) add(snapshot *ent.FactPipelineSnapshot) {
+	counts.NumOpenProcesses += snapshot.NumOpenProcesses
+	counts.NumInProgressProcesses += snapshot.NumInProgressProcesses
+	counts.NumClosedProcesses += snapshot.NumClosedProcesses
+	counts.NumOpenVacancies += snapshot.NumOpenVacancies
+	counts.NumInAnalysisVacancies += snapshot.NumInAnalysisVacancies
+	counts.NumClosedVacancies += snapshot.NumClosedVacancies
+	counts.NumCandidatesInAnalysis += snapshot.NumCandidatesInAnalysis
+	counts.NumCandidatesInterview += snapshot.NumCandidatesInterview
+	counts.NumCandidatesHired += snapshot.NumCandidatesHired
+	counts.NumCandidatesRejected += snapshot.NumCandidatesRejected
+}
+
+// PipelineTrendPoint holds the pipeline of a period as of `Date`, the
+// first date of the period with a snapshot.
+type PipelineTrendPoint struct {
+	Period string `json:"period"`
+	Date   string `json:"date"`
+	PipelineCounts
+}
+
+type PipelineTrend struct {
+	Granularity CohortGranularity    `json:"granularity"`
+	Points      []PipelineTrendPoint `json:"points"`
+}
+
+// GeneratePipelineTrend sums the snapshots of the first snapshot date of
+// each period, e.g. the pipeline on the first of each month, over the
+// departments and recruiters of the snapshots. Periods without
+// snapshots are left out.
+func GeneratePipelineTrend(
+	snapshots []*ent.FactPipelineSnapshot,
+	granularity CohortGranularity,
+) (PipelineTrend, error) {
+	if granularity != PipelineGranularityDay {
+		if _, err := periodKey(time.Time{}, granularity); err != nil {
+			return PipelineTrend{}, err
+		}
+	}
+
+	trend := PipelineTrend{
+		Granularity: granularity,
+		Points:      []PipelineTrendPoint{},
+	}
+
+	points := make(map[string]*PipelineTrendPoint)
+	for _, snapshot := range snapshots {
+		if snapshot.SnapshotDate == nil || !snapshot.SnapshotDate.Valid {
+			continue
+		}
+
+		date := snapshot.SnapshotDate.Time.Format("2006-01-02")
+		period := date
+		if granularity != PipelineGranularityDay {
+			period, _ = periodKey(snapshot.SnapshotDate.Time, granularity)
+		}
+
+		point, ok := points[period]
+		if !ok || date < point.Date {
+			point = &PipelineTrendPoint{
+				Period: period,
+				Date:   date,
+			}
+			points[period] = point
+		}
+
+		if date == point.Date {
+			point.add(snapshot)
+		}
+	}
+
+	for _, point := range points {
+		trend.Points = append(trend.Points, *point)
+	}
+	sort.Slice(trend.Points, func(i, j int) bool {
+		return trend.Points[i].Date < trend.Points[j].Date
+	})
+
+	return trend, nil
+}
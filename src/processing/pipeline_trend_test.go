@@ -0,0 +1,56 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotOn(month time.Month, day, numOpenVacancies int) *ent.FactPipelineSnapshot {
+	return &ent.FactPipelineSnapshot{
+		SnapshotDate:     &pgtype.Date{Time: time.Date(2024, month, day, 0, 0, 0, 0, time.UTC), Valid: true},
+		NumOpenVacancies: numOpenVacancies,
+	}
+}
+
+func TestGeneratePipelineTrend(t *testing.T) {
+	snapshots := []*ent.FactPipelineSnapshot{
+		snapshotOn(7, 2, 5),
+		snapshotOn(7, 1, 3),
+		snapshotOn(7, 1, 4),
+		snapshotOn(8, 15, 1),
+		snapshotOn(8, 20, 8),
+	}
+
+	trend, err := GeneratePipelineTrend(snapshots, CohortGranularityMonth)
+	require.NoError(t, err)
+	require.Equal(t, []PipelineTrendPoint{
+		{
+			Period:         "2024-07",
+			Date:           "2024-07-01",
+			PipelineCounts: PipelineCounts{NumOpenVacancies: 7},
+		},
+		{
+			Period:         "2024-08",
+			Date:           "2024-08-15",
+			PipelineCounts: PipelineCounts{NumOpenVacancies: 1},
+		},
+	}, trend.Points)
+
+	daily, err := GeneratePipelineTrend(snapshots, PipelineGranularityDay)
+	require.NoError(t, err)
+	require.Len(t, daily.Points, 4)
+	require.Equal(t, "2024-07-02", daily.Points[1].Period)
+	require.Equal(t, 5, daily.Points[1].NumOpenVacancies)
+
+	_, err = GeneratePipelineTrend(nil, "year")
+	require.Error(t, err)
+
+	empty, err := GeneratePipelineTrend(nil, CohortGranularityWeek)
+	require.NoError(t, err)
+	require.Empty(t, empty.Points)
+}
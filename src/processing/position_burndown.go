@@ -0,0 +1,418 @@
+package processing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+// FillStatus flags how the hires of a vacancy compare with its
+// positions.
+type FillStatus string
+
+const (
+	// positions remain and the vacancy is still open
+	FillStatusOpen FillStatus = "open"
+	// every position was hired
+	FillStatusFilled FillStatus = "filled"
+	// more candidates were hired than there are positions
+	FillStatusOverFilled FillStatus = "overFilled"
+	// the vacancy closed, or its closing date passed, with positions
+	// remaining
+	FillStatusUnderFilled FillStatus = "underFilled"
+)
+
+// BurndownPoint holds the positions remaining at `Date`. `Ideal` is the
+// straight line from every position open at the opening date to none at
+// the closing date, nil when a vacancy has no closing date.
+type BurndownPoint struct {
+	Date      string   `json:"date"`
+	Remaining int      `json:"remaining"`
+	Ideal     *float64 `json:"ideal"`
+}
+
+// Burndown is the series of positions remaining, with a point at the
+// start and end dates, at each hire and at the reference date.
+// `Remaining` is negative when more candidates were hired than there
+// are positions.
+type Burndown struct {
+	NumPositions int    `json:"numPositions"`
+	NumHired     int    `json:"numHired"`
+	Remaining    int    `json:"remaining"`
+	StartDate    string `json:"startDate"`
+	// empty when a vacancy has no closing date
+	EndDate string `json:"endDate"`
+	// hires per day from the start date up to the reference date, or
+	// the end date once it passed; nil before the first hire
+	HireRate *float64 `json:"hireRate"`
+	// the date the last position was filled or, while positions remain,
+	// the date they would be at the current hire rate; nil when nobody
+	// was hired yet
+	ProjectedCompletionDate *string         `json:"projectedCompletionDate"`
+	Points                  []BurndownPoint `json:"points"`
+}
+
+type VacancyBurndown struct {
+	VacancyId    int        `json:"vacancyId"`
+	VacancyTitle string     `json:"vacancyTitle"`
+	FillStatus   FillStatus `json:"fillStatus"`
+	// the projected completion date is after the closing date
+	BehindSchedule bool `json:"behindSchedule"`
+	Burndown
+}
+
+type ProcessBurndown struct {
+	ProcessId         int    `json:"processId"`
+	ProcessTitle      string `json:"processTitle"`
+	NumOverFilled     int    `json:"numOverFilled"`
+	NumUnderFilled    int    `json:"numUnderFilled"`
+	NumBehindSchedule int    `json:"numBehindSchedule"`
+	Burndown
+	Vacancies []VacancyBurndown `json:"vacancies"`
+}
+
+type PositionBurndown struct {
+	ReferenceDate string            `json:"referenceDate"`
+	Processes     []ProcessBurndown `json:"processes"`
+}
+
+// burndownPlan is what the burndown of a vacancy is built from. `end`
+// is nil when the vacancy has no closing date, and `hires` are sorted.
+type burndownPlan struct {
+	numPositions int
+	start        time.Time
+	end          *time.Time
+	hires        []time.Time
+}
+
+func (plan burndownPlan) remainingAt(date time.Time) int {
+	hired := sort.Search(len(plan.hires), func(i int) bool {
+		return plan.hires[i].After(date)
+	})
+	return plan.numPositions - hired
+}
+
+// idealAt is the ideal line of a plan with an end date.
+func (plan burndownPlan) idealAt(date time.Time) float64 {
+	if !date.After(plan.start) {
+		return float64(plan.numPositions)
+	}
+
+	if !date.Before(*plan.end) {
+		return 0
+	}
+
+	elapsed := date.Sub(plan.start).Hours()
+	total := plan.end.Sub(plan.start).Hours()
+	return float64(plan.numPositions) * (1 - elapsed/total)
+}
+
+// GeneratePositionBurndown builds the burndown of each vacancy of the
+// facts, from its `openingDate` to its `closingDate`, and of each process
+// over its vacancies, as of the reference date. Hires are dated by the
+// `updatedAt` of the hired candidates; the ones without dates are left
+// out. Processes and their vacancies are sorted by title.
+func GeneratePositionBurndown(
+	factHiringProcesses []*ent.FactHiringProcess,
+	referenceDate time.Time,
+) (PositionBurndown, error) {
+	referenceDate = time.Date(
+		referenceDate.Year(), referenceDate.Month(), referenceDate.Day(),
+		0, 0, 0, 0, time.UTC,
+	)
+
+	type processGroup struct {
+		process   *ent.DimProcess
+		plans     []burndownPlan
+		vacancies []VacancyBurndown
+	}
+
+	var groups []*processGroup
+	groupsByProcess := make(map[int]*processGroup)
+	visitedVacancies := make(map[int]bool)
+
+	for _, factHiringProcess := range factHiringProcesses {
+		process, err := factHiringProcess.Edges.DimProcessOrErr()
+		if err != nil {
+			return PositionBurndown{}, fmt.Errorf(
+				"`DimProcess` of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		vacancy, err := factHiringProcess.Edges.DimVacancyOrErr()
+		if err != nil {
+			return PositionBurndown{}, fmt.Errorf(
+				"`DimVacancy` of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		if visitedVacancies[vacancy.ID] {
+			continue
+		}
+		visitedVacancies[vacancy.ID] = true
+
+		if vacancy.OpeningDate == nil || !vacancy.OpeningDate.Valid {
+			continue
+		}
+
+		candidates, err := vacancy.Edges.DimCandidatesOrErr()
+		if err != nil {
+			return PositionBurndown{}, fmt.Errorf(
+				"`DimCandidates` of `DimVacancy` with ID %d not found: %w",
+				vacancy.ID,
+				err,
+			)
+		}
+
+		group, ok := groupsByProcess[process.ID]
+		if !ok {
+			group = &processGroup{process: process}
+			groupsByProcess[process.ID] = group
+			groups = append(groups, group)
+		}
+
+		plan := vacancyBurndownPlan(vacancy, candidates)
+		group.plans = append(group.plans, plan)
+		group.vacancies = append(
+			group.vacancies,
+			vacancyBurndown(vacancy, plan, referenceDate),
+		)
+	}
+
+	result := PositionBurndown{
+		ReferenceDate: referenceDate.Format(time.DateOnly),
+		Processes:     []ProcessBurndown{},
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group.vacancies, func(i, j int) bool {
+			return group.vacancies[i].VacancyTitle < group.vacancies[j].VacancyTitle
+		})
+
+		processBurndown := ProcessBurndown{
+			ProcessId:    group.process.ID,
+			ProcessTitle: group.process.Title,
+			Burndown:     generateBurndown(group.plans, referenceDate),
+			Vacancies:    group.vacancies,
+		}
+		for _, vacancy := range group.vacancies {
+			switch vacancy.FillStatus {
+			case FillStatusOverFilled:
+				processBurndown.NumOverFilled++
+			case FillStatusUnderFilled:
+				processBurndown.NumUnderFilled++
+			}
+			if vacancy.BehindSchedule {
+				processBurndown.NumBehindSchedule++
+			}
+		}
+
+		result.Processes = append(result.Processes, processBurndown)
+	}
+
+	sort.SliceStable(result.Processes, func(i, j int) bool {
+		return result.Processes[i].ProcessTitle < result.Processes[j].ProcessTitle
+	})
+
+	return result, nil
+}
+
+func vacancyBurndownPlan(
+	vacancy *ent.DimVacancy,
+	candidates []*ent.DimCandidate,
+) burndownPlan {
+	plan := burndownPlan{
+		numPositions: vacancy.NumPositions,
+		start:        vacancy.OpeningDate.Time,
+	}
+
+	if vacancy.ClosingDate != nil && vacancy.ClosingDate.Valid &&
+		vacancy.ClosingDate.Time.After(plan.start) {
+		end := vacancy.ClosingDate.Time
+		plan.end = &end
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Status != property.DimCandidateStatusHired ||
+			candidate.UpdatedAt == nil || !candidate.UpdatedAt.Valid {
+			continue
+		}
+
+		hire := candidate.UpdatedAt.Time
+		if hire.Before(plan.start) {
+			hire = plan.start
+		}
+		plan.hires = append(plan.hires, hire)
+	}
+
+	sort.Slice(plan.hires, func(i, j int) bool {
+		return plan.hires[i].Before(plan.hires[j])
+	})
+
+	return plan
+}
+
+func vacancyBurndown(
+	vacancy *ent.DimVacancy,
+	plan burndownPlan,
+	referenceDate time.Time,
+) VacancyBurndown {
+	burndown := generateBurndown([]burndownPlan{plan}, referenceDate)
+
+	row := VacancyBurndown{
+		VacancyId:    vacancy.ID,
+		VacancyTitle: vacancy.Title,
+		FillStatus:   FillStatusOpen,
+		Burndown:     burndown,
+	}
+
+	closed := VacancyStatusOf(vacancy) == property.DimVacancyStatusClosed ||
+		(plan.end != nil && plan.end.Before(referenceDate))
+
+	switch {
+	case burndown.Remaining < 0:
+		row.FillStatus = FillStatusOverFilled
+	case burndown.Remaining == 0:
+		row.FillStatus = FillStatusFilled
+	case closed:
+		row.FillStatus = FillStatusUnderFilled
+	}
+
+	if burndown.Remaining > 0 && plan.end != nil &&
+		burndown.ProjectedCompletionDate != nil {
+		row.BehindSchedule = *burndown.ProjectedCompletionDate > plan.end.Format(time.DateOnly)
+	}
+
+	return row
+}
+
+// generateBurndown sums the plans into a single series. It starts at the
+// earliest start date and ends at the latest end date, and it has an
+// ideal line only when every plan has an end date.
+func generateBurndown(
+	plans []burndownPlan,
+	referenceDate time.Time,
+) Burndown {
+	burndown := Burndown{
+		Points: []BurndownPoint{},
+	}
+	if len(plans) == 0 {
+		return burndown
+	}
+
+	start := plans[0].start
+	var end *time.Time
+	hasIdeal := true
+	var hires []time.Time
+
+	for _, plan := range plans {
+		burndown.NumPositions += plan.numPositions
+		burndown.NumHired += len(plan.hires)
+		hires = append(hires, plan.hires...)
+
+		if plan.start.Before(start) {
+			start = plan.start
+		}
+
+		if plan.end == nil {
+			hasIdeal = false
+		} else if end == nil || plan.end.After(*end) {
+			end = plan.end
+		}
+	}
+
+	burndown.Remaining = burndown.NumPositions - burndown.NumHired
+	burndown.StartDate = start.Format(time.DateOnly)
+	if !hasIdeal {
+		end = nil
+	}
+	if end != nil {
+		burndown.EndDate = end.Format(time.DateOnly)
+	}
+
+	dates := []time.Time{start}
+	dates = append(dates, hires...)
+	if end != nil {
+		dates = append(dates, *end)
+	}
+	if referenceDate.After(start) && (end == nil || referenceDate.Before(*end)) {
+		dates = append(dates, referenceDate)
+	}
+
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+
+	for i, date := range dates {
+		if i > 0 && date.Equal(dates[i-1]) {
+			continue
+		}
+
+		point := BurndownPoint{
+			Date: date.Format(time.DateOnly),
+		}
+
+		ideal := 0.0
+		for _, plan := range plans {
+			point.Remaining += plan.remainingAt(date)
+			if hasIdeal {
+				ideal += plan.idealAt(date)
+			}
+		}
+		if hasIdeal {
+			point.Ideal = &ideal
+		}
+
+		burndown.Points = append(burndown.Points, point)
+	}
+
+	if burndown.NumHired == 0 {
+		return burndown
+	}
+
+	// the rate is measured up to the reference date, or up to the end
+	// date once it passed, as no more hires are expected after it
+	rateEnd := referenceDate
+	if end != nil && end.Before(rateEnd) {
+		rateEnd = *end
+	}
+	sort.Slice(hires, func(i, j int) bool {
+		return hires[i].Before(hires[j])
+	})
+	if lastHire := hires[len(hires)-1]; lastHire.After(rateEnd) {
+		rateEnd = lastHire
+	}
+
+	elapsedDays := math.Max(rateEnd.Sub(start).Hours()/24, 1)
+	hireRate := float64(burndown.NumHired) / elapsedDays
+	burndown.HireRate = &hireRate
+
+	if burndown.Remaining <= 0 {
+		for _, point := range burndown.Points {
+			if point.Remaining <= 0 {
+				completion := point.Date
+				burndown.ProjectedCompletionDate = &completion
+				break
+			}
+		}
+		return burndown
+	}
+
+	projectionStart := referenceDate
+	if rateEnd.After(projectionStart) {
+		projectionStart = rateEnd
+	}
+	remainingDays := int(math.Ceil(float64(burndown.Remaining) / hireRate))
+	completion := projectionStart.AddDate(0, 0, remainingDays).Format(time.DateOnly)
+	burndown.ProjectedCompletionDate = &completion
+
+	return burndown
+}
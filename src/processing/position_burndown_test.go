@@ -0,0 +1,143 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func burndownDate(month time.Month, day int) time.Time {
+	return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func newTestBurndownVacancy(numPositions int, closed bool) *ent.DimVacancy {
+	vacancy := &ent.DimVacancy{
+		ID:           1,
+		Title:        "Backend Developer",
+		NumPositions: numPositions,
+		OpeningDate:  &pgtype.Date{Time: burndownDate(time.March, 1), Valid: true},
+		ClosingDate:  &pgtype.Date{Time: burndownDate(time.March, 31), Valid: true},
+	}
+	if closed {
+		// statuses are scanned zero based
+		vacancy.Status = property.DimVacancyStatusClosed - 1
+	}
+	return vacancy
+}
+
+func burndownCandidates() []*ent.DimCandidate {
+	return []*ent.DimCandidate{
+		newTestCandidate(property.DimCandidateStatusHired, "2024-02-20", "2024-03-11"),
+		newTestCandidate(property.DimCandidateStatusRejected, "2024-02-20", "2024-03-04"),
+		newTestCandidate(property.DimCandidateStatusHired, "2024-02-20", "2024-03-06"),
+	}
+}
+
+func TestVacancyBurndown(t *testing.T) {
+	ideal := func(value float64) *float64 {
+		return &value
+	}
+
+	t.Run("projects the completion from the hire rate", func(t *testing.T) {
+		vacancy := newTestBurndownVacancy(3, false)
+		plan := vacancyBurndownPlan(vacancy, burndownCandidates())
+		row := vacancyBurndown(vacancy, plan, burndownDate(time.March, 16))
+
+		require.Equal(t, FillStatusOpen, row.FillStatus)
+		require.False(t, row.BehindSchedule)
+		require.Equal(t, 3, row.NumPositions)
+		require.Equal(t, 2, row.NumHired)
+		require.Equal(t, 1, row.Remaining)
+		require.Equal(t, "2024-03-01", row.StartDate)
+		require.Equal(t, "2024-03-31", row.EndDate)
+		require.InDelta(t, 2.0/15, *row.HireRate, 1e-9)
+		require.Equal(t, "2024-03-24", *row.ProjectedCompletionDate)
+		require.Equal(t, []BurndownPoint{
+			{Date: "2024-03-01", Remaining: 3, Ideal: ideal(3)},
+			{Date: "2024-03-06", Remaining: 2, Ideal: ideal(2.5)},
+			{Date: "2024-03-11", Remaining: 1, Ideal: ideal(2)},
+			{Date: "2024-03-16", Remaining: 1, Ideal: ideal(1.5)},
+			{Date: "2024-03-31", Remaining: 1, Ideal: ideal(0)},
+		}, row.Points)
+	})
+
+	t.Run("flags positions remaining after the closing date", func(t *testing.T) {
+		vacancy := newTestBurndownVacancy(3, false)
+		plan := vacancyBurndownPlan(vacancy, burndownCandidates())
+		row := vacancyBurndown(vacancy, plan, burndownDate(time.April, 10))
+
+		require.Equal(t, FillStatusUnderFilled, row.FillStatus)
+		require.True(t, row.BehindSchedule)
+		require.InDelta(t, 1.0/15, *row.HireRate, 1e-9)
+		require.Equal(t, "2024-04-25", *row.ProjectedCompletionDate)
+		require.Len(t, row.Points, 4)
+	})
+
+	t.Run("flags closed vacancies with positions remaining", func(t *testing.T) {
+		vacancy := newTestBurndownVacancy(3, true)
+		plan := vacancyBurndownPlan(vacancy, burndownCandidates())
+		row := vacancyBurndown(vacancy, plan, burndownDate(time.March, 16))
+
+		require.Equal(t, FillStatusUnderFilled, row.FillStatus)
+	})
+
+	t.Run("flags more hires than positions", func(t *testing.T) {
+		vacancy := newTestBurndownVacancy(1, true)
+		plan := vacancyBurndownPlan(vacancy, burndownCandidates())
+		row := vacancyBurndown(vacancy, plan, burndownDate(time.March, 16))
+
+		require.Equal(t, FillStatusOverFilled, row.FillStatus)
+		require.False(t, row.BehindSchedule)
+		require.Equal(t, -1, row.Remaining)
+		require.Equal(t, "2024-03-06", *row.ProjectedCompletionDate)
+	})
+
+	t.Run("no hires has no projection", func(t *testing.T) {
+		vacancy := newTestBurndownVacancy(2, false)
+		plan := vacancyBurndownPlan(vacancy, nil)
+		row := vacancyBurndown(vacancy, plan, burndownDate(time.March, 16))
+
+		require.Nil(t, row.HireRate)
+		require.Nil(t, row.ProjectedCompletionDate)
+		require.False(t, row.BehindSchedule)
+	})
+}
+
+func TestGenerateBurndown(t *testing.T) {
+	end := burndownDate(time.March, 31)
+	plans := []burndownPlan{
+		{
+			numPositions: 2,
+			start:        burndownDate(time.March, 1),
+			end:          &end,
+			hires:        []time.Time{burndownDate(time.March, 6)},
+		},
+		{
+			numPositions: 1,
+			start:        burndownDate(time.March, 11),
+			hires:        []time.Time{burndownDate(time.March, 21)},
+		},
+	}
+
+	burndown := generateBurndown(plans, burndownDate(time.April, 10))
+
+	require.Equal(t, 3, burndown.NumPositions)
+	require.Equal(t, 2, burndown.NumHired)
+	require.Equal(t, "2024-03-01", burndown.StartDate)
+	require.Empty(t, burndown.EndDate)
+
+	var dates []string
+	for _, point := range burndown.Points {
+		require.Nil(t, point.Ideal)
+		dates = append(dates, point.Date)
+	}
+	require.Equal(t, []string{"2024-03-01", "2024-03-06", "2024-03-21", "2024-04-10"}, dates)
+	require.Equal(t, 1, burndown.Points[len(burndown.Points)-1].Remaining)
+
+	require.Equal(t, Burndown{Points: []BurndownPoint{}}, generateBurndown(nil, end))
+}
@@ -0,0 +1,324 @@
+package processing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TimelineMarkerType names a milestone of an interval of the timeline.
+type TimelineMarkerType string
+
+const (
+	// the first candidate was hired
+	TimelineMarkerFirstHire TimelineMarkerType = "firstHire"
+	// the remaining duration fell under the deadline threshold, see
+	// `DeadlineRule`
+	TimelineMarkerDeadlineApproaching TimelineMarkerType = "deadlineApproaching"
+	// the end date passed before the interval was closed
+	TimelineMarkerDeadlineMissed TimelineMarkerType = "deadlineMissed"
+)
+
+type TimelineMarker struct {
+	Type TimelineMarkerType `json:"type"`
+	Date string             `json:"date"`
+}
+
+// TimelineInterval is a bar of the Gantt chart. `EndDate` is nil when
+// the process or vacancy has no end date. `Owner` is the recruiter of
+// the facts, their names joined by ", " when there are many.
+type TimelineInterval struct {
+	Id        int              `json:"id"`
+	Title     string           `json:"title"`
+	StartDate string           `json:"startDate"`
+	EndDate   *string          `json:"endDate"`
+	Status    string           `json:"status"`
+	Owner     string           `json:"owner"`
+	Markers   []TimelineMarker `json:"markers"`
+}
+
+type TimelineProcess struct {
+	TimelineInterval
+	Vacancies []TimelineInterval `json:"vacancies"`
+}
+
+// GenerateProcessTimeline lays out the processes of the facts, from
+// their `initialDate` to their `finishDate`, with their vacancies, from
+// their `openingDate` to their `closingDate`, sorted by start date and
+// title. Hires are dated by the `updatedAt` of the hired candidates,
+// and the deadline markers of both processes and vacancies follow the
+// threshold of the department of the process.
+func GenerateProcessTimeline(
+	factHiringProcesses []*ent.FactHiringProcess,
+	rule DeadlineRule,
+) ([]TimelineProcess, error) {
+	type processGroup struct {
+		process   *ent.DimProcess
+		owners    []string
+		vacancies []TimelineInterval
+		visited   map[int]bool
+	}
+
+	var groups []*processGroup
+	groupsByProcess := make(map[int]*processGroup)
+
+	for _, factHiringProcess := range factHiringProcesses {
+		process, err := factHiringProcess.Edges.DimProcessOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimProcess` of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		vacancy, err := factHiringProcess.Edges.DimVacancyOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimVacancy` of `FactHiringProcess` with ID %d not found: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+
+		owner := ""
+		if user, err := factHiringProcess.Edges.DimUserOrErr(); err == nil {
+			owner = user.Name
+		}
+
+		group, ok := groupsByProcess[process.ID]
+		if !ok {
+			group = &processGroup{
+				process: process,
+				visited: make(map[int]bool),
+			}
+			groupsByProcess[process.ID] = group
+			groups = append(groups, group)
+		}
+		group.owners = append(group.owners, owner)
+
+		if group.visited[vacancy.ID] {
+			continue
+		}
+		group.visited[vacancy.ID] = true
+
+		candidates, err := vacancy.Edges.DimCandidatesOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimCandidates` of `DimVacancy` with ID %d not found: %w",
+				vacancy.ID,
+				err,
+			)
+		}
+
+		group.vacancies = append(
+			group.vacancies,
+			timelineVacancy(vacancy, candidates, owner, rule.ThresholdFor(process), rule.ReferenceDate),
+		)
+	}
+
+	timeline := []TimelineProcess{}
+	for _, group := range groups {
+		timeline = append(timeline, timelineProcess(
+			group.process,
+			group.vacancies,
+			joinOwners(group.owners),
+			rule,
+		))
+	}
+
+	sortTimelineIntervals(timeline, func(process TimelineProcess) TimelineInterval {
+		return process.TimelineInterval
+	})
+
+	return timeline, nil
+}
+
+func timelineVacancy(
+	vacancy *ent.DimVacancy,
+	candidates []*ent.DimCandidate,
+	owner string,
+	threshold float64,
+	referenceDate time.Time,
+) TimelineInterval {
+	status := VacancyStatusOf(vacancy)
+	interval := timelineInterval(
+		vacancy.ID,
+		vacancy.Title,
+		vacancy.OpeningDate,
+		vacancy.ClosingDate,
+		status.String(),
+		owner,
+		status == property.DimVacancyStatusClosed,
+		threshold,
+		referenceDate,
+	)
+
+	var firstHire *time.Time
+	for _, candidate := range candidates {
+		if candidate.Status != property.DimCandidateStatusHired ||
+			candidate.UpdatedAt == nil || !candidate.UpdatedAt.Valid {
+			continue
+		}
+
+		if firstHire == nil || candidate.UpdatedAt.Time.Before(*firstHire) {
+			hire := candidate.UpdatedAt.Time
+			firstHire = &hire
+		}
+	}
+
+	if firstHire != nil {
+		interval.Markers = append(interval.Markers, TimelineMarker{
+			Type: TimelineMarkerFirstHire,
+			Date: firstHire.Format(time.DateOnly),
+		})
+		sortTimelineMarkers(interval.Markers)
+	}
+
+	return interval
+}
+
+// timelineProcess builds the interval of the process, whose first hire
+// is the earliest of its vacancies.
+func timelineProcess(
+	process *ent.DimProcess,
+	vacancies []TimelineInterval,
+	owner string,
+	rule DeadlineRule,
+) TimelineProcess {
+	status := ProcessStatusOf(process)
+	interval := timelineInterval(
+		process.ID,
+		process.Title,
+		process.InitialDate,
+		process.FinishDate,
+		status.String(),
+		owner,
+		status == property.DimProcessStatusClosed,
+		rule.ThresholdFor(process),
+		rule.ReferenceDate,
+	)
+
+	firstHire := ""
+	for _, vacancy := range vacancies {
+		for _, marker := range vacancy.Markers {
+			if marker.Type == TimelineMarkerFirstHire &&
+				(firstHire == "" || marker.Date < firstHire) {
+				firstHire = marker.Date
+			}
+		}
+	}
+
+	if firstHire != "" {
+		interval.Markers = append(interval.Markers, TimelineMarker{
+			Type: TimelineMarkerFirstHire,
+			Date: firstHire,
+		})
+		sortTimelineMarkers(interval.Markers)
+	}
+
+	sortTimelineIntervals(vacancies, func(vacancy TimelineInterval) TimelineInterval {
+		return vacancy
+	})
+
+	return TimelineProcess{
+		TimelineInterval: interval,
+		Vacancies:        vacancies,
+	}
+}
+
+// timelineInterval builds an interval with its deadline markers. The
+// deadline approaches once less than `threshold` of the duration of the
+// interval remains, and it is missed when the end date passed, as of
+// the reference date, before the interval was closed.
+func timelineInterval(
+	id int,
+	title string,
+	startDate, endDate *pgtype.Date,
+	status string,
+	owner string,
+	closed bool,
+	threshold float64,
+	referenceDate time.Time,
+) TimelineInterval {
+	interval := TimelineInterval{
+		Id:      id,
+		Title:   title,
+		Status:  status,
+		Owner:   owner,
+		Markers: []TimelineMarker{},
+	}
+
+	if startDate != nil && startDate.Valid {
+		interval.StartDate = startDate.Time.Format(time.DateOnly)
+	}
+
+	if endDate == nil || !endDate.Valid {
+		return interval
+	}
+
+	end := endDate.Time.Format(time.DateOnly)
+	interval.EndDate = &end
+
+	if startDate != nil && startDate.Valid && endDate.Time.After(startDate.Time) {
+		totalDuration := endDate.Time.Sub(startDate.Time)
+		approaching := endDate.Time.Add(-time.Duration(float64(totalDuration) * threshold))
+		interval.Markers = append(interval.Markers, TimelineMarker{
+			Type: TimelineMarkerDeadlineApproaching,
+			Date: approaching.Format(time.DateOnly),
+		})
+	}
+
+	if !closed && endDate.Time.Before(referenceDate) {
+		interval.Markers = append(interval.Markers, TimelineMarker{
+			Type: TimelineMarkerDeadlineMissed,
+			Date: end,
+		})
+	}
+
+	return interval
+}
+
+func joinOwners(owners []string) string {
+	var distinct []string
+	visited := make(map[string]bool)
+	for _, owner := range owners {
+		if owner != "" && !visited[owner] {
+			visited[owner] = true
+			distinct = append(distinct, owner)
+		}
+	}
+
+	sort.Strings(distinct)
+	return strings.Join(distinct, ", ")
+}
+
+func sortTimelineMarkers(markers []TimelineMarker) {
+	sort.SliceStable(markers, func(i, j int) bool {
+		return markers[i].Date < markers[j].Date
+	})
+}
+
+// sortTimelineIntervals sorts by start date, then title and ID, with the
+// intervals without a start date last.
+func sortTimelineIntervals[T any](items []T, interval func(T) TimelineInterval) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := interval(items[i]), interval(items[j])
+		if a.StartDate != b.StartDate {
+			if a.StartDate == "" || b.StartDate == "" {
+				return b.StartDate == ""
+			}
+			return a.StartDate < b.StartDate
+		}
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		return a.Id < b.Id
+	})
+}
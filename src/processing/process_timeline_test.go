@@ -0,0 +1,92 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func timelineDate(day int) *pgtype.Date {
+	return &pgtype.Date{Time: time.Date(2024, time.January, day, 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func TestProcessTimeline(t *testing.T) {
+	referenceDate := time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC)
+	rule := DeadlineRule{
+		Threshold:            DefaultDeadlineThreshold,
+		DepartmentThresholds: map[int]float64{3: 0.5},
+		ReferenceDate:        referenceDate,
+	}
+
+	developer := timelineVacancy(
+		&ent.DimVacancy{
+			ID:          2,
+			Title:       "Developer",
+			OpeningDate: timelineDate(5),
+			ClosingDate: timelineDate(15),
+			// statuses are scanned zero based
+			Status: property.DimVacancyStatusClosed - 1,
+		},
+		[]*ent.DimCandidate{
+			newTestCandidate(property.DimCandidateStatusHired, "2024-01-05", "2024-01-12"),
+			newTestCandidate(property.DimCandidateStatusRejected, "2024-01-05", "2024-01-06"),
+			newTestCandidate(property.DimCandidateStatusHired, "2024-01-05", "2024-01-09"),
+		},
+		"Maria",
+		DefaultDeadlineThreshold,
+		referenceDate,
+	)
+
+	require.Equal(t, "Closed", developer.Status)
+	require.Equal(t, "2024-01-05", developer.StartDate)
+	require.Equal(t, "2024-01-15", *developer.EndDate)
+	require.Equal(t, []TimelineMarker{
+		{Type: TimelineMarkerFirstHire, Date: "2024-01-09"},
+		{Type: TimelineMarkerDeadlineApproaching, Date: "2024-01-13"},
+	}, developer.Markers)
+
+	analyst := timelineVacancy(
+		&ent.DimVacancy{
+			ID:          1,
+			Title:       "Analyst",
+			OpeningDate: timelineDate(2),
+		},
+		nil,
+		"João",
+		DefaultDeadlineThreshold,
+		referenceDate,
+	)
+
+	require.Equal(t, "Open", analyst.Status)
+	require.Nil(t, analyst.EndDate)
+	require.Empty(t, analyst.Markers)
+
+	process := timelineProcess(
+		&ent.DimProcess{
+			ID:              1,
+			Title:           "Engineering",
+			InitialDate:     timelineDate(1),
+			FinishDate:      timelineDate(31),
+			DimDepartmentId: 3,
+			Status:          property.DimProcessStatusInProgress - 1,
+		},
+		[]TimelineInterval{developer, analyst},
+		joinOwners([]string{"Maria", "João", "Maria", ""}),
+		rule,
+	)
+
+	require.Equal(t, "In Progress", process.Status)
+	require.Equal(t, "João, Maria", process.Owner)
+	require.Equal(t, []TimelineMarker{
+		{Type: TimelineMarkerFirstHire, Date: "2024-01-09"},
+		{Type: TimelineMarkerDeadlineApproaching, Date: "2024-01-16"},
+		{Type: TimelineMarkerDeadlineMissed, Date: "2024-01-31"},
+	}, process.Markers)
+	require.Equal(t, "Analyst", process.Vacancies[0].Title)
+	require.Equal(t, "Developer", process.Vacancies[1].Title)
+}
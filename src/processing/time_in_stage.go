@@ -0,0 +1,252 @@
+package processing
+
+import (
+	"fmt"
+	"sort"
+
+	"api5back/ent"
+	"api5back/src/property"
+)
+
+const (
+	TimeInStageGroupByVacancy    = "vacancy"
+	TimeInStageGroupByRecruiter  = "recruiter"
+	TimeInStageGroupByDepartment = "department"
+)
+
+// StageStay is the time in days a candidate spent in a status before
+// moving to the next one.
+type StageStay struct {
+	VacancyId int
+	Status    property.DimCandidateStatus
+	Days      float64
+}
+
+type StageDuration struct {
+	Status string  `json:"status"`
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+}
+
+type TimeInStageGroup struct {
+	Id     int             `json:"id"`
+	Label  string          `json:"label"`
+	Stages []StageDuration `json:"stages"`
+}
+
+type TimeInStage struct {
+	GroupBy string             `json:"groupBy"`
+	Stages  []StageDuration    `json:"stages"`
+	Groups  []TimeInStageGroup `json:"groups"`
+}
+
+func ValidateTimeInStageGroupBy(groupBy string) error {
+	switch groupBy {
+	case TimeInStageGroupByVacancy,
+		TimeInStageGroupByRecruiter,
+		TimeInStageGroupByDepartment:
+		return nil
+	default:
+		return fmt.Errorf(
+			"invalid group: %q, expected %q, %q or %q",
+			groupBy,
+			TimeInStageGroupByVacancy,
+			TimeInStageGroupByRecruiter,
+			TimeInStageGroupByDepartment,
+		)
+	}
+}
+
+// CandidateStageStays pairs each status change of a candidate with the
+// next one, counting the days in between by `days`. The status a
+// candidate is still in has no stay yet.
+func CandidateStageStays(
+	changes []*ent.FactCandidateStatusChange,
+	days DayCounter,
+) ([]StageStay, error) {
+	type candidateKey struct {
+		DbId      int
+		VacancyId int
+	}
+
+	var keys []candidateKey
+	changesByCandidate := make(map[candidateKey][]*ent.FactCandidateStatusChange)
+	for _, change := range changes {
+		candidate, err := change.Edges.DimCandidateOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimCandidate` of `FactCandidateStatusChange` with ID %d not found: %w",
+				change.ID,
+				err,
+			)
+		}
+
+		key := candidateKey{candidate.DbId, change.DimVacancyId}
+		if _, ok := changesByCandidate[key]; !ok {
+			keys = append(keys, key)
+		}
+		changesByCandidate[key] = append(changesByCandidate[key], change)
+	}
+
+	var stays []StageStay
+	for _, key := range keys {
+		candidateChanges := changesByCandidate[key]
+		sort.SliceStable(candidateChanges, func(i, j int) bool {
+			if candidateChanges[i].ChangedAt.Equal(candidateChanges[j].ChangedAt) {
+				return candidateChanges[i].ID < candidateChanges[j].ID
+			}
+			return candidateChanges[i].ChangedAt.Before(candidateChanges[j].ChangedAt)
+		})
+
+		for i := 0; i+1 < len(candidateChanges); i++ {
+			stays = append(stays, StageStay{
+				VacancyId: key.VacancyId,
+				Status:    candidateChanges[i].ToStatus,
+				Days: days.Days(
+					candidateChanges[i].ChangedAt,
+					candidateChanges[i+1].ChangedAt,
+				),
+			})
+		}
+	}
+
+	return stays, nil
+}
+
+// stageDurations summarizes the stays of each status, in the order of
+// the statuses. Statuses without stays are left out.
+func stageDurations(stays []StageStay) []StageDuration {
+	daysByStatus := make(map[property.DimCandidateStatus][]float64)
+	for _, stay := range stays {
+		daysByStatus[stay.Status] = append(daysByStatus[stay.Status], stay.Days)
+	}
+
+	stages := []StageDuration{}
+	for i, status := range property.DimCandidateStatus(0).Values() {
+		days := daysByStatus[property.DimCandidateStatus(i)]
+		if len(days) == 0 {
+			continue
+		}
+
+		sort.Float64s(days)
+
+		sum := 0.0
+		for _, d := range days {
+			sum += d
+		}
+
+		stages = append(stages, StageDuration{
+			Status: status,
+			Count:  len(days),
+			Mean:   sum / float64(len(days)),
+			Median: percentile(days, 50),
+		})
+	}
+
+	return stages
+}
+
+// GenerateTimeInStage computes the average and median time in each
+// status over every stay, and per vacancy, recruiter or department of
+// the facts the stays belong to. Groups are ordered by their ID.
+func GenerateTimeInStage(
+	factHiringProcesses []*ent.FactHiringProcess,
+	stays []StageStay,
+	groupBy string,
+) (TimeInStage, error) {
+	if err := ValidateTimeInStageGroupBy(groupBy); err != nil {
+		return TimeInStage{}, err
+	}
+
+	type group struct {
+		Id    int
+		Label string
+	}
+
+	groupByVacancy := make(map[int]group)
+	for _, factHiringProcess := range factHiringProcesses {
+		if _, ok := groupByVacancy[factHiringProcess.DimVacancyId]; ok {
+			continue
+		}
+
+		var g group
+		switch groupBy {
+		case TimeInStageGroupByVacancy:
+			vacancy, err := factHiringProcess.Edges.DimVacancyOrErr()
+			if err != nil {
+				return TimeInStage{}, fmt.Errorf(
+					"`DimVacancy` of `FactHiringProcess` with ID %d not found: %w",
+					factHiringProcess.ID,
+					err,
+				)
+			}
+			g = group{vacancy.ID, vacancy.Title}
+		case TimeInStageGroupByRecruiter:
+			user, err := factHiringProcess.Edges.DimUserOrErr()
+			if err != nil {
+				return TimeInStage{}, fmt.Errorf(
+					"`DimUser` of `FactHiringProcess` with ID %d not found: %w",
+					factHiringProcess.ID,
+					err,
+				)
+			}
+			g = group{user.ID, user.Name}
+		case TimeInStageGroupByDepartment:
+			process, err := factHiringProcess.Edges.DimProcessOrErr()
+			if err != nil {
+				return TimeInStage{}, fmt.Errorf(
+					"`DimProcess` of `FactHiringProcess` with ID %d not found: %w",
+					factHiringProcess.ID,
+					err,
+				)
+			}
+			department, err := process.Edges.DimDepartmentOrErr()
+			if err != nil {
+				return TimeInStage{}, fmt.Errorf(
+					"`DimDepartment` of `DimProcess` with ID %d not found: %w",
+					process.ID,
+					err,
+				)
+			}
+			g = group{department.ID, department.Name}
+		}
+
+		groupByVacancy[factHiringProcess.DimVacancyId] = g
+	}
+
+	var groups []group
+	staysByGroup := make(map[group][]StageStay)
+	var filteredStays []StageStay
+	for _, stay := range stays {
+		g, ok := groupByVacancy[stay.VacancyId]
+		if !ok {
+			continue
+		}
+
+		if _, ok := staysByGroup[g]; !ok {
+			groups = append(groups, g)
+		}
+		staysByGroup[g] = append(staysByGroup[g], stay)
+		filteredStays = append(filteredStays, stay)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Id < groups[j].Id
+	})
+
+	timeInStage := TimeInStage{
+		GroupBy: groupBy,
+		Stages:  stageDurations(filteredStays),
+		Groups:  []TimeInStageGroup{},
+	}
+	for _, g := range groups {
+		timeInStage.Groups = append(timeInStage.Groups, TimeInStageGroup{
+			Id:     g.Id,
+			Label:  g.Label,
+			Stages: stageDurations(staysByGroup[g]),
+		})
+	}
+
+	return timeInStage, nil
+}
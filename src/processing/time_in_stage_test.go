@@ -0,0 +1,126 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/stretchr/testify/require"
+)
+
+func statusChange(
+	id, candidateDbId, vacancyId int,
+	toStatus property.DimCandidateStatus,
+	day int,
+) *ent.FactCandidateStatusChange {
+	return &ent.FactCandidateStatusChange{
+		ID:           id,
+		DimVacancyId: vacancyId,
+		ToStatus:     toStatus,
+		ChangedAt:    time.Date(2024, 7, day, 0, 0, 0, 0, time.UTC),
+		Edges: ent.FactCandidateStatusChangeEdges{
+			DimCandidate: &ent.DimCandidate{DbId: candidateDbId},
+		},
+	}
+}
+
+func TestTimeInStage(t *testing.T) {
+	changes := []*ent.FactCandidateStatusChange{
+		// candidate 1 of vacancy 1, loaded out of order
+		statusChange(2, 1, 1, property.DimCandidateStatusInterview, 5),
+		statusChange(1, 1, 1, property.DimCandidateStatusInAnalysis, 1),
+		statusChange(3, 1, 1, property.DimCandidateStatusHired, 11),
+		// candidate 2 of vacancy 1
+		statusChange(4, 2, 1, property.DimCandidateStatusInAnalysis, 1),
+		statusChange(5, 2, 1, property.DimCandidateStatusRejected, 3),
+		// candidate 1 of vacancy 2, still in analysis
+		statusChange(6, 1, 2, property.DimCandidateStatusInAnalysis, 2),
+		// candidate 3 of vacancy 2
+		statusChange(7, 3, 2, property.DimCandidateStatusInAnalysis, 1),
+		statusChange(8, 3, 2, property.DimCandidateStatusInterview, 10),
+	}
+
+	stays, err := CandidateStageStays(changes, DayCounter{})
+	require.NoError(t, err)
+	require.Len(t, stays, 4)
+
+	businessStays, err := CandidateStageStays(changes, DayCounter{Unit: DurationUnitBusinessDays})
+	require.NoError(t, err)
+	require.Equal(t, 6.0, stays[1].Days)
+	require.Equal(t, 4.0, businessStays[1].Days, "the interview spans a weekend")
+
+	factHiringProcesses := []*ent.FactHiringProcess{
+		{
+			DimVacancyId: 1,
+			Edges: ent.FactHiringProcessEdges{
+				DimVacancy: &ent.DimVacancy{ID: 1, Title: "Go Developer"},
+				DimUser:    &ent.DimUser{ID: 7, Name: "Alice"},
+			},
+		},
+		{
+			DimVacancyId: 2,
+			Edges: ent.FactHiringProcessEdges{
+				DimVacancy: &ent.DimVacancy{ID: 2, Title: "Designer"},
+				DimUser:    &ent.DimUser{ID: 7, Name: "Alice"},
+			},
+		},
+	}
+
+	for i, testCase := range []struct {
+		Name           string
+		GroupBy        string
+		ExpectedStages []StageDuration
+		ExpectedGroups int
+	}{
+		{
+			Name:    "per vacancy",
+			GroupBy: TimeInStageGroupByVacancy,
+			ExpectedStages: []StageDuration{
+				{Status: "In Analysis", Count: 3, Mean: 5, Median: 4},
+				{Status: "Interview", Count: 1, Mean: 6, Median: 6},
+			},
+			ExpectedGroups: 2,
+		},
+		{
+			Name:    "per recruiter",
+			GroupBy: TimeInStageGroupByRecruiter,
+			ExpectedStages: []StageDuration{
+				{Status: "In Analysis", Count: 3, Mean: 5, Median: 4},
+				{Status: "Interview", Count: 1, Mean: 6, Median: 6},
+			},
+			ExpectedGroups: 1,
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			timeInStage, err := GenerateTimeInStage(factHiringProcesses, stays, testCase.GroupBy)
+			require.NoError(t, err)
+
+			if testResult := len(timeInStage.Groups) == testCase.ExpectedGroups; !testResult {
+				t.Errorf("Test case %d failed", i)
+			}
+			require.Equal(t, testCase.ExpectedStages, timeInStage.Stages)
+		})
+	}
+
+	t.Run("groups split the stays", func(t *testing.T) {
+		timeInStage, err := GenerateTimeInStage(factHiringProcesses, stays, TimeInStageGroupByVacancy)
+		require.NoError(t, err)
+		require.Equal(t, "Go Developer", timeInStage.Groups[0].Label)
+		require.Equal(t, []StageDuration{
+			{Status: "In Analysis", Count: 2, Mean: 3, Median: 3},
+			{Status: "Interview", Count: 1, Mean: 6, Median: 6},
+		}, timeInStage.Groups[0].Stages)
+	})
+
+	t.Run("invalid group", func(t *testing.T) {
+		_, err := GenerateTimeInStage(factHiringProcesses, stays, "location")
+		require.Error(t, err)
+	})
+
+	t.Run("changes without candidates fail", func(t *testing.T) {
+		_, err := CandidateStageStays([]*ent.FactCandidateStatusChange{{ID: 1}}, DayCounter{})
+		require.Error(t, err)
+	})
+}
@@ -13,30 +13,27 @@ type VacancyStatusSummary struct {
 	Closed    int `json:"closed" default:"0"`
 }
 
+// GenerateVacancyStatusSummary evaluates `MetricVacanciesByStatus` over
+// the facts.
 func GenerateVacancyStatusSummary(
 	factHiringProcesses []*ent.FactHiringProcess,
 ) (VacancyStatusSummary, error) {
-	countByStatus := make(map[property.DimVacancyStatus]int)
-
-	for _, factHiringProcess := range factHiringProcesses {
-		vacancy, err := factHiringProcess.
-			Edges.
-			DimVacancyOrErr()
-		if err != nil {
-			return VacancyStatusSummary{}, fmt.Errorf(
-				"`DimVacancy` with ID %d of `FactHiringProcess` with ID %d not found: %w",
-				factHiringProcess.DimVacancyId,
-				factHiringProcess.ID,
-				err,
-			)
-		}
-
-		countByStatus[property.DimVacancyStatus(vacancy.Status+1)]++
+	countByStatus, err := MetricVacanciesByStatus.Aggregate(
+		factHiringProcesses,
+		DayCounter{},
+		DeadlineRule{},
+	)
+	if err != nil {
+		return VacancyStatusSummary{}, fmt.Errorf(
+			"could not aggregate metric %q: %w",
+			MetricVacanciesByStatus.Id,
+			err,
+		)
 	}
 
 	return VacancyStatusSummary{
-		Open:      countByStatus[property.DimVacancyStatusOpen],
-		Analyzing: countByStatus[property.DimVacancyStatusInAnalysis],
-		Closed:    countByStatus[property.DimVacancyStatusClosed],
+		Open:      int(countByStatus[property.DimVacancyStatusOpen.String()]),
+		Analyzing: int(countByStatus[property.DimVacancyStatusInAnalysis.String()]),
+		Closed:    int(countByStatus[property.DimVacancyStatusClosed.String()]),
 	}, nil
 }
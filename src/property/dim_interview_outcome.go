@@ -0,0 +1,63 @@
+package property
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+type DimInterviewOutcome int
+
+const (
+	DimInterviewOutcomePending DimInterviewOutcome = iota
+	DimInterviewOutcomeApproved
+	DimInterviewOutcomeRejected
+	DimInterviewOutcomeNoShow
+)
+
+func (o DimInterviewOutcome) String() string {
+	return [...]string{
+		"Pending",
+		"Approved",
+		"Rejected",
+		"No Show",
+	}[o]
+}
+
+func (DimInterviewOutcome) Values() []string {
+	return []string{
+		DimInterviewOutcomePending.String(),
+		DimInterviewOutcomeApproved.String(),
+		DimInterviewOutcomeRejected.String(),
+		DimInterviewOutcomeNoShow.String(),
+	}
+}
+
+func (o DimInterviewOutcome) Value() (driver.Value, error) {
+	return o.String(), nil
+}
+
+func (o *DimInterviewOutcome) Scan(value interface{}) error {
+	var valueStr string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case int:
+		*o = DimInterviewOutcome(v)
+		return nil
+	case string:
+		valueStr = v
+	case []byte:
+		valueStr = string(v)
+	default:
+		return fmt.Errorf("invalid dim_interview outcome: %v", value)
+	}
+
+	for i, outcomeStr := range DimInterviewOutcome(0).Values() {
+		if outcomeStr == valueStr {
+			*o = DimInterviewOutcome(i)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid dim_interview outcome: %q", value)
+}
@@ -0,0 +1,72 @@
+package property
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDimInterviewOutcome(t *testing.T) {
+	for _, testCase := range []PropertyStatusTestCase{
+		{
+			Name:           "Pending",
+			IntValue:       0,
+			ExpectedPanic:  false,
+			ExpectedError:  false,
+			ExpectedStatus: "Pending",
+		},
+		{
+			Name:           "Approved",
+			IntValue:       "Approved",
+			ExpectedPanic:  false,
+			ExpectedError:  false,
+			ExpectedStatus: "Approved",
+		},
+		{
+			Name:           "No Show",
+			IntValue:       []byte("No Show"),
+			ExpectedPanic:  false,
+			ExpectedError:  false,
+			ExpectedStatus: "No Show",
+		},
+		{
+			Name:           "Invalid positive value",
+			IntValue:       4,
+			ExpectedPanic:  true,
+			ExpectedError:  false,
+			ExpectedStatus: "",
+		},
+		{
+			Name:           "Invalid string value",
+			IntValue:       "Invalid",
+			ExpectedPanic:  false,
+			ExpectedError:  true,
+			ExpectedStatus: "",
+		},
+		{
+			Name:           "Nil value",
+			IntValue:       nil,
+			ExpectedPanic:  false,
+			ExpectedError:  false,
+			ExpectedStatus: "Pending",
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			testFunction := func() {
+				outcome := DimInterviewOutcome(0)
+				err := outcome.Scan(testCase.IntValue)
+				if err != nil {
+					require.Equal(t, testCase.ExpectedStatus, "")
+				} else {
+					require.Equal(t, testCase.ExpectedStatus, outcome.String())
+				}
+			}
+
+			if testCase.ExpectedPanic {
+				require.Panics(t, testFunction)
+			} else {
+				require.NotPanics(t, testFunction)
+			}
+		})
+	}
+}
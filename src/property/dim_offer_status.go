@@ -0,0 +1,60 @@
+package property
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+type DimOfferStatus int
+
+const (
+	DimOfferStatusSent DimOfferStatus = iota
+	DimOfferStatusAccepted
+	DimOfferStatusDeclined
+)
+
+func (s DimOfferStatus) String() string {
+	return [...]string{
+		"Sent",
+		"Accepted",
+		"Declined",
+	}[s]
+}
+
+func (DimOfferStatus) Values() []string {
+	return []string{
+		DimOfferStatusSent.String(),
+		DimOfferStatusAccepted.String(),
+		DimOfferStatusDeclined.String(),
+	}
+}
+
+func (s DimOfferStatus) Value() (driver.Value, error) {
+	return s.String(), nil
+}
+
+func (s *DimOfferStatus) Scan(value interface{}) error {
+	var valueStr string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case int:
+		*s = DimOfferStatus(v)
+		return nil
+	case string:
+		valueStr = v
+	case []byte:
+		valueStr = string(v)
+	default:
+		return fmt.Errorf("invalid dim_offer status: %v", value)
+	}
+
+	for i, statusStr := range DimOfferStatus(0).Values() {
+		if statusStr == valueStr {
+			*s = DimOfferStatus(i)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid dim_offer status: %q", value)
+}
@@ -0,0 +1,72 @@
+package property
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDimOfferStatus(t *testing.T) {
+	for _, testCase := range []PropertyStatusTestCase{
+		{
+			Name:           "Sent",
+			IntValue:       0,
+			ExpectedPanic:  false,
+			ExpectedError:  false,
+			ExpectedStatus: "Sent",
+		},
+		{
+			Name:           "Accepted",
+			IntValue:       "Accepted",
+			ExpectedPanic:  false,
+			ExpectedError:  false,
+			ExpectedStatus: "Accepted",
+		},
+		{
+			Name:           "Declined",
+			IntValue:       []byte("Declined"),
+			ExpectedPanic:  false,
+			ExpectedError:  false,
+			ExpectedStatus: "Declined",
+		},
+		{
+			Name:           "Invalid positive value",
+			IntValue:       3,
+			ExpectedPanic:  true,
+			ExpectedError:  false,
+			ExpectedStatus: "",
+		},
+		{
+			Name:           "Invalid string value",
+			IntValue:       "Invalid",
+			ExpectedPanic:  false,
+			ExpectedError:  true,
+			ExpectedStatus: "",
+		},
+		{
+			Name:           "Nil value",
+			IntValue:       nil,
+			ExpectedPanic:  false,
+			ExpectedError:  false,
+			ExpectedStatus: "Sent",
+		},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			testFunction := func() {
+				status := DimOfferStatus(0)
+				err := status.Scan(testCase.IntValue)
+				if err != nil {
+					require.Equal(t, testCase.ExpectedStatus, "")
+				} else {
+					require.Equal(t, testCase.ExpectedStatus, status.String())
+				}
+			}
+
+			if testCase.ExpectedPanic {
+				require.Panics(t, testFunction)
+			} else {
+				require.NotPanics(t, testFunction)
+			}
+		})
+	}
+}
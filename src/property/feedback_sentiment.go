@@ -0,0 +1,24 @@
+package property
+
+type FeedbackSentiment string
+
+const (
+	FeedbackSentimentPositive FeedbackSentiment = "positive"
+	FeedbackSentimentNeutral  FeedbackSentiment = "neutral"
+	FeedbackSentimentNegative FeedbackSentiment = "negative"
+)
+
+// FeedbackSentimentThreshold is the sentiment score from which a
+// feedback is positive, or, negated, up to which it is negative.
+var FeedbackSentimentThreshold = 0.2
+
+func FeedbackSentimentOf(sentimentScore float64) FeedbackSentiment {
+	switch {
+	case sentimentScore >= FeedbackSentimentThreshold:
+		return FeedbackSentimentPositive
+	case sentimentScore <= -FeedbackSentimentThreshold:
+		return FeedbackSentimentNegative
+	default:
+		return FeedbackSentimentNeutral
+	}
+}
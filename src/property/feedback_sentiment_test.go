@@ -0,0 +1,22 @@
+package property
+
+import "testing"
+
+func TestFeedbackSentimentOf(t *testing.T) {
+	for i, testCase := range []struct {
+		Score    float64
+		Expected FeedbackSentiment
+	}{
+		{Score: 1, Expected: FeedbackSentimentPositive},
+		{Score: 0.2, Expected: FeedbackSentimentPositive},
+		{Score: 0.19, Expected: FeedbackSentimentNeutral},
+		{Score: 0, Expected: FeedbackSentimentNeutral},
+		{Score: -0.19, Expected: FeedbackSentimentNeutral},
+		{Score: -0.2, Expected: FeedbackSentimentNegative},
+		{Score: -1, Expected: FeedbackSentimentNegative},
+	} {
+		if testResult := FeedbackSentimentOf(testCase.Score) == testCase.Expected; !testResult {
+			t.Errorf("Test case %d failed", i)
+		}
+	}
+}
@@ -23,8 +23,7 @@ func (DimCandidate) Fields() []ent.Field {
 		field.String("email"),
 		field.String("phone"),
 		field.Float("score"),
-		field.Int("dimVacancyDbId").
-			Immutable(),
+		field.Int("dimVacancyDbId"),
 		field.Other("applyDate", &pgtype.Date{}).SchemaType(map[string]string{
 			dialect.Postgres: "date",
 		}),
@@ -40,13 +39,28 @@ func (DimCandidate) Fields() []ent.Field {
 	}
 }
 
+func (DimCandidate) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		SlowlyChangingDimension{},
+	}
+}
+
 func (DimCandidate) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("dimVacancy", DimVacancy.Type).
 			Field("dimVacancyDbId").
 			Unique().
-			Required().
-			Immutable(),
+			Required(),
+		edge.From("statusChanges", FactCandidateStatusChange.Type).
+			Ref("dimCandidate"),
+		edge.From("dimInterviews", DimInterview.Type).
+			Ref("dimCandidate"),
+		edge.From("dimFeedbacks", DimFeedback.Type).
+			Ref("dimCandidate"),
+		edge.From("dimOffers", DimOffer.Type).
+			Ref("dimCandidate"),
+		edge.From("milestones", FactCandidateMilestone.Type).
+			Ref("dimCandidate"),
 	}
 }
 
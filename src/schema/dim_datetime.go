@@ -10,6 +10,9 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// DimDatetime is the date dimension. Its calendar attributes are filled
+// by the calendar generator, see `warehouse.LoadCalendar`; `weekday`
+// goes from 1, Monday, to 7, Sunday.
 type DimDatetime struct {
 	ent.Schema
 }
@@ -26,6 +29,18 @@ func (DimDatetime) Fields() []ent.Field {
 		field.Int("hour"),
 		field.Int("minute"),
 		field.Int("second"),
+		field.Int("isoYear").Default(0),
+		field.Int("isoWeek").Default(0),
+		field.Int("quarter").Default(0),
+		field.Int("fiscalYear").Default(0),
+		field.Int("fiscalQuarter").Default(0),
+		field.String("monthNamePt").Default(""),
+		field.String("monthNameEn").Default(""),
+		field.String("dayNamePt").Default(""),
+		field.String("dayNameEn").Default(""),
+		field.Bool("isWeekend").Default(false),
+		field.Bool("isHoliday").Default(false),
+		field.String("holidayName").Optional(),
 	}
 }
 
@@ -20,6 +20,12 @@ func (DimDepartment) Fields() []ent.Field {
 	}
 }
 
+func (DimDepartment) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		SlowlyChangingDimension{},
+	}
+}
+
 func (DimDepartment) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.From("dim_process", DimProcess.Type).
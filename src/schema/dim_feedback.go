@@ -0,0 +1,59 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DimFeedback is the feedback given on a candidate, optionally after one
+// of its interviews. `sentimentScore` goes from -1, the most negative,
+// to 1, the most positive.
+type DimFeedback struct {
+	ent.Schema
+}
+
+func (DimFeedback) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("dbId"),
+		field.Int("dimCandidateId").Immutable(),
+		field.Int("dimVacancyId"),
+		field.Int("dimInterviewId").Optional(),
+		field.Other("date", &pgtype.Date{}).SchemaType(map[string]string{
+			dialect.Postgres: "date",
+		}),
+		field.Float("sentimentScore").
+			Min(-1).
+			Max(1),
+		field.String("comment").Optional(),
+	}
+}
+
+func (DimFeedback) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("dimCandidate", DimCandidate.Type).
+			Unique().
+			Immutable().
+			Required().
+			Field("dimCandidateId"),
+		edge.To("dimVacancy", DimVacancy.Type).
+			Unique().
+			Required().
+			Field("dimVacancyId"),
+		edge.To("dimInterview", DimInterview.Type).
+			Unique().
+			Field("dimInterviewId"),
+	}
+}
+
+func (DimFeedback) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{
+			Table: "dim_feedback",
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"api5back/src/property"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type DimInterview struct {
+	ent.Schema
+}
+
+func (DimInterview) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("dbId"),
+		field.Int("dimCandidateId").Immutable(),
+		field.Int("dimVacancyId"),
+		field.Other("date", &pgtype.Date{}).SchemaType(map[string]string{
+			dialect.Postgres: "date",
+		}),
+		field.String("interviewer"),
+		field.Enum("outcome").
+			GoType(property.DimInterviewOutcome(0)).
+			SchemaType(map[string]string{
+				dialect.Postgres: "character varying",
+			}),
+	}
+}
+
+func (DimInterview) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("dimCandidate", DimCandidate.Type).
+			Unique().
+			Immutable().
+			Required().
+			Field("dimCandidateId"),
+		edge.To("dimVacancy", DimVacancy.Type).
+			Unique().
+			Required().
+			Field("dimVacancyId"),
+		edge.From("dimFeedbacks", DimFeedback.Type).
+			Ref("dimInterview"),
+	}
+}
+
+func (DimInterview) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{
+			Table: "dim_interview",
+		},
+	}
+}
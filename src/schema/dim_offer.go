@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"regexp"
+
+	"api5back/src/property"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DimOffer is the job offer made to a candidate of a vacancy. A new
+// version is loaded when the candidate accepts or declines it, setting
+// the date of the answer. `currency` is an ISO 4217 code.
+type DimOffer struct {
+	ent.Schema
+}
+
+func (DimOffer) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("dbId"),
+		field.Int("dimCandidateId").Immutable(),
+		field.Int("dimVacancyId"),
+		field.Float("salary").
+			Min(0),
+		field.String("currency").
+			Match(regexp.MustCompile("^[A-Z]{3}$")).
+			Default("BRL"),
+		field.Other("sentDate", &pgtype.Date{}).SchemaType(map[string]string{
+			dialect.Postgres: "date",
+		}),
+		field.Other("acceptedDate", &pgtype.Date{}).SchemaType(map[string]string{
+			dialect.Postgres: "date",
+		}).Optional(),
+		field.Other("declinedDate", &pgtype.Date{}).SchemaType(map[string]string{
+			dialect.Postgres: "date",
+		}).Optional(),
+		field.Enum("status").
+			GoType(property.DimOfferStatus(0)).
+			SchemaType(map[string]string{
+				dialect.Postgres: "character varying",
+			}),
+	}
+}
+
+func (DimOffer) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		SlowlyChangingDimension{},
+	}
+}
+
+func (DimOffer) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("dimCandidate", DimCandidate.Type).
+			Unique().
+			Immutable().
+			Required().
+			Field("dimCandidateId"),
+		edge.To("dimVacancy", DimVacancy.Type).
+			Unique().
+			Required().
+			Field("dimVacancyId"),
+	}
+}
+
+func (DimOffer) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{
+			Table: "dim_offer",
+		},
+	}
+}
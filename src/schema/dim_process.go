@@ -34,7 +34,13 @@ func (DimProcess) Fields() []ent.Field {
 			Default(int(property.DimProcessStatusOpen)),
 		field.Int("dimUsrId"),
 		field.String("description").Optional(),
-		field.Int("dimDepartmentId").Immutable(),
+		field.Int("dimDepartmentId"),
+	}
+}
+
+func (DimProcess) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		SlowlyChangingDimension{},
 	}
 }
 
@@ -42,7 +48,6 @@ func (DimProcess) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("dimDepartment", DimDepartment.Type).
 			Unique().
-			Immutable().
 			Required().
 			Field("dimDepartmentId"),
 		edge.From("fact_hiring_process", FactHiringProcess.Type).
@@ -25,6 +25,12 @@ func (DimUser) Fields() []ent.Field {
 	}
 }
 
+func (DimUser) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		SlowlyChangingDimension{},
+	}
+}
+
 func (DimUser) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.From("fact_hiring_process", FactHiringProcess.Type).
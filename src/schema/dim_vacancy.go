@@ -39,12 +39,28 @@ func (DimVacancy) Fields() []ent.Field {
 	}
 }
 
+func (DimVacancy) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		SlowlyChangingDimension{},
+	}
+}
+
 func (DimVacancy) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.From("fact_hiring_process", FactHiringProcess.Type).
 			Ref("dimVacancy"),
 		edge.From("dimCandidates", DimCandidate.Type).
 			Ref("dimVacancy"),
+		edge.From("candidateStatusChanges", FactCandidateStatusChange.Type).
+			Ref("dimVacancy"),
+		edge.From("dimInterviews", DimInterview.Type).
+			Ref("dimVacancy"),
+		edge.From("dimFeedbacks", DimFeedback.Type).
+			Ref("dimVacancy"),
+		edge.From("dimOffers", DimOffer.Type).
+			Ref("dimVacancy"),
+		edge.From("candidateMilestones", FactCandidateMilestone.Type).
+			Ref("dimVacancy"),
 	}
 }
 
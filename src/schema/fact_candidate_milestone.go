@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// FactCandidateMilestone is an accumulating snapshot of each application
+// of a candidate, identified by the `dbId` of the candidate and the
+// vacancy it applied to. Its milestone dates are filled as the candidate
+// moves forward, and `dimCandidateId` follows the latest version loaded
+// of the candidate.
+type FactCandidateMilestone struct {
+	ent.Schema
+}
+
+func milestoneDate(name string) ent.Field {
+	return field.Other(name, &pgtype.Date{}).SchemaType(map[string]string{
+		dialect.Postgres: "date",
+	}).Optional()
+}
+
+func (FactCandidateMilestone) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("candidateDbId").Immutable(),
+		field.Int("dimVacancyId"),
+		field.Int("dimCandidateId"),
+		milestoneDate("appliedDate"),
+		milestoneDate("screenedDate"),
+		milestoneDate("firstInterviewDate"),
+		milestoneDate("offerDate"),
+		milestoneDate("hiredDate"),
+		milestoneDate("rejectedDate"),
+	}
+}
+
+func (FactCandidateMilestone) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("dimCandidate", DimCandidate.Type).
+			Unique().
+			Required().
+			Field("dimCandidateId"),
+		edge.To("dimVacancy", DimVacancy.Type).
+			Unique().
+			Required().
+			Field("dimVacancyId"),
+	}
+}
+
+func (FactCandidateMilestone) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("candidateDbId", "dimVacancyId").
+			Unique(),
+	}
+}
+
+func (FactCandidateMilestone) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{
+			Table: "fact_candidate_milestone",
+		},
+	}
+}
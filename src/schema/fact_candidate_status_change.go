@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"api5back/src/property"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// FactCandidateStatusChange records each move of a candidate from one
+// status to another. The first status of a candidate has no `fromStatus`.
+type FactCandidateStatusChange struct {
+	ent.Schema
+}
+
+func (FactCandidateStatusChange) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("dimCandidateId").Immutable(),
+		field.Int("dimVacancyId"),
+		field.Enum("fromStatus").
+			GoType(property.DimCandidateStatus(0)).
+			SchemaType(map[string]string{
+				dialect.Postgres: "character varying",
+			}).
+			Optional().
+			Nillable().
+			Immutable(),
+		field.Enum("toStatus").
+			GoType(property.DimCandidateStatus(0)).
+			SchemaType(map[string]string{
+				dialect.Postgres: "character varying",
+			}).
+			Immutable(),
+		field.Time("changedAt").Immutable(),
+	}
+}
+
+func (FactCandidateStatusChange) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("dimCandidate", DimCandidate.Type).
+			Unique().
+			Immutable().
+			Required().
+			Field("dimCandidateId"),
+		edge.To("dimVacancy", DimVacancy.Type).
+			Unique().
+			Required().
+			Field("dimVacancyId"),
+	}
+}
+
+func (FactCandidateStatusChange) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("dimVacancyId", "changedAt"),
+	}
+}
+
+func (FactCandidateStatusChange) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{
+			Table: "fact_candidate_status_change",
+		},
+	}
+}
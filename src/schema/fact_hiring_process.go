@@ -14,9 +14,9 @@ type FactHiringProcess struct {
 
 func (FactHiringProcess) Fields() []ent.Field {
 	return []ent.Field{
-		field.Int("dimProcessId").Immutable(),
-		field.Int("dimVacancyId").Immutable(),
-		field.Int("dimUserId").Immutable(),
+		field.Int("dimProcessId"),
+		field.Int("dimVacancyId"),
+		field.Int("dimUserId"),
 		field.Int("dimDateId").Immutable(),
 		field.Int("metTotalCandidatesApplied"),
 		field.Int("metTotalCandidatesInterviewed"),
@@ -33,17 +33,14 @@ func (FactHiringProcess) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("dimProcess", DimProcess.Type).
 			Unique().
-			Immutable().
 			Required().
 			Field("dimProcessId"),
 		edge.To("dimVacancy", DimVacancy.Type).
 			Unique().
-			Immutable().
 			Required().
 			Field("dimVacancyId"),
 		edge.To("dimUser", DimUser.Type).
 			Unique().
-			Immutable().
 			Required().
 			Field("dimUserId"),
 		edge.To("dimDatetime", DimDatetime.Type).
@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// FactPipelineSnapshot is a daily periodic snapshot of the pipeline of
+// each department and recruiter: the processes and vacancies by status
+// and the candidates by status at `snapshotDate`.
+type FactPipelineSnapshot struct {
+	ent.Schema
+}
+
+func (FactPipelineSnapshot) Fields() []ent.Field {
+	return []ent.Field{
+		field.Other("snapshotDate", &pgtype.Date{}).SchemaType(map[string]string{
+			dialect.Postgres: "date",
+		}).Immutable(),
+		field.Int("dimDepartmentId"),
+		field.Int("dimUserId"),
+		field.Int("numOpenProcesses").Default(0),
+		field.Int("numInProgressProcesses").Default(0),
+		field.Int("numClosedProcesses").Default(0),
+		field.Int("numOpenVacancies").Default(0),
+		field.Int("numInAnalysisVacancies").Default(0),
+		field.Int("numClosedVacancies").Default(0),
+		field.Int("numCandidatesInAnalysis").Default(0),
+		field.Int("numCandidatesInterview").Default(0),
+		field.Int("numCandidatesHired").Default(0),
+		field.Int("numCandidatesRejected").Default(0),
+	}
+}
+
+func (FactPipelineSnapshot) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("dimDepartment", DimDepartment.Type).
+			Unique().
+			Required().
+			Field("dimDepartmentId"),
+		edge.To("dimUser", DimUser.Type).
+			Unique().
+			Required().
+			Field("dimUserId"),
+	}
+}
+
+func (FactPipelineSnapshot) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("snapshotDate", "dimDepartmentId", "dimUserId").
+			Unique(),
+	}
+}
+
+func (FactPipelineSnapshot) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{
+			Table: "fact_pipeline_snapshot",
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SlowlyChangingDimension keeps the history of a dimension as type 2
+// versions: a change to a `dbId` closes its current row and opens a new
+// one. The rows referencing the closed row are moved to the new one, so
+// joins by ID always reach the current version of a `dbId`, and the
+// attributes at a date are found by `dbId` within the validity.
+//
+// A version is valid from `validFrom`, inclusive, to `validTo`,
+// exclusive. Either bound is empty when the version is open on that
+// side, as are the rows loaded before the dimension kept its history.
+type SlowlyChangingDimension struct {
+	mixin.Schema
+}
+
+func (SlowlyChangingDimension) Fields() []ent.Field {
+	return []ent.Field{
+		field.Other("validFrom", &pgtype.Date{}).SchemaType(map[string]string{
+			dialect.Postgres: "date",
+		}).Optional(),
+		field.Other("validTo", &pgtype.Date{}).SchemaType(map[string]string{
+			dialect.Postgres: "date",
+		}).Optional(),
+		field.Bool("isCurrent").
+			Default(true),
+	}
+}
+
+func (SlowlyChangingDimension) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("dbId", "isCurrent"),
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+
+	"api5back/ent"
+	"api5back/src/model"
+	"api5back/src/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Analytics(
+	engine *gin.Engine,
+	dbClient *ent.Client,
+	dwClient *ent.Client,
+) {
+	v1 := engine.Group("/api/v1")
+	{
+		analytics := v1.Group("/analytics")
+		{
+			analytics.POST("/query", AnalyticsQuery(dwClient))
+		}
+
+		metrics := v1.Group("/metrics")
+		{
+			metrics.GET("/catalog", MetricCatalog())
+		}
+	}
+}
+
+// AnalyticsQuery godoc
+// @Summary Dimensional query
+// @Description Aggregate the hiring process measures by the requested dimensions
+// @Tags analytics
+// @Accept json
+// @Param body body model.AnalyticsQuery true "Analytics query"
+// @Produce json
+// @Success 200 {object} model.AnalyticsResult
+// @Router /analytics/query [post]
+func AnalyticsQuery(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var query model.AnalyticsQuery
+		if err := c.ShouldBindJSON(&query); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		result, err := service.RunAnalyticsQuery(
+			c, dwClient,
+			query,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// MetricCatalog godoc
+// @Summary Metric catalog
+// @Description List the definition of every metric shown by the dashboard
+// @Tags analytics
+// @Produce json
+// @Success 200 {object} model.MetricCatalog
+// @Router /metrics/catalog [get]
+func MetricCatalog() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		c.JSON(http.StatusOK, service.GetMetricCatalog())
+	}
+}
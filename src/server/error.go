@@ -1,8 +1,15 @@
 package server
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+
+	"api5back/src/pagination"
+	"api5back/src/service"
+
+	"github.com/gin-gonic/gin"
 )
 
 func DisplayError(err error) string {
@@ -13,3 +20,34 @@ func DisplayError(err error) string {
 		return "Erro"
 	}
 }
+
+// invalidRequestErrors are the errors caused by the request, whose
+// messages are safe to show to the client.
+var invalidRequestErrors = []error{
+	service.ErrInvalidFilter,
+	service.ErrInvalidAnalyticsQuery,
+	pagination.ErrInvalidPageRequest,
+}
+
+// RespondError responds 401 to unauthenticated callers, 403 to requests
+// outside their access group, 400 to the other errors caused by the
+// request and 500 to any other error.
+func RespondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnauthenticated):
+		c.JSON(http.StatusUnauthorized, err.Error())
+		return
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, err.Error())
+		return
+	}
+
+	for _, invalidRequestError := range invalidRequestErrors {
+		if errors.Is(err, invalidRequestError) {
+			c.JSON(http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	c.JSON(http.StatusInternalServerError, DisplayError(err))
+}
@@ -21,6 +21,19 @@ func HiringProcessDashboard(
 		{
 			hiringProcess.POST("/dashboard", Dashboard(dwClient))
 			hiringProcess.POST("/table", VacancyTable(dwClient))
+			hiringProcess.POST("/at-risk", AtRiskProcesses(dwClient))
+			hiringProcess.POST("/candidate-aging", CandidateAgingReport(dwClient))
+			hiringProcess.POST("/candidate-aging/candidates", AgingCandidates(dwClient))
+			hiringProcess.POST("/cohorts", CandidateCohorts(dwClient))
+			hiringProcess.POST("/scores", CandidateScores(dwClient))
+			hiringProcess.POST("/time-in-stage", TimeInStage(dwClient))
+			hiringProcess.POST("/interviews-per-hire", InterviewsPerHire(dwClient))
+			hiringProcess.POST("/feedback-sentiment", FeedbackSentimentTrend(dwClient))
+			hiringProcess.POST("/compensation", CompensationWidgets(dbClient, dwClient))
+			hiringProcess.POST("/pipeline-trend", PipelineTrend(dwClient))
+			hiringProcess.POST("/burndown", PositionBurndown(dwClient))
+			hiringProcess.POST("/timeline", ProcessTimeline(dwClient))
+			hiringProcess.POST("/forecast", HiringForecast(dwClient))
 		}
 
 		suggestions := v1.Group("/suggestions")
@@ -72,7 +85,7 @@ func Dashboard(
 			c, dwClient, dashboardMetricsFilter,
 		)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, DisplayError(err))
+			RespondError(c, err)
 			return
 		}
 
@@ -105,7 +118,7 @@ func UserList(dwClient *ent.Client) func(c *gin.Context) {
 			pageRequest,
 		)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, DisplayError(err))
+			RespondError(c, err)
 			return
 		}
 
@@ -140,7 +153,7 @@ func HiringProcessList(
 			pageRequest,
 		)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, DisplayError(err))
+			RespondError(c, err)
 			return
 		}
 
@@ -175,7 +188,7 @@ func VacancyList(
 			pageRequest,
 		)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, DisplayError(err))
+			RespondError(c, err)
 			return
 		}
 
@@ -188,7 +201,7 @@ func VacancyList(
 // @Description Return a list of vacancies with summarized information
 // @Tags hiring-process
 // @Accept json
-// @Param body body model.FactHiringProcessFilter true "Metrics filter"
+// @Param body body model.VacancyTableFilter true "Vacancy table filter"
 // @Produce json
 // @Success 200 {array} model.Page[model.DashboardTableRow]
 // @Router /hiring-process/table [post]
@@ -198,7 +211,7 @@ func VacancyTable(
 	return func(c *gin.Context) {
 		c.Header("Content-Type", "application/json")
 
-		var filter model.FactHiringProcessFilter
+		var filter model.VacancyTableFilter
 
 		if err := c.ShouldBindJSON(&filter); err != nil {
 			c.JSON(http.StatusBadRequest, DisplayError(err))
@@ -210,7 +223,7 @@ func VacancyTable(
 			filter,
 		)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, DisplayError(err))
+			RespondError(c, err)
 			return
 		}
 
@@ -218,6 +231,181 @@ func VacancyTable(
 	}
 }
 
+// AtRiskProcesses godoc
+// @Summary List at-risk processes
+// @Description Return the processes and vacancies approaching their deadline
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.FactHiringProcessFilter true "Metrics filter"
+// @Produce json
+// @Success 200 {array} model.Page[processing.AtRiskProcess]
+// @Router /hiring-process/at-risk [post]
+func AtRiskProcesses(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.FactHiringProcessFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		atRiskProcesses, err := service.GetAtRiskProcesses(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, atRiskProcesses)
+	}
+}
+
+// CandidateAgingReport godoc
+// @Summary Candidate pipeline aging report
+// @Description Return the candidates stuck in a stage grouped by vacancy and recruiter
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.CandidateAgingFilter true "Candidate aging filter"
+// @Produce json
+// @Success 200 {object} processing.CandidateAgingReport
+// @Router /hiring-process/candidate-aging [post]
+func CandidateAgingReport(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.CandidateAgingFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		report, err := service.GetCandidateAgingReport(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// AgingCandidates godoc
+// @Summary List aging candidates
+// @Description Return a page of the candidates stuck in a stage, the oldest ones first
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.CandidateAgingFilter true "Candidate aging filter"
+// @Produce json
+// @Success 200 {array} model.Page[processing.AgingCandidate]
+// @Router /hiring-process/candidate-aging/candidates [post]
+func AgingCandidates(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.CandidateAgingFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		candidates, err := service.GetAgingCandidates(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, candidates)
+	}
+}
+
+// CandidateCohorts godoc
+// @Summary Candidate cohort matrix
+// @Description Return the share of each application cohort that reached interview, hired or rejected after each horizon
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.CohortFilter true "Cohort filter"
+// @Produce json
+// @Success 200 {object} processing.CohortMatrix
+// @Router /hiring-process/cohorts [post]
+func CandidateCohorts(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.CohortFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		cohortMatrix, err := service.GetCohortMatrix(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, cohortMatrix)
+	}
+}
+
+// CandidateScores godoc
+// @Summary Candidate score analytics
+// @Description Return score distributions per vacancy and process, mean score by status and a suggested screening threshold
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.ScoreFilter true "Score filter"
+// @Produce json
+// @Success 200 {object} processing.ScoreAnalytics
+// @Router /hiring-process/scores [post]
+func CandidateScores(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.ScoreFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		scoreAnalytics, err := service.GetScoreAnalytics(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, scoreAnalytics)
+	}
+}
+
 // ListDepartments godoc
 // @Summary List departments
 // @Description Return a list of departments with id and title
@@ -382,3 +570,301 @@ func CreateUser(client *ent.Client) func(c *gin.Context) {
 		c.JSON(http.StatusCreated, user)
 	}
 }
+
+// TimeInStage godoc
+// @Summary Time in each candidate status
+// @Description Return the average and median days candidates spend in each status, overall and per vacancy, recruiter or department
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.TimeInStageFilter true "Time in stage filter"
+// @Produce json
+// @Success 200 {object} processing.TimeInStage
+// @Router /hiring-process/time-in-stage [post]
+func TimeInStage(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.TimeInStageFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		timeInStage, err := service.GetTimeInStage(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, timeInStage)
+	}
+}
+
+// InterviewsPerHire godoc
+// @Summary Interviews per hire
+// @Description Return the interviews held per candidate hired, overall and per vacancy
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.FactHiringProcessFilter true "Fact hiring process filter"
+// @Produce json
+// @Success 200 {object} processing.InterviewsPerHire
+// @Router /hiring-process/interviews-per-hire [post]
+func InterviewsPerHire(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.FactHiringProcessFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		interviewsPerHire, err := service.GetInterviewsPerHire(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, interviewsPerHire)
+	}
+}
+
+// FeedbackSentimentTrend godoc
+// @Summary Feedback sentiment trend
+// @Description Return the mean sentiment score and the number of positive, neutral and negative feedbacks per month or week
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.FeedbackSentimentFilter true "Feedback sentiment filter"
+// @Produce json
+// @Success 200 {object} processing.FeedbackSentimentTrend
+// @Router /hiring-process/feedback-sentiment [post]
+func FeedbackSentimentTrend(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.FeedbackSentimentFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		trend, err := service.GetFeedbackSentimentTrend(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, trend)
+	}
+}
+
+// CompensationWidgets godoc
+// @Summary Offer and compensation widgets
+// @Description Return the offer acceptance rate, the offer to acceptance time and the average initial salary by department and location, within the departments of the access group of the caller, authenticated by basic auth with its email and password. The access group of the filter can only narrow them down
+// @Tags hiring-process
+// @Accept json
+// @Param Authorization header string true "Basic credentials of the caller"
+// @Param body body model.FactHiringProcessFilter true "Fact hiring process filter"
+// @Produce json
+// @Success 200 {object} processing.CompensationWidgets
+// @Router /hiring-process/compensation [post]
+func CompensationWidgets(
+	dbClient *ent.Client,
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		email, password, ok := c.Request.BasicAuth()
+		if !ok {
+			c.JSON(http.StatusUnauthorized, service.ErrUnauthenticated.Error())
+			return
+		}
+
+		var filter model.FactHiringProcessFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		allowedDepartments, err := service.GetAccessGroupDepartments(
+			c, dbClient,
+			email, password,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		compensationWidgets, err := service.GetCompensationWidgets(
+			c, dwClient,
+			filter,
+			allowedDepartments,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, compensationWidgets)
+	}
+}
+
+// PipelineTrend godoc
+// @Summary Pipeline trend
+// @Description Return the processes, vacancies and candidates by status on the first snapshot of each day, week or month
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.PipelineTrendFilter true "Pipeline trend filter"
+// @Produce json
+// @Success 200 {object} processing.PipelineTrend
+// @Router /hiring-process/pipeline-trend [post]
+func PipelineTrend(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.PipelineTrendFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		trend, err := service.GetPipelineTrend(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, trend)
+	}
+}
+
+// PositionBurndown godoc
+// @Summary Position fill burndown
+// @Description Return the positions remaining over time of each vacancy and process, with the ideal line, the projected completion date and the over and under filled vacancies
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.PositionBurndownFilter true "Position burndown filter"
+// @Produce json
+// @Success 200 {object} processing.PositionBurndown
+// @Router /hiring-process/burndown [post]
+func PositionBurndown(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.PositionBurndownFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		burndown, err := service.GetPositionBurndown(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, burndown)
+	}
+}
+
+// ProcessTimeline godoc
+// @Summary Process timeline
+// @Description Return a page of processes with their vacancies as time intervals, with their status, owner and first hire and deadline markers, to draw a Gantt chart
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.FactHiringProcessFilter true "Fact hiring process filter"
+// @Produce json
+// @Success 200 {object} model.Page[processing.TimelineProcess]
+// @Router /hiring-process/timeline [post]
+func ProcessTimeline(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.FactHiringProcessFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		timeline, err := service.GetProcessTimeline(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, timeline)
+	}
+}
+
+// HiringForecast godoc
+// @Summary Hiring forecast
+// @Description Return the expected fill date of each open vacancy, with its confidence interval, from the time to fill of similar vacancies, and the hires per week forecast of their departments
+// @Tags hiring-process
+// @Accept json
+// @Param body body model.HiringForecastFilter true "Hiring forecast filter"
+// @Produce json
+// @Success 200 {object} processing.HiringForecast
+// @Router /hiring-process/forecast [post]
+func HiringForecast(
+	dwClient *ent.Client,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+
+		var filter model.HiringForecastFilter
+
+		if err := c.ShouldBindJSON(&filter); err != nil {
+			c.JSON(http.StatusBadRequest, DisplayError(err))
+			return
+		}
+
+		forecast, err := service.GetHiringForecast(
+			c, dwClient,
+			filter,
+		)
+		if err != nil {
+			RespondError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, forecast)
+	}
+}
@@ -24,6 +24,7 @@ func NewServer(
 		Swagger,
 		Base,
 		HiringProcessDashboard,
+		Analytics,
 	} {
 		endpointGroups(
 			engine,
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/analytics"
+	"api5back/src/model"
+	"api5back/src/processing"
+)
+
+// ErrInvalidAnalyticsQuery is returned when the query does not validate
+// against the whitelisted fields, operators and limits.
+var ErrInvalidAnalyticsQuery = errors.New("invalid analytics query")
+
+func RunAnalyticsQuery(
+	ctx context.Context,
+	client *ent.Client,
+	query model.AnalyticsQuery,
+) (*model.AnalyticsResult, error) {
+	statement, err := analytics.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: %w",
+			ErrInvalidAnalyticsQuery,
+			err,
+		)
+	}
+
+	rows, err := client.QueryContext(ctx, statement.SQL, statement.Args...)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not execute analytics query: %w",
+			err,
+		)
+	}
+	defer rows.Close()
+
+	result := &model.AnalyticsResult{
+		Columns: statement.Columns,
+		Rows:    [][]any{},
+	}
+
+	for rows.Next() {
+		values := make([]any, len(statement.Columns))
+		pointers := make([]any, len(statement.Columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf(
+				"could not scan analytics query row: %w",
+				err,
+			)
+		}
+
+		for i, value := range values {
+			if date, ok := value.(time.Time); ok {
+				values[i] = date.Format("2006-01-02")
+			}
+		}
+
+		result.Rows = append(result.Rows, values)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"could not read analytics query rows: %w",
+			err,
+		)
+	}
+
+	return result, nil
+}
+
+func GetMetricCatalog() *model.MetricCatalog {
+	return &model.MetricCatalog{
+		Version: processing.MetricCatalogVersion,
+		Metrics: processing.MetricCatalog,
+	}
+}
@@ -10,6 +10,15 @@ import (
 	"api5back/src/model"
 )
 
+var (
+	// ErrUnauthenticated is returned when the credentials of the caller
+	// do not match any user.
+	ErrUnauthenticated = errors.New("invalid email or password")
+	// ErrForbidden is returned when the caller asks for data outside the
+	// departments of its access group.
+	ErrForbidden = errors.New("forbidden")
+)
+
 type UserResponse struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
@@ -104,6 +113,41 @@ func Login(
 	return response, nil
 }
 
+// GetAccessGroupDepartments authenticates the caller by its email and
+// password, like `Login`, and returns the IDs of the departments of its
+// access group, the ones it is allowed to see.
+func GetAccessGroupDepartments(
+	ctx context.Context,
+	client *ent.Client,
+	email string,
+	password string,
+) ([]int, error) {
+	user, err := client.
+		Authentication.
+		Query().
+		Where(
+			authentication.Email(email),
+			authentication.Password(password),
+		).
+		WithAccessGroup(func(gaq *ent.AccessGroupQuery) {
+			gaq.WithDepartment()
+		}).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return nil, ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+
+	var departmentIds []int
+	for _, dept := range user.Edges.AccessGroup.Edges.Department {
+		departmentIds = append(departmentIds, dept.ID)
+	}
+
+	return departmentIds, nil
+}
+
 func CreateUser(
 	ctx context.Context,
 	client *ent.Client,
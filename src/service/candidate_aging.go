@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/model"
+	"api5back/src/pagination"
+	"api5back/src/processing"
+	"api5back/src/property"
+)
+
+func parseCandidateAgingRule(
+	filter model.CandidateAgingFilter,
+) (processing.CandidateAgingRule, error) {
+	if filter.MinDays < 0 {
+		return processing.CandidateAgingRule{}, fmt.Errorf(
+			"%w: invalid `MinDays`, expected a non negative value",
+			ErrInvalidFilter,
+		)
+	}
+
+	rule := processing.CandidateAgingRule{
+		Stages:        processing.DefaultCandidateAgingStages,
+		MinDays:       filter.MinDays,
+		ReferenceDate: time.Now(),
+	}
+
+	if len(filter.Stages) > 0 {
+		rule.Stages = []property.DimCandidateStatus{}
+		for _, stage := range filter.Stages {
+			if stage < int(property.DimCandidateStatusInAnalysis) ||
+				stage > int(property.DimCandidateStatusRejected) {
+				return processing.CandidateAgingRule{}, fmt.Errorf(
+					"%w: invalid candidate stage: %d",
+					ErrInvalidFilter,
+					stage,
+				)
+			}
+			rule.Stages = append(rule.Stages, property.DimCandidateStatus(stage))
+		}
+	}
+
+	if filter.ReferenceDate != "" {
+		referenceDate, err := time.Parse("2006-01-02", filter.ReferenceDate)
+		if err != nil {
+			return processing.CandidateAgingRule{}, fmt.Errorf(
+				"%w: could not parse `ReferenceDate`: %w",
+				ErrInvalidFilter,
+				err,
+			)
+		}
+		rule.ReferenceDate = referenceDate
+	}
+
+	return rule, nil
+}
+
+func getAgingCandidates(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.CandidateAgingFilter,
+) ([]processing.AgingCandidate, error) {
+	rule, err := parseCandidateAgingRule(filter)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not parse candidate aging rule: %w",
+			err,
+		)
+	}
+
+	rule.Days, err = parseDayCounter(ctx, client, filter.DurationUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory).
+			WithDimUser(),
+		filter.FactHiringProcessFilter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	agingCandidates, err := processing.GenerateAgingCandidates(
+		factHiringProcesses,
+		rule,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate aging candidates: %w",
+			err,
+		)
+	}
+
+	return agingCandidates, nil
+}
+
+func GetCandidateAgingReport(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.CandidateAgingFilter,
+) (*processing.CandidateAgingReport, error) {
+	agingCandidates, err := getAgingCandidates(ctx, client, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := processing.GenerateCandidateAgingReport(agingCandidates)
+
+	return &report, nil
+}
+
+func GetAgingCandidates(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.CandidateAgingFilter,
+) (*model.Page[processing.AgingCandidate], error) {
+	request, err := pagination.ParseRequest(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	agingCandidates, err := getAgingCandidates(ctx, client, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return pagination.PaginateRequest(
+		agingCandidates,
+		request,
+		func(item processing.AgingCandidate) []any {
+			// negated, as the oldest candidates come first
+			return []any{-item.DaysInStage, item.VacancyId, item.CandidateId, item.DimCandidateId}
+		},
+	)
+}
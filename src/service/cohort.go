@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/ent/factcandidatestatuschange"
+	"api5back/src/model"
+	"api5back/src/processing"
+)
+
+func GetCohortMatrix(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.CohortFilter,
+) (*processing.CohortMatrix, error) {
+	granularity := filter.Granularity
+	if granularity == "" {
+		granularity = processing.CohortGranularityMonth
+	}
+
+	if err := processing.ValidateCohortGranularity(granularity); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	horizons := processing.DefaultCohortHorizons
+	if len(filter.Horizons) > 0 {
+		for _, horizon := range filter.Horizons {
+			if horizon <= 0 {
+				return nil, fmt.Errorf(
+					"%w: invalid cohort horizon: %d",
+					ErrInvalidFilter,
+					horizon,
+				)
+			}
+		}
+		horizons = filter.Horizons
+	}
+
+	days, err := parseDayCounter(ctx, client, filter.DurationUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory),
+		filter.FactHiringProcessFilter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	candidates, err := processing.UniqueFactCandidates(factHiringProcesses)
+	if err != nil {
+		return nil, err
+	}
+
+	vacancyIds := make([]int, len(factHiringProcesses))
+	for i, factHiringProcess := range factHiringProcesses {
+		vacancyIds[i] = factHiringProcess.DimVacancyId
+	}
+
+	statusChanges, err := client.FactCandidateStatusChange.
+		Query().
+		Where(factcandidatestatuschange.DimVacancyIdIn(vacancyIds...)).
+		WithDimCandidate().
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactCandidateStatusChange` data: %w",
+			err,
+		)
+	}
+
+	cohortMatrix, err := processing.GenerateCohortMatrix(
+		candidates,
+		statusChanges,
+		granularity,
+		horizons,
+		days,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate cohort matrix: %w",
+			err,
+		)
+	}
+
+	return &cohortMatrix, nil
+}
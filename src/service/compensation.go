@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/ent/dimoffer"
+	"api5back/src/model"
+	"api5back/src/processing"
+)
+
+// GetCompensationWidgets computes the offer and initial salary widgets.
+// Salaries and offers are only read from the facts within the
+// departments the caller is allowed to see, `allowedDepartments`, the
+// departments of its access group, see `GetAccessGroupDepartments`.
+// `AccessGroups` narrows them down, defaulting to all of them; asking
+// for a department outside of them is forbidden. Unlike the other
+// widgets, it fails closed: a caller without departments sees nothing.
+func GetCompensationWidgets(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.FactHiringProcessFilter,
+	allowedDepartments []int,
+) (*processing.CompensationWidgets, error) {
+	accessGroups, err := scopeAccessGroups(filter.AccessGroups, allowedDepartments)
+	if err != nil {
+		return nil, err
+	}
+	filter.AccessGroups = accessGroups
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory).
+			WithDimProcess(func(query *ent.DimProcessQuery) {
+				query.WithDimDepartment()
+			}),
+		filter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	days, err := parseDayCounter(ctx, client, filter.DurationUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	byDepartment, byLocation, err := processing.GenerateAverageSalaryInitial(factHiringProcesses)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate average initial salary: %w",
+			err,
+		)
+	}
+
+	var vacancyIds []int
+	visitedVacancies := make(map[int]bool)
+	for _, factHiringProcess := range factHiringProcesses {
+		if !visitedVacancies[factHiringProcess.DimVacancyId] {
+			visitedVacancies[factHiringProcess.DimVacancyId] = true
+			vacancyIds = append(vacancyIds, factHiringProcess.DimVacancyId)
+		}
+	}
+
+	offers, err := client.DimOffer.
+		Query().
+		Where(
+			dimoffer.DimVacancyIdIn(vacancyIds...),
+			dimoffer.IsCurrent(true),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `DimOffer` data: %w",
+			err,
+		)
+	}
+
+	return &processing.CompensationWidgets{
+		OfferAcceptance:                  processing.GenerateOfferAcceptance(offers),
+		OfferToAcceptanceTime:            processing.GenerateOfferToAcceptanceTime(offers, days),
+		AverageSalaryInitialByDepartment: byDepartment,
+		AverageSalaryInitialByLocation:   byLocation,
+	}, nil
+}
+
+// scopeAccessGroups returns the requested departments, or every allowed
+// one when none is requested, and fails with `ErrForbidden` when any of
+// them is not allowed.
+func scopeAccessGroups(requested []int, allowed []int) ([]int, error) {
+	if len(allowed) == 0 {
+		return nil, fmt.Errorf("%w: the access group of the caller has no departments", ErrForbidden)
+	}
+	if len(requested) == 0 {
+		return allowed, nil
+	}
+
+	isAllowed := make(map[int]bool, len(allowed))
+	for _, departmentId := range allowed {
+		isAllowed[departmentId] = true
+	}
+
+	var outside []int
+	for _, departmentId := range requested {
+		if !isAllowed[departmentId] {
+			outside = append(outside, departmentId)
+		}
+	}
+	if len(outside) > 0 {
+		return nil, fmt.Errorf(
+			"%w: departments %v are outside the access group of the caller",
+			ErrForbidden,
+			outside,
+		)
+	}
+
+	return requested, nil
+}
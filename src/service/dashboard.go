@@ -2,42 +2,55 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"api5back/ent"
-	"api5back/ent/dimdepartment"
+	"api5back/ent/dimdatetime"
 	"api5back/ent/dimprocess"
-	"api5back/ent/dimuser"
 	"api5back/ent/dimvacancy"
 	"api5back/ent/facthiringprocess"
+	"api5back/ent/predicate"
+	"api5back/src/expression"
 	"api5back/src/model"
-	"api5back/src/pagination"
 	"api5back/src/processing"
 	"api5back/src/property"
+	"api5back/src/warehouse"
 )
 
+// createFactHiringProcessBaseQuery eager loads the dimensions of the
+// facts, with the latest version of each candidate or, when
+// `candidateHistory` is set, every version loaded.
 func createFactHiringProcessBaseQuery(
 	client *ent.Client,
+	candidateHistory bool,
 ) *ent.FactHiringProcessQuery {
 	return client.
 		FactHiringProcess.
 		Query().
 		WithDimProcess().
 		WithDimVacancy(func(query *ent.DimVacancyQuery) {
-			query.WithDimCandidates(func(query *ent.DimCandidateQuery) {
-				// query.
-				// 	Order(
-				// 		ent.Desc(hiringprocesscandidate.FieldDbId),
-				// 		ent.Desc(hiringprocesscandidate.FieldID),
-				// 	).
-				// 	Modify(func(s *sql.Selector) {
-				// 		s.Select("DISTINCT ON (db_id) *")
-				// 	})
-			})
+			query.WithDimCandidates(withDimCandidates(candidateHistory))
 		})
 }
 
+// withDimCandidates eager loads the latest version of each candidate or,
+// when `candidateHistory` is set, every version loaded.
+func withDimCandidates(
+	candidateHistory bool,
+) func(query *ent.DimCandidateQuery) {
+	return func(query *ent.DimCandidateQuery) {
+		if !candidateHistory {
+			query.Where(warehouse.LatestDimCandidate)
+		}
+	}
+}
+
+// ErrInvalidFilter is returned when the filter expression of a request
+// does not validate against the whitelisted fields and operators.
+var ErrInvalidFilter = errors.New("invalid filter")
+
 func applyFactHiringProcessQueryFilters(
 	query *ent.FactHiringProcessQuery,
 	filter model.FactHiringProcessFilter,
@@ -46,7 +59,7 @@ func applyFactHiringProcessQueryFilters(
 		query = query.Where(
 			facthiringprocess.HasDimProcessWith(
 				dimprocess.HasDimDepartmentWith(
-					dimdepartment.IDIn(filter.AccessGroups...),
+					predicate.DimDepartment(warehouse.SameDbId(filter.AccessGroups...)),
 				),
 			),
 		)
@@ -54,14 +67,14 @@ func applyFactHiringProcessQueryFilters(
 	if filter.Recruiters != nil && len(filter.Recruiters) > 0 {
 		query = query.Where(
 			facthiringprocess.HasDimUserWith(
-				dimuser.IDIn(filter.Recruiters...),
+				predicate.DimUser(warehouse.SameDbId(filter.Recruiters...)),
 			),
 		)
 	}
 	if filter.Processes != nil && len(filter.Processes) > 0 {
 		query = query.Where(
 			facthiringprocess.HasDimProcessWith(
-				dimprocess.IDIn(filter.Processes...),
+				predicate.DimProcess(warehouse.SameDbId(filter.Processes...)),
 			),
 		)
 	}
@@ -69,53 +82,41 @@ func applyFactHiringProcessQueryFilters(
 	if filter.Vacancies != nil && len(filter.Vacancies) > 0 {
 		query = query.Where(
 			facthiringprocess.HasDimVacancyWith(
-				dimvacancy.IDIn(filter.Vacancies...),
+				predicate.DimVacancy(warehouse.SameDbId(filter.Vacancies...)),
 			),
 		)
 	}
 
 	if filter.DateRange != nil {
-		if filter.DateRange.StartDate != "" {
-			hiringProcessStartDate, err := processing.ParseStringToPgtypeDate(
-				"2006-01-02",
-				filter.DateRange.StartDate,
-			)
-			if err != nil {
-				return nil, fmt.Errorf(
-					"could not parse `StartDate`: %w",
-					err,
-				)
-			}
-
-			query = query.Where(
-				facthiringprocess.HasDimVacancyWith(
-					dimvacancy.ClosingDateGTE(
-						&hiringProcessStartDate,
-					),
-				),
-			)
+		dateRangePredicate, err := dateRangePredicate(*filter.DateRange)
+		if err != nil {
+			return nil, err
 		}
 
-		if filter.DateRange.EndDate != "" {
-			hiringProcessEndDate, err := processing.ParseStringToPgtypeDate(
-				"2006-01-02",
-				filter.DateRange.EndDate,
-			)
-			if err != nil {
-				return nil, fmt.Errorf(
-					"could not parse `EndDate`: %w",
-					err,
-				)
-			}
-
-			query = query.Where(
-				facthiringprocess.HasDimVacancyWith(
-					dimvacancy.OpeningDateLTE(
-						&hiringProcessEndDate,
-					),
-				),
+		query = query.Where(dateRangePredicate)
+	}
+
+	if filter.AsOf != "" {
+		asOf, err := processing.ParseStringToPgtypeDate("2006-01-02", filter.AsOf)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: could not parse `AsOf`: %w",
+				ErrInvalidFilter,
+				err,
 			)
 		}
+
+		query = query.Where(
+			facthiringprocess.HasDimDatetimeWith(dimdatetime.DateLTE(&asOf)),
+			facthiringprocess.HasDimProcessWith(
+				predicate.DimProcess(warehouse.HasVersionAt(&asOf)),
+				dimprocess.HasDimDepartmentWith(
+					predicate.DimDepartment(warehouse.HasVersionAt(&asOf)),
+				),
+			),
+			facthiringprocess.HasDimVacancyWith(predicate.DimVacancy(warehouse.HasVersionAt(&asOf))),
+			facthiringprocess.HasDimUserWith(predicate.DimUser(warehouse.HasVersionAt(&asOf))),
+		)
 	}
 
 	if filter.ProcessStatus != nil && len(filter.ProcessStatus) > 0 {
@@ -144,16 +145,142 @@ func applyFactHiringProcessQueryFilters(
 		)
 	}
 
+	if filter.Where != nil {
+		predicate, err := expression.Compile(*filter.Where)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: %w",
+				ErrInvalidFilter,
+				err,
+			)
+		}
+
+		query = query.Where(predicate)
+	}
+
 	return query, nil
 }
 
+func createComparisonDateRange(
+	comparison processing.ComparisonPeriod,
+	dateRange *model.DateRange,
+) (*model.DateRange, error) {
+	if dateRange == nil || dateRange.StartDate == "" || dateRange.EndDate == "" {
+		return nil, fmt.Errorf(
+			"%w: comparison `%s` requires both `StartDate` and `EndDate`",
+			ErrInvalidFilter,
+			comparison,
+		)
+	}
+
+	bounds, err := processing.ParseDateBounds(
+		dateRange.DateField,
+		dateRange.Match,
+		dateRange.StartDate,
+		dateRange.EndDate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	comparisonStartDate, comparisonEndDate, err := processing.ShiftPeriod(
+		comparison,
+		bounds.Start.Time,
+		bounds.End.Time,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	return &model.DateRange{
+		StartDate: comparisonStartDate.Format("2006-01-02"),
+		EndDate:   comparisonEndDate.Format("2006-01-02"),
+		DateField: bounds.Field,
+		Match:     bounds.Match,
+	}, nil
+}
+
 func GetMetrics(
 	ctx context.Context,
 	client *ent.Client,
 	filter model.FactHiringProcessFilter,
 ) (*model.DashboardMetrics, error) {
+	metrics, err := computeMetrics(ctx, client, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Comparison == nil {
+		return metrics, nil
+	}
+
+	comparisonDateRange, err := createComparisonDateRange(
+		*filter.Comparison,
+		filter.DateRange,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not create comparison `DateRange`: %w",
+			err,
+		)
+	}
+
+	comparisonFilter := filter
+	comparisonFilter.DateRange = comparisonDateRange
+	comparisonFilter.Comparison = nil
+
+	// the deadlines of the comparison period are checked as of its end,
+	// not as of today
+	comparisonDeadline := model.DeadlineFilter{}
+	if filter.Deadline != nil {
+		comparisonDeadline = *filter.Deadline
+	}
+	comparisonDeadline.ReferenceDate = comparisonDateRange.EndDate
+	comparisonFilter.Deadline = &comparisonDeadline
+
+	comparisonMetrics, err := computeMetrics(ctx, client, comparisonFilter)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not compute comparison metrics: %w",
+			err,
+		)
+	}
+
+	metrics.Comparison = &model.DashboardComparison{
+		Period:    *filter.Comparison,
+		DateRange: *comparisonDateRange,
+		CardInfos: processing.CompareCardInfos(
+			metrics.CardInfos,
+			comparisonMetrics.CardInfos,
+		),
+		VacancySummary: processing.CompareVacancyStatusSummary(
+			metrics.VacancySummary,
+			comparisonMetrics.VacancySummary,
+		),
+	}
+
+	return metrics, nil
+}
+
+func computeMetrics(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.FactHiringProcessFilter,
+) (*model.DashboardMetrics, error) {
+	bucketWidth := processing.DefaultHiringTimeBucketWidth
+	if filter.HiringTimeBucketWidth != nil {
+		if *filter.HiringTimeBucketWidth <= 0 {
+			return nil, fmt.Errorf(
+				"%w: invalid `HiringTimeBucketWidth`: %d, expected a positive value",
+				ErrInvalidFilter,
+				*filter.HiringTimeBucketWidth,
+			)
+		}
+		bucketWidth = *filter.HiringTimeBucketWidth
+	}
+
 	query, err := applyFactHiringProcessQueryFilters(
-		createFactHiringProcessBaseQuery(client),
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory),
 		filter,
 	)
 	if err != nil {
@@ -171,9 +298,26 @@ func GetMetrics(
 		)
 	}
 
+	deadlineRule, err := parseDeadlineRule(filter.Deadline)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not parse `Deadline` rule: %w",
+			err,
+		)
+	}
+
+	days, err := parseDayCounter(ctx, client, filter.DurationUnit)
+	if err != nil {
+		return nil, err
+	}
+
 	var errors []error
 
-	cardInfo, err := processing.ComputingCardsInfo(hiringProcesses)
+	cardInfo, err := processing.ComputingCardsInfo(
+		hiringProcesses,
+		deadlineRule,
+		days,
+	)
 	if err != nil {
 		errors = append(errors, fmt.Errorf(
 			"could not calculate `CardInfo` data: %w",
@@ -196,7 +340,7 @@ func GetMetrics(
 		}
 	}
 
-	averageHiringTime, err := processing.GenerateAverageHiringTimePerMonth(dimVacancies)
+	averageHiringTime, err := processing.GenerateAverageHiringTimePerMonth(dimVacancies, days)
 	if err != nil {
 		errors = append(errors, fmt.Errorf(
 			"could not generate `AvgHiringTime` data: %w",
@@ -204,6 +348,22 @@ func GetMetrics(
 		))
 	}
 
+	var dimCandidates []*ent.DimCandidate
+	for _, dimVacancy := range dimVacancies {
+		dimCandidates = append(dimCandidates, dimVacancy.Edges.DimCandidates...)
+	}
+
+	hiringTime, err := processing.GenerateHiringTimeStatistics(
+		processing.HiredCandidateDurations(dimCandidates, days),
+		bucketWidth,
+	)
+	if err != nil {
+		errors = append(errors, fmt.Errorf(
+			"could not generate `HiringTime` statistics: %w",
+			err,
+		))
+	}
+
 	if len(errors) > 0 {
 		var sb strings.Builder
 		sb.WriteString("failed to get metrics due to the following errors:\n")
@@ -212,101 +372,13 @@ func GetMetrics(
 			sb.WriteString(fmt.Sprintf("\t[%d] %s\n", i+1, err))
 		}
 
-		return nil, fmt.Errorf(sb.String())
+		return nil, fmt.Errorf("%s", sb.String())
 	}
 
 	return &model.DashboardMetrics{
 		CardInfos:         cardInfo,
 		VacancySummary:    vacancyInfo,
 		AverageHiringTime: averageHiringTime,
-	}, nil
-}
-
-func GetVacancyTable(
-	ctx context.Context,
-	client *ent.Client,
-	filter model.FactHiringProcessFilter,
-) (*model.Page[model.DashboardTableRow], error) {
-	query, err := applyFactHiringProcessQueryFilters(
-		createFactHiringProcessBaseQuery(client),
-		filter,
-	)
-	if err != nil {
-		return nil, fmt.Errorf(
-			"could not apply filters: %w",
-			err,
-		)
-	}
-
-	page, pageSize, err := pagination.ParsePageRequest(filter)
-	if err != nil {
-		return nil, err
-	}
-
-	totalRecords, err := query.Count(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	offset, numMaxPages := processing.ParseOffsetAndTotalPages(
-		page,
-		pageSize,
-		totalRecords,
-	)
-
-	factHiringProcesses, err := query.
-		Offset(offset).
-		Limit(pageSize).
-		All(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	var tableDatas []model.DashboardTableRow
-	for _, factHiringProcess := range factHiringProcesses {
-		dimVacancy, err := factHiringProcess.
-			Edges.
-			DimVacancyOrErr()
-		if err != nil {
-			return nil, fmt.Errorf(
-				"nil `DimVacancy` for `FactHiringProcess` with ID %d: %w",
-				factHiringProcess.ID, err,
-			)
-		}
-
-		numPositions := dimVacancy.NumPositions
-		var competitionRate *float32
-		if numPositions > 0 {
-			rate := float32(factHiringProcess.MetTotalCandidatesApplied) / float32(numPositions)
-			competitionRate = &rate
-		} else {
-			competitionRate = nil
-		}
-
-		hiringTime, err := processing.GenerateAverageHiringTimePerFactHiringProcess(factHiringProcess)
-		var averageHiringTime *float32
-		if err != nil {
-			averageHiringTime = nil
-		} else {
-			averageHiringTime = &(hiringTime)
-		}
-
-		numFeedback := factHiringProcess.MetTotalFeedbackPositive + factHiringProcess.MetTotalNegative + factHiringProcess.MetTotalNeutral
-		tableDatas = append(tableDatas, model.DashboardTableRow{
-			ProcessTitle:      factHiringProcess.Edges.DimProcess.Title,
-			VacancyTitle:      factHiringProcess.Edges.DimVacancy.Title,
-			NumPositions:      numPositions,
-			NumCandidates:     factHiringProcess.MetTotalCandidatesApplied,
-			CompetitionRate:   competitionRate,
-			NumInterviewed:    factHiringProcess.MetTotalCandidatesInterviewed,
-			NumHired:          factHiringProcess.MetTotalCandidatesHired,
-			AverageHiringTime: averageHiringTime,
-			NumFeedback:       numFeedback,
-		})
-	}
-
-	return &model.Page[model.DashboardTableRow]{
-		Items:       tableDatas,
-		NumMaxPages: numMaxPages,
+		HiringTime:        hiringTime,
 	}, nil
 }
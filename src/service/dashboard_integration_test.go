@@ -6,10 +6,15 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
+	"api5back/ent"
 	"api5back/seeds"
 	"api5back/src/database"
 	"api5back/src/model"
+	"api5back/src/processing"
+	"api5back/src/property"
+	"api5back/src/warehouse"
 
 	"github.com/stretchr/testify/require"
 )
@@ -49,9 +54,560 @@ func TestDashboard(t *testing.T) {
 		require.NotEmpty(t, metricsData.CardInfos)
 		require.NotNil(t, metricsData.VacancySummary)
 		require.NotNil(t, metricsData.AverageHiringTime)
+
+		bucketWidth := 0
+		_, err = GetMetrics(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{HiringTimeBucketWidth: &bucketWidth},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
 	}); !testResult {
 		t.Fatalf("GetMetrics test failed")
 	}
+
+	if testResult := t.Run("GetMetrics compares against the previous period", func(t *testing.T) {
+		comparison := processing.ComparisonPeriodPrevious
+		deadline := &model.DeadlineFilter{ReferenceDate: "2024-12-31"}
+
+		metricsData, err := GetMetrics(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{
+				DateRange: &model.DateRange{
+					StartDate: "2024-07-01",
+					EndDate:   "2024-12-31",
+				},
+				Comparison: &comparison,
+				Deadline:   deadline,
+			},
+		)
+		require.NoError(t, err)
+		require.NotNil(t, metricsData.Comparison)
+		require.Equal(t, "2024-06-30", metricsData.Comparison.DateRange.EndDate)
+		require.Equal(t, "2024-12-31", deadline.ReferenceDate, "the filter is left untouched")
+	}); !testResult {
+		t.Fatalf("GetMetrics comparison test failed")
+	}
+
+	if testResult := t.Run("GetTimeInStage groups the seeded status changes", func(t *testing.T) {
+		for _, groupBy := range []string{
+			processing.TimeInStageGroupByVacancy,
+			processing.TimeInStageGroupByRecruiter,
+			processing.TimeInStageGroupByDepartment,
+		} {
+			timeInStage, err := GetTimeInStage(
+				ctx, intEnv.Client,
+				model.TimeInStageFilter{GroupBy: groupBy},
+			)
+			require.NoError(t, err)
+			require.NotEmpty(t, timeInStage.Stages)
+			require.NotEmpty(t, timeInStage.Groups)
+		}
+
+		_, err := GetTimeInStage(
+			ctx, intEnv.Client,
+			model.TimeInStageFilter{GroupBy: "location"},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	}); !testResult {
+		t.Fatalf("GetTimeInStage test failed")
+	}
+
+	if testResult := t.Run("Interview and feedback reports use the seeded detail rows", func(t *testing.T) {
+		interviewsPerHire, err := GetInterviewsPerHire(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{},
+		)
+		require.NoError(t, err)
+		require.NotZero(t, interviewsPerHire.NumInterviews)
+		require.NotNil(t, interviewsPerHire.InterviewsPerHire)
+
+		trend, err := GetFeedbackSentimentTrend(
+			ctx, intEnv.Client,
+			model.FeedbackSentimentFilter{},
+		)
+		require.NoError(t, err)
+		require.NotEmpty(t, trend.Periods)
+
+		_, err = GetFeedbackSentimentTrend(
+			ctx, intEnv.Client,
+			model.FeedbackSentimentFilter{Granularity: "year"},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+
+		updated, err := warehouse.DeriveFactHiringProcessCounters(ctx, intEnv.Client)
+		require.NoError(t, err)
+		require.Zero(t, updated, "the seeded counters are already derived")
+	}); !testResult {
+		t.Fatalf("Interview and feedback test failed")
+	}
+
+	if testResult := t.Run("Compensation widgets respect the access group", func(t *testing.T) {
+		departmentIds, err := intEnv.Client.DimDepartment.Query().IDs(ctx)
+		require.NoError(t, err)
+
+		_, err = GetCompensationWidgets(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{},
+			nil,
+		)
+		require.ErrorIs(t, err, ErrForbidden, "a caller without departments sees nothing")
+
+		compensationWidgets, err := GetCompensationWidgets(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{},
+			departmentIds,
+		)
+		require.NoError(t, err)
+		require.NotZero(t, compensationWidgets.OfferAcceptance.NumAccepted)
+		require.NotNil(t, compensationWidgets.OfferAcceptance.AcceptanceRate)
+		require.NotZero(t, compensationWidgets.OfferToAcceptanceTime.Count)
+		require.Greater(t, len(compensationWidgets.AverageSalaryInitialByDepartment), 1)
+
+		scopedWidgets, err := GetCompensationWidgets(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{},
+			[]int{1},
+		)
+		require.NoError(t, err)
+		require.Len(t, scopedWidgets.AverageSalaryInitialByDepartment, 1)
+		require.Less(
+			t,
+			scopedWidgets.OfferAcceptance.NumOffers,
+			compensationWidgets.OfferAcceptance.NumOffers,
+		)
+
+		narrowedWidgets, err := GetCompensationWidgets(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{AccessGroups: []int{1}},
+			departmentIds,
+		)
+		require.NoError(t, err)
+		require.Equal(t, scopedWidgets, narrowedWidgets)
+
+		_, err = GetCompensationWidgets(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{AccessGroups: departmentIds},
+			[]int{1},
+		)
+		require.ErrorIs(t, err, ErrForbidden, "the filter cannot widen the access group")
+	}); !testResult {
+		t.Fatalf("Compensation test failed")
+	}
+
+	if testResult := t.Run("Pipeline trend reads the monthly snapshots", func(t *testing.T) {
+		trend, err := GetPipelineTrend(
+			ctx, intEnv.Client,
+			model.PipelineTrendFilter{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-12-31",
+			},
+		)
+		require.NoError(t, err)
+		require.NotEmpty(t, trend.Points)
+		for _, point := range trend.Points {
+			require.Equal(t, point.Period+"-01", point.Date)
+		}
+
+		_, err = GetPipelineTrend(
+			ctx, intEnv.Client,
+			model.PipelineTrendFilter{Granularity: "year"},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+
+		_, err = GetPipelineTrend(
+			ctx, intEnv.Client,
+			model.PipelineTrendFilter{StartDate: "2024-12-31", EndDate: "2024-01-01"},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	}); !testResult {
+		t.Fatalf("Pipeline trend test failed")
+	}
+
+	if testResult := t.Run("Position burndown covers every vacancy", func(t *testing.T) {
+		burndown, err := GetPositionBurndown(
+			ctx, intEnv.Client,
+			model.PositionBurndownFilter{ReferenceDate: "2024-12-31"},
+		)
+		require.NoError(t, err)
+		require.Equal(t, "2024-12-31", burndown.ReferenceDate)
+		require.NotEmpty(t, burndown.Processes)
+
+		for _, process := range burndown.Processes {
+			numPositions, numHired := 0, 0
+			for _, vacancy := range process.Vacancies {
+				require.NotEmpty(t, vacancy.Points)
+				require.Equal(t, vacancy.Remaining, vacancy.Points[len(vacancy.Points)-1].Remaining)
+				numPositions += vacancy.NumPositions
+				numHired += vacancy.NumHired
+			}
+			require.Equal(t, numPositions, process.NumPositions)
+			require.Equal(t, numHired, process.NumHired)
+		}
+
+		_, err = GetPositionBurndown(
+			ctx, intEnv.Client,
+			model.PositionBurndownFilter{ReferenceDate: "31/12/2024"},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	}); !testResult {
+		t.Fatalf("Position burndown test failed")
+	}
+
+	if testResult := t.Run("Process timeline pages by process", func(t *testing.T) {
+		pageSize := 2
+		firstPage, err := GetProcessTimeline(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{
+				PageRequest: &model.PageRequest{PageSize: &pageSize},
+			},
+		)
+		require.NoError(t, err)
+		require.Len(t, firstPage.Items, pageSize)
+		require.NotNil(t, firstPage.NextCursor)
+
+		for _, process := range firstPage.Items {
+			require.NotEmpty(t, process.StartDate)
+			require.NotEmpty(t, process.Vacancies)
+		}
+
+		secondPage, err := GetProcessTimeline(
+			ctx, intEnv.Client,
+			model.FactHiringProcessFilter{
+				PageRequest: &model.PageRequest{
+					PageSize: &pageSize,
+					Cursor:   firstPage.NextCursor,
+				},
+			},
+		)
+		require.NoError(t, err)
+		require.NotEmpty(t, secondPage.Items)
+		require.NotEqual(t, firstPage.Items[0].Id, secondPage.Items[0].Id)
+		require.LessOrEqual(t, firstPage.Items[pageSize-1].StartDate, secondPage.Items[0].StartDate)
+	}); !testResult {
+		t.Fatalf("Process timeline test failed")
+	}
+
+	if testResult := t.Run("Hiring forecast is deterministic on the seeded history", func(t *testing.T) {
+		filter := model.HiringForecastFilter{ReferenceDate: "2024-12-31"}
+
+		forecast, err := GetHiringForecast(ctx, intEnv.Client, filter)
+		require.NoError(t, err)
+		require.Equal(t, "2024-12-31", forecast.ReferenceDate)
+		require.NotEmpty(t, forecast.Departments)
+
+		for _, department := range forecast.Departments {
+			require.Len(t, department.History, processing.DefaultForecastHistoryWeeks)
+			require.Len(t, department.Forecast, processing.DefaultForecastHorizonWeeks)
+		}
+
+		for _, vacancy := range forecast.Vacancies {
+			if vacancy.ExpectedFillDate != nil {
+				require.LessOrEqual(t, *vacancy.LowerFillDate, *vacancy.ExpectedFillDate)
+				require.LessOrEqual(t, *vacancy.ExpectedFillDate, *vacancy.UpperFillDate)
+			}
+		}
+
+		again, err := GetHiringForecast(ctx, intEnv.Client, filter)
+		require.NoError(t, err)
+		require.Equal(t, forecast, again)
+
+		backtest, err := GetHiringForecast(
+			ctx, intEnv.Client,
+			model.HiringForecastFilter{ReferenceDate: "2024-06-30"},
+		)
+		require.NoError(t, err)
+		for _, vacancy := range backtest.Vacancies {
+			require.LessOrEqual(t, vacancy.OpeningDate, "2024-06-30")
+		}
+
+		_, err = GetHiringForecast(
+			ctx, intEnv.Client,
+			model.HiringForecastFilter{ReferenceDate: "31/12/2024"},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+
+		horizonWeeks := 0
+		_, err = GetHiringForecast(
+			ctx, intEnv.Client,
+			model.HiringForecastFilter{HorizonWeeks: &horizonWeeks},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	}); !testResult {
+		t.Fatalf("Hiring forecast test failed")
+	}
+
+	if testResult := t.Run("Deadline rule rejects invalid thresholds and dates", func(t *testing.T) {
+		threshold := 1.5
+		for _, deadline := range []*model.DeadlineFilter{
+			{Threshold: &threshold},
+			{DepartmentThresholds: map[int]float64{1: 0}},
+			{ReferenceDate: "31/12/2024"},
+		} {
+			filter := model.FactHiringProcessFilter{Deadline: deadline}
+
+			_, err := GetAtRiskProcesses(ctx, intEnv.Client, filter)
+			require.ErrorIs(t, err, ErrInvalidFilter)
+
+			_, err = GetMetrics(ctx, intEnv.Client, filter)
+			require.ErrorIs(t, err, ErrInvalidFilter)
+
+			_, err = GetProcessTimeline(ctx, intEnv.Client, filter)
+			require.ErrorIs(t, err, ErrInvalidFilter)
+		}
+	}); !testResult {
+		t.Fatalf("Deadline rule test failed")
+	}
+
+	if testResult := t.Run("Score analytics rejects invalid band widths and retentions", func(t *testing.T) {
+		scoreAnalytics, err := GetScoreAnalytics(ctx, intEnv.Client, model.ScoreFilter{})
+		require.NoError(t, err)
+		require.NotEmpty(t, scoreAnalytics.Overall.Bands)
+
+		for _, bandWidth := range []float64{0, 1e-7, 1e-300} {
+			_, err := GetScoreAnalytics(
+				ctx, intEnv.Client,
+				model.ScoreFilter{BandWidth: &bandWidth},
+			)
+			require.ErrorIs(t, err, ErrInvalidFilter)
+		}
+
+		hireRetention := 120.0
+		_, err = GetScoreAnalytics(
+			ctx, intEnv.Client,
+			model.ScoreFilter{HireRetention: &hireRetention},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	}); !testResult {
+		t.Fatalf("Score analytics test failed")
+	}
+
+	if testResult := t.Run("Cohorts, time in stage and aging count business days", func(t *testing.T) {
+		for _, unit := range []processing.DurationUnit{
+			processing.DurationUnitCalendarDays,
+			processing.DurationUnitBusinessDays,
+		} {
+			filter := model.FactHiringProcessFilter{DurationUnit: unit}
+
+			cohortMatrix, err := GetCohortMatrix(ctx, intEnv.Client, model.CohortFilter{FactHiringProcessFilter: filter})
+			require.NoError(t, err)
+			require.NotEmpty(t, cohortMatrix.Cohorts)
+
+			timeInStage, err := GetTimeInStage(ctx, intEnv.Client, model.TimeInStageFilter{FactHiringProcessFilter: filter})
+			require.NoError(t, err)
+			require.NotEmpty(t, timeInStage.Stages)
+
+			_, err = GetCandidateAgingReport(ctx, intEnv.Client, model.CandidateAgingFilter{FactHiringProcessFilter: filter})
+			require.NoError(t, err)
+		}
+
+		filter := model.FactHiringProcessFilter{DurationUnit: "hours"}
+
+		_, err := GetCohortMatrix(ctx, intEnv.Client, model.CohortFilter{FactHiringProcessFilter: filter})
+		require.ErrorIs(t, err, ErrInvalidFilter)
+
+		_, err = GetTimeInStage(ctx, intEnv.Client, model.TimeInStageFilter{FactHiringProcessFilter: filter})
+		require.ErrorIs(t, err, ErrInvalidFilter)
+
+		_, err = GetCandidateAgingReport(ctx, intEnv.Client, model.CandidateAgingFilter{FactHiringProcessFilter: filter})
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	}); !testResult {
+		t.Fatalf("Duration unit test failed")
+	}
+
+	if testResult := t.Run("Aging candidates reject invalid rules", func(t *testing.T) {
+		for _, filter := range []model.CandidateAgingFilter{
+			{MinDays: -1},
+			{Stages: []int{-1}},
+			{ReferenceDate: "2024-13-01"},
+		} {
+			_, err := GetAgingCandidates(ctx, intEnv.Client, filter)
+			require.ErrorIs(t, err, ErrInvalidFilter)
+		}
+
+		page, err := GetAgingCandidates(ctx, intEnv.Client, model.CandidateAgingFilter{})
+		require.NoError(t, err)
+
+		keys := map[int]bool{}
+		for _, item := range page.Items {
+			require.False(t, keys[item.DimCandidateId])
+			keys[item.DimCandidateId] = true
+		}
+	}); !testResult {
+		t.Fatalf("Aging rule test failed")
+	}
+
+	if testResult := t.Run("Cohorts reject invalid horizons and granularities", func(t *testing.T) {
+		for _, filter := range []model.CohortFilter{
+			{Horizons: []int{7, 0}},
+			{Granularity: "year"},
+		} {
+			_, err := GetCohortMatrix(ctx, intEnv.Client, filter)
+			require.ErrorIs(t, err, ErrInvalidFilter)
+		}
+	}); !testResult {
+		t.Fatalf("Cohort filter test failed")
+	}
+
+	if testResult := t.Run("Facts follow new versions of their vacancy and department", func(t *testing.T) {
+		july := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+		august := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+		september := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+		department, err := warehouse.LoadDimDepartment(ctx, intEnv.Client, ent.DimDepartment{
+			DbId: 9001, Name: "Versioned", Description: "Before",
+		}, july)
+		require.NoError(t, err)
+
+		user, err := warehouse.LoadDimUser(ctx, intEnv.Client, ent.DimUser{
+			DbId: 9001, Name: "Versioned", Occupation: "Recruiter",
+		}, july)
+		require.NoError(t, err)
+
+		process, err := warehouse.LoadDimProcess(ctx, intEnv.Client, ent.DimProcess{
+			DbId:            9001,
+			Title:           "Versioned",
+			InitialDate:     warehouse.Date(july),
+			Status:          property.DimProcessStatusInProgress,
+			DimUsrId:        user.DbId,
+			DimDepartmentId: department.ID,
+		}, july)
+		require.NoError(t, err)
+
+		vacancy := ent.DimVacancy{
+			DbId:         9001,
+			Title:        "Before",
+			NumPositions: 2,
+			Status:       property.DimVacancyStatusOpen,
+			Location:     "Remote",
+			DimUsrId:     user.DbId,
+			OpeningDate:  warehouse.Date(july),
+		}
+		before, err := warehouse.LoadDimVacancy(ctx, intEnv.Client, vacancy, july)
+		require.NoError(t, err)
+
+		for dbId := 9001; dbId <= 9002; dbId++ {
+			_, err := warehouse.LoadDimCandidate(ctx, intEnv.Client, ent.DimCandidate{
+				DbId:           dbId,
+				Name:           "Versioned",
+				Email:          "versioned@mail.com",
+				Phone:          "11999999999",
+				DimVacancyDbId: before.ID,
+				ApplyDate:      warehouse.Date(july),
+				Status:         property.DimCandidateStatusInAnalysis,
+			}, july)
+			require.NoError(t, err)
+		}
+
+		date, err := intEnv.Client.DimDatetime.Create().
+			SetDate(warehouse.Date(august)).
+			SetYear(2024).SetMonth(8).SetWeekday(4).SetDay(1).
+			SetHour(0).SetMinute(0).SetSecond(0).
+			Save(ctx)
+		require.NoError(t, err)
+
+		_, err = intEnv.Client.FactHiringProcess.Create().
+			SetDimProcessID(process.ID).
+			SetDimVacancyID(before.ID).
+			SetDimUserID(user.ID).
+			SetDimDatetimeID(date.ID).
+			SetMetTotalCandidatesApplied(2).
+			SetMetTotalCandidatesInterviewed(0).
+			SetMetTotalCandidatesHired(0).
+			SetMetSumDurationHiringProces(0).
+			SetMetSumSalaryInitial(0).
+			SetMetTotalFeedbackPositive(0).
+			SetMetTotalNeutral(0).
+			SetMetTotalNegative(0).
+			Save(ctx)
+		require.NoError(t, err)
+
+		count := func(accessGroup int, asOf string) (int, int) {
+			query, err := applyFactHiringProcessQueryFilters(
+				createFactHiringProcessBaseQuery(intEnv.Client, false),
+				model.FactHiringProcessFilter{
+					AccessGroups: []int{accessGroup},
+					AsOf:         asOf,
+				},
+			)
+			require.NoError(t, err)
+
+			facts, err := query.All(ctx)
+			require.NoError(t, err)
+
+			candidates, err := processing.UniqueFactCandidates(facts)
+			require.NoError(t, err)
+
+			return len(facts), len(candidates)
+		}
+
+		for _, asOf := range []string{"", "2024-08-15"} {
+			facts, candidates := count(department.ID, asOf)
+			require.Equal(t, 1, facts)
+			require.Equal(t, 2, candidates)
+		}
+
+		vacancy.Title = "After"
+		after, err := warehouse.LoadDimVacancy(ctx, intEnv.Client, vacancy, september)
+		require.NoError(t, err)
+		require.NotEqual(t, before.ID, after.ID)
+
+		renamed, err := warehouse.LoadDimDepartment(ctx, intEnv.Client, ent.DimDepartment{
+			DbId: 9001, Name: "Versioned", Description: "After",
+		}, september)
+		require.NoError(t, err)
+		require.NotEqual(t, department.ID, renamed.ID)
+
+		// both the closed and the current department grant access, as of
+		// any date the vacancy and department existed
+		for _, accessGroup := range []int{department.ID, renamed.ID} {
+			for _, asOf := range []string{"", "2024-08-15", "2024-09-15"} {
+				facts, candidates := count(accessGroup, asOf)
+				require.Equal(t, 1, facts)
+				require.Equal(t, 2, candidates)
+			}
+		}
+
+		// a department opened after the date of the fact of its process
+		late, err := warehouse.LoadDimDepartment(ctx, intEnv.Client, ent.DimDepartment{
+			DbId: 9002, Name: "Late", Description: "Late",
+		}, september)
+		require.NoError(t, err)
+
+		lateProcess, err := warehouse.LoadDimProcess(ctx, intEnv.Client, ent.DimProcess{
+			DbId:            9002,
+			Title:           "Late",
+			InitialDate:     warehouse.Date(july),
+			Status:          property.DimProcessStatusInProgress,
+			DimUsrId:        user.DbId,
+			DimDepartmentId: late.ID,
+		}, july)
+		require.NoError(t, err)
+
+		_, err = intEnv.Client.FactHiringProcess.Create().
+			SetDimProcessID(lateProcess.ID).
+			SetDimVacancyID(after.ID).
+			SetDimUserID(user.ID).
+			SetDimDatetimeID(date.ID).
+			SetMetTotalCandidatesApplied(2).
+			SetMetTotalCandidatesInterviewed(0).
+			SetMetTotalCandidatesHired(0).
+			SetMetSumDurationHiringProces(0).
+			SetMetSumSalaryInitial(0).
+			SetMetTotalFeedbackPositive(0).
+			SetMetTotalNeutral(0).
+			SetMetTotalNegative(0).
+			Save(ctx)
+		require.NoError(t, err)
+
+		facts, _ := count(late.ID, "2024-08-15")
+		require.Zero(t, facts)
+
+		facts, candidates := count(late.ID, "2024-09-15")
+		require.Equal(t, 1, facts)
+		require.Equal(t, 2, candidates)
+	}); !testResult {
+		t.Fatalf("Versioned dimensions test failed")
+	}
 }
 
 func TestTableDashboard(t *testing.T) {
@@ -72,16 +628,18 @@ func TestTableDashboard(t *testing.T) {
 	if testResult := t.Run("Vacancy Table returns all FactHiringProcess", func(t *testing.T) {
 		dashboardTablePage, err := GetVacancyTable(
 			ctx, intEnv.Client,
-			model.FactHiringProcessFilter{
-				Recruiters:    []int{},
-				Processes:     []int{},
-				Vacancies:     []int{},
-				DateRange:     nil,
-				ProcessStatus: []int{},
-				VacancyStatus: []int{},
-				PageRequest: &model.PageRequest{
-					Page:     nil,
-					PageSize: nil,
+			model.VacancyTableFilter{
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					Recruiters:    []int{},
+					Processes:     []int{},
+					Vacancies:     []int{},
+					DateRange:     nil,
+					ProcessStatus: []int{},
+					VacancyStatus: []int{},
+					PageRequest: &model.PageRequest{
+						Page:     nil,
+						PageSize: nil,
+					},
 				},
 			},
 		)
@@ -96,19 +654,21 @@ func TestTableDashboard(t *testing.T) {
 	if testResult := t.Run("Vacancy Table returns correct number of FactHiringProcess", func(t *testing.T) {
 		vacancies, err := GetVacancyTable(
 			ctx, intEnv.Client,
-			model.FactHiringProcessFilter{
-				Recruiters: []int{},
-				Processes:  []int{},
-				Vacancies:  []int{},
-				DateRange: &model.DateRange{
-					StartDate: "2024-07-16",
-					EndDate:   "2024-08-12",
-				},
-				ProcessStatus: []int{},
-				VacancyStatus: []int{},
-				PageRequest: &model.PageRequest{
-					Page:     nil,
-					PageSize: nil,
+			model.VacancyTableFilter{
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					Recruiters: []int{},
+					Processes:  []int{},
+					Vacancies:  []int{},
+					DateRange: &model.DateRange{
+						StartDate: "2024-07-16",
+						EndDate:   "2024-08-12",
+					},
+					ProcessStatus: []int{},
+					VacancyStatus: []int{},
+					PageRequest: &model.PageRequest{
+						Page:     nil,
+						PageSize: nil,
+					},
 				},
 			},
 		)
@@ -119,4 +679,243 @@ func TestTableDashboard(t *testing.T) {
 	}); !testResult {
 		t.Fatalf("GetVacancyTable dateRange test failed")
 	}
+
+	if testResult := t.Run("Vacancy Table sorts by computed columns across pages", func(t *testing.T) {
+		pageSize := 100
+		vacancies, err := GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				Sort: []model.TableSort{
+					{Column: "competitionRate", Direction: "desc"},
+				},
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					PageRequest: &model.PageRequest{
+						PageSize: &pageSize,
+					},
+				},
+			},
+		)
+
+		require.NoError(t, err)
+		for i := 1; i < len(vacancies.Items); i++ {
+			previous := vacancies.Items[i-1].CompetitionRate
+			current := vacancies.Items[i].CompetitionRate
+			if current == nil {
+				continue
+			}
+			require.NotNil(t, previous, "empty values must be sorted last")
+			require.GreaterOrEqual(t, *previous, *current)
+		}
+	}); !testResult {
+		t.Fatalf("GetVacancyTable sort test failed")
+	}
+
+	if testResult := t.Run("Vacancy Table searches the titles", func(t *testing.T) {
+		vacancies, err := GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				Search:  "zzz-no-title-matches-zzz",
+				Columns: []string{"vacancyTitle", "numHired"},
+			},
+		)
+
+		require.NoError(t, err)
+		require.Empty(t, vacancies.Items)
+	}); !testResult {
+		t.Fatalf("GetVacancyTable search test failed")
+	}
+
+	if testResult := t.Run("Vacancy Table totals cover the whole filtered set", func(t *testing.T) {
+		pageSize := 1
+		vacancies, err := GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				Subtotals: true,
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					PageRequest: &model.PageRequest{
+						PageSize: &pageSize,
+					},
+				},
+			},
+		)
+
+		require.NoError(t, err)
+		require.NotNil(t, vacancies.Totals)
+		require.Equal(t, vacancies.NumMaxPages, vacancies.Totals.NumRows)
+
+		numRows, numHired := 0, 0
+		for _, subtotal := range vacancies.Subtotals {
+			require.NotNil(t, subtotal.ProcessId)
+			numRows += subtotal.NumRows
+			numHired += subtotal.NumHired
+		}
+		require.Equal(t, vacancies.Totals.NumRows, numRows)
+		require.Equal(t, vacancies.Totals.NumHired, numHired)
+	}); !testResult {
+		t.Fatalf("GetVacancyTable totals test failed")
+	}
+
+	if testResult := t.Run("Vacancy Table counts hiring durations in business days", func(t *testing.T) {
+		pageSize := 100
+		sort := []model.TableSort{{Column: "averageHiringTime", Direction: "desc"}}
+
+		calendarDays, err := GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				Sort: sort,
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					PageRequest: &model.PageRequest{PageSize: &pageSize},
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		businessDays, err := GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				Sort: sort,
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					DurationUnit: processing.DurationUnitBusinessDays,
+					PageRequest:  &model.PageRequest{PageSize: &pageSize},
+				},
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, calendarDays.TotalItems, businessDays.TotalItems)
+
+		if calendarDays.Totals.AverageHiringTime != nil {
+			require.NotNil(t, businessDays.Totals.AverageHiringTime)
+			require.LessOrEqual(t, *businessDays.Totals.AverageHiringTime, *calendarDays.Totals.AverageHiringTime)
+		}
+
+		_, err = GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					DurationUnit: "weeks",
+				},
+			},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	}); !testResult {
+		t.Fatalf("GetVacancyTable business days test failed")
+	}
+
+	if testResult := t.Run("Vacancy Table rejects unknown columns", func(t *testing.T) {
+		_, err := GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				Sort: []model.TableSort{{Column: "salary"}},
+			},
+		)
+
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	}); !testResult {
+		t.Fatalf("GetVacancyTable invalid sort test failed")
+	}
+
+	if testResult := t.Run("Vacancy Table filters by the selected date field", func(t *testing.T) {
+		pageSize := 100
+		for _, dateField := range []processing.DateField{
+			processing.DateFieldVacancyPeriod,
+			processing.DateFieldProcessPeriod,
+			processing.DateFieldProcessStart,
+			processing.DateFieldCandidateApply,
+			processing.DateFieldCandidateHire,
+			processing.DateFieldLoadDate,
+		} {
+			overlapping, err := GetVacancyTable(
+				ctx, intEnv.Client,
+				model.VacancyTableFilter{
+					FactHiringProcessFilter: model.FactHiringProcessFilter{
+						DateRange: &model.DateRange{
+							StartDate: "2024-01-01",
+							EndDate:   "2024-12-31",
+							DateField: dateField,
+						},
+						PageRequest: &model.PageRequest{PageSize: &pageSize},
+					},
+				},
+			)
+			require.NoError(t, err, "date field %q", dateField)
+
+			contained, err := GetVacancyTable(
+				ctx, intEnv.Client,
+				model.VacancyTableFilter{
+					FactHiringProcessFilter: model.FactHiringProcessFilter{
+						DateRange: &model.DateRange{
+							StartDate: "2024-01-01",
+							EndDate:   "2024-12-31",
+							DateField: dateField,
+							Match:     processing.DateMatchContains,
+						},
+						PageRequest: &model.PageRequest{PageSize: &pageSize},
+					},
+				},
+			)
+			require.NoError(t, err, "date field %q", dateField)
+			require.LessOrEqual(t, contained.TotalItems, overlapping.TotalItems)
+		}
+	}); !testResult {
+		t.Fatalf("GetVacancyTable date field test failed")
+	}
+
+	if testResult := t.Run("Vacancy Table rejects invalid date ranges", func(t *testing.T) {
+		for _, dateRange := range []model.DateRange{
+			{StartDate: "2024-13-01"},
+			{StartDate: "2024-08-12", EndDate: "2024-07-16"},
+			{StartDate: "2024-07-16", DateField: "closingDate"},
+			{StartDate: "2024-07-16", Match: "within"},
+		} {
+			_, err := GetVacancyTable(
+				ctx, intEnv.Client,
+				model.VacancyTableFilter{
+					FactHiringProcessFilter: model.FactHiringProcessFilter{
+						DateRange: &dateRange,
+					},
+				},
+			)
+
+			require.ErrorIs(t, err, ErrInvalidFilter)
+		}
+	}); !testResult {
+		t.Fatalf("GetVacancyTable invalid date range test failed")
+	}
+
+	if testResult := t.Run("Vacancy Table runs as of a date", func(t *testing.T) {
+		pageSize := 100
+		current, err := GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					PageRequest: &model.PageRequest{PageSize: &pageSize},
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		asOf, err := GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					AsOf:        "2024-08-15",
+					PageRequest: &model.PageRequest{PageSize: &pageSize},
+				},
+			},
+		)
+		require.NoError(t, err)
+		require.LessOrEqual(t, asOf.TotalItems, current.TotalItems)
+
+		_, err = GetVacancyTable(
+			ctx, intEnv.Client,
+			model.VacancyTableFilter{
+				FactHiringProcessFilter: model.FactHiringProcessFilter{
+					AsOf: "15/08/2024",
+				},
+			},
+		)
+		require.ErrorIs(t, err, ErrInvalidFilter)
+	}); !testResult {
+		t.Fatalf("GetVacancyTable as of test failed")
+	}
 }
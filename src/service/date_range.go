@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+
+	"api5back/ent/dimcandidate"
+	"api5back/ent/dimdatetime"
+	"api5back/ent/dimprocess"
+	"api5back/ent/dimvacancy"
+	"api5back/ent/facthiringprocess"
+	"api5back/ent/predicate"
+	"api5back/src/model"
+	"api5back/src/processing"
+	"api5back/src/property"
+	"api5back/src/warehouse"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// dateRangePredicate translates the `DateRange` into a predicate over
+// the dates selected by its `DateField`, matching the latest version of
+// the candidates. Invalid ranges are reported as `ErrInvalidFilter`.
+func dateRangePredicate(
+	dateRange model.DateRange,
+) (predicate.FactHiringProcess, error) {
+	bounds, err := processing.ParseDateBounds(
+		dateRange.DateField,
+		dateRange.Match,
+		dateRange.StartDate,
+		dateRange.EndDate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	switch bounds.Field {
+	case processing.DateFieldProcessPeriod:
+		return facthiringprocess.HasDimProcessWith(
+			predicate.DimProcess(periodPredicate(
+				bounds,
+				dimprocess.FieldInitialDate,
+				dimprocess.FieldFinishDate,
+			)),
+		), nil
+	case processing.DateFieldProcessStart:
+		return facthiringprocess.HasDimProcessWith(
+			predicate.DimProcess(datePredicate(bounds, dimprocess.FieldInitialDate)),
+		), nil
+	case processing.DateFieldCandidateApply:
+		return facthiringprocess.HasDimVacancyWith(
+			dimvacancy.HasDimCandidatesWith(
+				warehouse.LatestDimCandidate,
+				predicate.DimCandidate(datePredicate(bounds, dimcandidate.FieldApplyDate)),
+			),
+		), nil
+	case processing.DateFieldCandidateHire:
+		return facthiringprocess.HasDimVacancyWith(
+			dimvacancy.HasDimCandidatesWith(
+				warehouse.LatestDimCandidate,
+				dimcandidate.StatusEQ(property.DimCandidateStatusHired),
+				predicate.DimCandidate(datePredicate(bounds, dimcandidate.FieldUpdatedAt)),
+			),
+		), nil
+	case processing.DateFieldLoadDate:
+		return facthiringprocess.HasDimDatetimeWith(
+			predicate.DimDatetime(datePredicate(bounds, dimdatetime.FieldDate)),
+		), nil
+	default:
+		return facthiringprocess.HasDimVacancyWith(
+			predicate.DimVacancy(periodPredicate(
+				bounds,
+				dimvacancy.FieldOpeningDate,
+				dimvacancy.FieldClosingDate,
+			)),
+		), nil
+	}
+}
+
+// datePredicate matches the rows whose date column is within the range.
+func datePredicate(
+	bounds processing.DateBounds,
+	column string,
+) func(*sql.Selector) {
+	return periodPredicate(
+		processing.DateBounds{
+			Match: processing.DateMatchContains,
+			Start: bounds.Start,
+			End:   bounds.End,
+		},
+		column,
+		column,
+	)
+}
+
+// periodPredicate matches the rows whose interval, from the start column
+// to the end column, overlaps or is contained by the range.
+func periodPredicate(
+	bounds processing.DateBounds,
+	startColumn, endColumn string,
+) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		if bounds.Start != nil {
+			column := endColumn
+			if bounds.Match == processing.DateMatchContains {
+				column = startColumn
+			}
+			s.Where(sql.GTE(s.C(column), bounds.Start))
+		}
+
+		if bounds.End != nil {
+			column := startColumn
+			if bounds.Match == processing.DateMatchContains {
+				column = endColumn
+			}
+			s.Where(sql.LTE(s.C(column), bounds.End))
+		}
+	}
+}
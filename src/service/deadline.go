@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/model"
+	"api5back/src/pagination"
+	"api5back/src/processing"
+)
+
+func parseDeadlineThreshold(threshold float64) error {
+	if threshold <= 0 || threshold > 1 {
+		return fmt.Errorf(
+			"invalid deadline threshold %v, expected a value in (0, 1]",
+			threshold,
+		)
+	}
+
+	return nil
+}
+
+// parseDeadlineRule builds the deadline rule of the filter as of today,
+// unless a `ReferenceDate` is given. Invalid thresholds and dates are
+// reported as `ErrInvalidFilter`.
+func parseDeadlineRule(
+	deadlineFilter *model.DeadlineFilter,
+) (processing.DeadlineRule, error) {
+	rule := processing.NewDeadlineRule(time.Now())
+
+	if deadlineFilter == nil {
+		return rule, nil
+	}
+
+	if deadlineFilter.Threshold != nil {
+		if err := parseDeadlineThreshold(*deadlineFilter.Threshold); err != nil {
+			return processing.DeadlineRule{}, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+		}
+		rule.Threshold = *deadlineFilter.Threshold
+	}
+
+	for departmentId, threshold := range deadlineFilter.DepartmentThresholds {
+		if err := parseDeadlineThreshold(threshold); err != nil {
+			return processing.DeadlineRule{}, fmt.Errorf(
+				"%w: department %d: %w",
+				ErrInvalidFilter,
+				departmentId,
+				err,
+			)
+		}
+	}
+	rule.DepartmentThresholds = deadlineFilter.DepartmentThresholds
+
+	if deadlineFilter.ReferenceDate != "" {
+		referenceDate, err := time.Parse("2006-01-02", deadlineFilter.ReferenceDate)
+		if err != nil {
+			return processing.DeadlineRule{}, fmt.Errorf(
+				"%w: could not parse `ReferenceDate`: %w",
+				ErrInvalidFilter,
+				err,
+			)
+		}
+		rule.ReferenceDate = referenceDate
+	}
+
+	return rule, nil
+}
+
+func GetAtRiskProcesses(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.FactHiringProcessFilter,
+) (*model.Page[processing.AtRiskProcess], error) {
+	deadlineRule, err := parseDeadlineRule(filter.Deadline)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not parse `Deadline` rule: %w",
+			err,
+		)
+	}
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory).
+			WithDimUser(),
+		filter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	request, err := pagination.ParseRequest(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	atRiskProcesses, err := processing.GenerateAtRiskProcesses(
+		factHiringProcesses,
+		deadlineRule,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate at-risk processes: %w",
+			err,
+		)
+	}
+
+	return pagination.PaginateRequest(
+		atRiskProcesses,
+		request,
+		func(item processing.AtRiskProcess) []any {
+			return []any{item.RemainingDays, item.ProcessId, item.VacancyId, item.FactId}
+		},
+	)
+}
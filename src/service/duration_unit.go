@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/ent/dimdatetime"
+	"api5back/src/calendar"
+	"api5back/src/processing"
+)
+
+// parseDayCounter validates the duration unit of a request. Business
+// days leave out weekends and the holidays of the date dimension, see
+// `scripts/calendar`, so they are counted the same in memory and in
+// the queries of the vacancy table.
+func parseDayCounter(
+	ctx context.Context,
+	client *ent.Client,
+	unit processing.DurationUnit,
+) (processing.DayCounter, error) {
+	unit, err := processing.ParseDurationUnit(unit)
+	if err != nil {
+		return processing.DayCounter{}, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	if unit != processing.DurationUnitBusinessDays {
+		return processing.DayCounter{Unit: unit}, nil
+	}
+
+	holidays, err := client.DimDatetime.Query().
+		Where(dimdatetime.IsHoliday(true)).
+		All(ctx)
+	if err != nil {
+		return processing.DayCounter{}, fmt.Errorf(
+			"could not query the holidays of `DimDatetime`: %w",
+			err,
+		)
+	}
+
+	options := calendar.Options{}
+	for _, holiday := range holidays {
+		if holiday.Date == nil || !holiday.Date.Valid {
+			continue
+		}
+
+		options.HolidayDates = append(options.HolidayDates, calendar.Holiday{
+			Date: holiday.Date.Time,
+			Name: holiday.HolidayName,
+		})
+	}
+
+	return processing.DayCounter{
+		Unit:     unit,
+		Calendar: calendar.New(options),
+	}, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/model"
+	"api5back/src/processing"
+)
+
+// maxForecastWeeks bounds both the history and the horizon of the
+// weekly hires forecast.
+const maxForecastWeeks = 104
+
+func parseForecastOptions(
+	filter model.HiringForecastFilter,
+) (processing.ForecastOptions, error) {
+	options := processing.ForecastOptions{
+		ReferenceDate: time.Now(),
+		MinSamples:    processing.DefaultForecastMinSamples,
+		HistoryWeeks:  processing.DefaultForecastHistoryWeeks,
+		HorizonWeeks:  processing.DefaultForecastHorizonWeeks,
+	}
+
+	if filter.ReferenceDate != "" {
+		referenceDate, err := time.Parse("2006-01-02", filter.ReferenceDate)
+		if err != nil {
+			return processing.ForecastOptions{}, fmt.Errorf(
+				"could not parse `ReferenceDate`: %w",
+				err,
+			)
+		}
+		options.ReferenceDate = referenceDate
+	}
+
+	if filter.MinSamples != nil {
+		if *filter.MinSamples < 1 {
+			return processing.ForecastOptions{}, fmt.Errorf(
+				"invalid `MinSamples`: %d, expected a positive value",
+				*filter.MinSamples,
+			)
+		}
+		options.MinSamples = *filter.MinSamples
+	}
+
+	for _, weeks := range []struct {
+		name  string
+		value *int
+		field *int
+	}{
+		{"HistoryWeeks", filter.HistoryWeeks, &options.HistoryWeeks},
+		{"HorizonWeeks", filter.HorizonWeeks, &options.HorizonWeeks},
+	} {
+		if weeks.value == nil {
+			continue
+		}
+		if *weeks.value < 1 || *weeks.value > maxForecastWeeks {
+			return processing.ForecastOptions{}, fmt.Errorf(
+				"invalid `%s`: %d, expected from 1 to %d",
+				weeks.name,
+				*weeks.value,
+				maxForecastWeeks,
+			)
+		}
+		*weeks.field = *weeks.value
+	}
+
+	return options, nil
+}
+
+// GetHiringForecast forecasts the open vacancies of the filtered facts
+// and the weekly hires of their departments. The history is read from
+// every fact within the access groups of the filter, as of its `AsOf`
+// date, so forecasts can be reproduced.
+func GetHiringForecast(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.HiringForecastFilter,
+) (*processing.HiringForecast, error) {
+	options, err := parseForecastOptions(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	withDimDepartment := func(query *ent.DimProcessQuery) {
+		query.WithDimDepartment()
+	}
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory).
+			WithDimProcess(withDimDepartment),
+		filter.FactHiringProcessFilter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	historyQuery, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory).
+			WithDimProcess(withDimDepartment),
+		model.FactHiringProcessFilter{
+			AccessGroups:     filter.AccessGroups,
+			AsOf:             filter.AsOf,
+			CandidateHistory: filter.CandidateHistory,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply history filters: %w",
+			err,
+		)
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	history, err := historyQuery.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` history: %w",
+			err,
+		)
+	}
+
+	forecast, err := processing.GenerateHiringForecast(
+		factHiringProcesses,
+		history,
+		options,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate hiring forecast: %w",
+			err,
+		)
+	}
+
+	return &forecast, nil
+}
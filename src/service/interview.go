@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/ent/dimfeedback"
+	"api5back/ent/facthiringprocess"
+	"api5back/src/model"
+	"api5back/src/processing"
+)
+
+func GetInterviewsPerHire(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.FactHiringProcessFilter,
+) (*processing.InterviewsPerHire, error) {
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory).
+			WithDimVacancy(func(query *ent.DimVacancyQuery) {
+				query.
+					WithDimCandidates(withDimCandidates(filter.CandidateHistory)).
+					WithDimInterviews()
+			}),
+		filter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	interviewsPerHire, err := processing.GenerateInterviewsPerHire(factHiringProcesses)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate interviews per hire: %w",
+			err,
+		)
+	}
+
+	return &interviewsPerHire, nil
+}
+
+func GetFeedbackSentimentTrend(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.FeedbackSentimentFilter,
+) (*processing.FeedbackSentimentTrend, error) {
+	granularity := filter.Granularity
+	if granularity == "" {
+		granularity = processing.CohortGranularityMonth
+	}
+
+	if granularity != processing.CohortGranularityMonth &&
+		granularity != processing.CohortGranularityWeek {
+		return nil, fmt.Errorf(
+			"%w: invalid granularity: %q",
+			ErrInvalidFilter,
+			granularity,
+		)
+	}
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory),
+		filter.FactHiringProcessFilter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	vacancyIds, err := query.
+		Unique(true).
+		Select(facthiringprocess.FieldDimVacancyId).
+		Ints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	feedbacks, err := client.DimFeedback.
+		Query().
+		Where(dimfeedback.DimVacancyIdIn(vacancyIds...)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `DimFeedback` data: %w",
+			err,
+		)
+	}
+
+	trend, err := processing.GenerateFeedbackSentimentTrend(feedbacks, granularity)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate feedback sentiment trend: %w",
+			err,
+		)
+	}
+
+	return &trend, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/ent/factpipelinesnapshot"
+	"api5back/ent/predicate"
+	"api5back/src/model"
+	"api5back/src/processing"
+	"api5back/src/warehouse"
+)
+
+func GetPipelineTrend(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.PipelineTrendFilter,
+) (*processing.PipelineTrend, error) {
+	granularity := filter.Granularity
+	if granularity == "" {
+		granularity = processing.CohortGranularityMonth
+	}
+
+	if granularity != processing.CohortGranularityMonth &&
+		granularity != processing.CohortGranularityWeek &&
+		granularity != processing.PipelineGranularityDay {
+		return nil, fmt.Errorf(
+			"%w: invalid granularity: %q",
+			ErrInvalidFilter,
+			granularity,
+		)
+	}
+
+	bounds, err := processing.ParseDateBounds("", "", filter.StartDate, filter.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	query := client.FactPipelineSnapshot.Query()
+	if bounds.Start != nil {
+		query = query.Where(factpipelinesnapshot.SnapshotDateGTE(bounds.Start))
+	}
+	if bounds.End != nil {
+		query = query.Where(factpipelinesnapshot.SnapshotDateLTE(bounds.End))
+	}
+	if len(filter.Departments) > 0 {
+		query = query.Where(factpipelinesnapshot.HasDimDepartmentWith(
+			predicate.DimDepartment(warehouse.SameDbId(filter.Departments...)),
+		))
+	}
+	if len(filter.AccessGroups) > 0 {
+		query = query.Where(factpipelinesnapshot.HasDimDepartmentWith(
+			predicate.DimDepartment(warehouse.SameDbId(filter.AccessGroups...)),
+		))
+	}
+	if len(filter.Recruiters) > 0 {
+		query = query.Where(factpipelinesnapshot.HasDimUserWith(
+			predicate.DimUser(warehouse.SameDbId(filter.Recruiters...)),
+		))
+	}
+
+	snapshots, err := query.
+		Order(ent.Asc(factpipelinesnapshot.FieldSnapshotDate)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactPipelineSnapshot` data: %w",
+			err,
+		)
+	}
+
+	trend, err := processing.GeneratePipelineTrend(snapshots, granularity)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate pipeline trend: %w",
+			err,
+		)
+	}
+
+	return &trend, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/model"
+	"api5back/src/processing"
+)
+
+func GetPositionBurndown(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.PositionBurndownFilter,
+) (*processing.PositionBurndown, error) {
+	referenceDate := time.Now()
+	if filter.ReferenceDate != "" {
+		parsedDate, err := time.Parse("2006-01-02", filter.ReferenceDate)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: could not parse `ReferenceDate`: %w",
+				ErrInvalidFilter,
+				err,
+			)
+		}
+		referenceDate = parsedDate
+	}
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory),
+		filter.FactHiringProcessFilter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	burndown, err := processing.GeneratePositionBurndown(
+		factHiringProcesses,
+		referenceDate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate position burndown: %w",
+			err,
+		)
+	}
+
+	return &burndown, nil
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 
 	"api5back/ent"
-	"api5back/ent/dimdepartment"
 	"api5back/ent/dimprocess"
+	"api5back/ent/predicate"
 	"api5back/src/model"
 	"api5back/src/pagination"
-	"api5back/src/processing"
+	"api5back/src/warehouse"
 )
 
 func GetProcessSuggestions(
@@ -25,7 +25,7 @@ func GetProcessSuggestions(
 			query = query.
 				Where(
 					dimprocess.HasDimDepartmentWith(
-						dimdepartment.IDIn(*pageRequest.DepartmentIds...),
+						predicate.DimDepartment(warehouse.SameDbId(*pageRequest.DepartmentIds...)),
 					),
 				)
 		}
@@ -37,7 +37,7 @@ func GetProcessSuggestions(
 		}
 	}
 
-	page, pageSize, err := pagination.ParsePageRequest(pageRequest)
+	request, err := pagination.ParseRequest(pageRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -47,20 +47,29 @@ func GetProcessSuggestions(
 		return nil, err
 	}
 
-	offset, numMaxPages := processing.ParseOffsetAndTotalPages(
-		page,
-		pageSize,
-		totalRecords,
-	)
+	keyset := pagination.Keyset{
+		Keys:   []pagination.Key{pagination.IDKey},
+		Cursor: request.Cursor,
+	}
 
 	processes, err := query.
-		Offset(offset).
-		Limit(pageSize).
+		Where(predicate.DimProcess(keyset.Where())).
+		Order(dimprocess.OrderOption(keyset.Order())).
+		Offset(request.Offset()).
+		Limit(request.Limit()).
 		All(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	processes, cursors := pagination.Trim(
+		processes,
+		request,
+		func(item *ent.DimProcess) []any {
+			return []any{item.ID}
+		},
+	)
+
 	var suggestions []model.Suggestion
 	for _, process := range processes {
 		suggestions = append(suggestions, model.Suggestion{
@@ -69,8 +78,5 @@ func GetProcessSuggestions(
 		})
 	}
 
-	return &model.Page[model.Suggestion]{
-		Items:       suggestions,
-		NumMaxPages: numMaxPages,
-	}, nil
+	return pagination.NewPage(suggestions, request, totalRecords, cursors), nil
 }
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/src/model"
+	"api5back/src/pagination"
+	"api5back/src/processing"
+)
+
+// GetProcessTimeline lays out the processes of the filtered facts and
+// their vacancies in time, paginated by process. The deadline markers
+// follow the `Deadline` rule of the filter.
+func GetProcessTimeline(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.FactHiringProcessFilter,
+) (*model.Page[processing.TimelineProcess], error) {
+	deadlineRule, err := parseDeadlineRule(filter.Deadline)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not parse `Deadline` rule: %w",
+			err,
+		)
+	}
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory).
+			WithDimUser(),
+		filter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	request, err := pagination.ParseRequest(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	timeline, err := processing.GenerateProcessTimeline(
+		factHiringProcesses,
+		deadlineRule,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate process timeline: %w",
+			err,
+		)
+	}
+
+	return pagination.PaginateRequest(
+		timeline,
+		request,
+		func(item processing.TimelineProcess) []any {
+			var startDate any
+			if item.StartDate != "" {
+				startDate = item.StartDate
+			}
+			return []any{startDate, item.Title, item.Id}
+		},
+	)
+}
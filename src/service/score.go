@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/src/model"
+	"api5back/src/processing"
+)
+
+// minScoreBandWidth keeps the distribution of scores from 0 to 100
+// within `processing.MaxScoreBands`.
+const minScoreBandWidth = 0.1
+
+func GetScoreAnalytics(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.ScoreFilter,
+) (*processing.ScoreAnalytics, error) {
+	bandWidth := processing.DefaultScoreBandWidth
+	if filter.BandWidth != nil {
+		if *filter.BandWidth < minScoreBandWidth {
+			return nil, fmt.Errorf(
+				"%w: invalid `BandWidth`: %v, expected at least %v",
+				ErrInvalidFilter,
+				*filter.BandWidth,
+				minScoreBandWidth,
+			)
+		}
+		bandWidth = *filter.BandWidth
+	}
+
+	hireRetention := processing.DefaultHireRetention
+	if filter.HireRetention != nil {
+		if *filter.HireRetention <= 0 || *filter.HireRetention > 100 {
+			return nil, fmt.Errorf(
+				"%w: invalid `HireRetention`: %v, expected a percentage in (0, 100]",
+				ErrInvalidFilter,
+				*filter.HireRetention,
+			)
+		}
+		hireRetention = *filter.HireRetention
+	}
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory),
+		filter.FactHiringProcessFilter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	scoreAnalytics, err := processing.GenerateScoreAnalytics(
+		factHiringProcesses,
+		bandWidth,
+		hireRetention,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate score analytics: %w",
+			err,
+		)
+	}
+
+	return &scoreAnalytics, nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/ent/factcandidatestatuschange"
+	"api5back/src/model"
+	"api5back/src/processing"
+)
+
+func GetTimeInStage(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.TimeInStageFilter,
+) (*processing.TimeInStage, error) {
+	groupBy := filter.GroupBy
+	if groupBy == "" {
+		groupBy = processing.TimeInStageGroupByVacancy
+	}
+
+	if err := processing.ValidateTimeInStageGroupBy(groupBy); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	days, err := parseDayCounter(ctx, client, filter.DurationUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory).
+			WithDimUser().
+			WithDimProcess(func(query *ent.DimProcessQuery) {
+				query.WithDimDepartment()
+			}),
+		filter.FactHiringProcessFilter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	factHiringProcesses, err := query.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactHiringProcess` data: %w",
+			err,
+		)
+	}
+
+	vacancyIds := make([]int, len(factHiringProcesses))
+	for i, factHiringProcess := range factHiringProcesses {
+		vacancyIds[i] = factHiringProcess.DimVacancyId
+	}
+
+	statusChanges, err := client.FactCandidateStatusChange.
+		Query().
+		Where(factcandidatestatuschange.DimVacancyIdIn(vacancyIds...)).
+		WithDimCandidate().
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not retrieve `FactCandidateStatusChange` data: %w",
+			err,
+		)
+	}
+
+	stays, err := processing.CandidateStageStays(statusChanges, days)
+	if err != nil {
+		return nil, err
+	}
+
+	timeInStage, err := processing.GenerateTimeInStage(
+		factHiringProcesses,
+		stays,
+		groupBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not generate time in stage: %w",
+			err,
+		)
+	}
+
+	return &timeInStage, nil
+}
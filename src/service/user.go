@@ -4,13 +4,13 @@ import (
 	"context"
 
 	"api5back/ent"
-	"api5back/ent/dimdepartment"
 	"api5back/ent/dimprocess"
 	"api5back/ent/dimuser"
 	"api5back/ent/facthiringprocess"
+	"api5back/ent/predicate"
 	"api5back/src/model"
 	"api5back/src/pagination"
-	"api5back/src/processing"
+	"api5back/src/warehouse"
 )
 
 func GetUserSuggestions(
@@ -28,14 +28,14 @@ func GetUserSuggestions(
 				dimuser.HasFactHiringProcessWith(
 					facthiringprocess.HasDimProcessWith(
 						dimprocess.HasDimDepartmentWith(
-							dimdepartment.IDIn(*pageRequest.DepartmentIds...),
+							predicate.DimDepartment(warehouse.SameDbId(*pageRequest.DepartmentIds...)),
 						),
 					),
 				),
 			)
 	}
 
-	page, pageSize, err := pagination.ParsePageRequest(pageRequest)
+	request, err := pagination.ParseRequest(pageRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -45,20 +45,29 @@ func GetUserSuggestions(
 		return nil, err
 	}
 
-	offset, numMaxPages := processing.ParseOffsetAndTotalPages(
-		page,
-		pageSize,
-		totalRecords,
-	)
+	keyset := pagination.Keyset{
+		Keys:   []pagination.Key{pagination.IDKey},
+		Cursor: request.Cursor,
+	}
 
 	users, err := query.
-		Offset(offset).
-		Limit(pageSize).
+		Where(predicate.DimUser(keyset.Where())).
+		Order(dimuser.OrderOption(keyset.Order())).
+		Offset(request.Offset()).
+		Limit(request.Limit()).
 		All(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	users, cursors := pagination.Trim(
+		users,
+		request,
+		func(item *ent.DimUser) []any {
+			return []any{item.ID}
+		},
+	)
+
 	var suggestions []model.Suggestion
 	for _, user := range users {
 		suggestions = append(suggestions, model.Suggestion{
@@ -67,8 +76,5 @@ func GetUserSuggestions(
 		})
 	}
 
-	return &model.Page[model.Suggestion]{
-		Items:       suggestions,
-		NumMaxPages: numMaxPages,
-	}, nil
+	return pagination.NewPage(suggestions, request, totalRecords, cursors), nil
 }
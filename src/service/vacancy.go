@@ -2,15 +2,14 @@ package service
 
 import (
 	"context"
-	"fmt"
 
 	"api5back/ent"
-	"api5back/ent/dimdepartment"
 	"api5back/ent/dimprocess"
 	"api5back/ent/facthiringprocess"
+	"api5back/ent/predicate"
 	"api5back/src/model"
 	"api5back/src/pagination"
-	"api5back/src/processing"
+	"api5back/src/warehouse"
 )
 
 func GetVacancySuggestions(
@@ -29,7 +28,7 @@ func GetVacancySuggestions(
 				Where(
 					facthiringprocess.HasDimProcessWith(
 						dimprocess.HasDimDepartmentWith(
-							dimdepartment.IDIn(*pageRequest.DepartmentIds...),
+							predicate.DimDepartment(warehouse.SameDbId(*pageRequest.DepartmentIds...)),
 						),
 					),
 				)
@@ -42,7 +41,7 @@ func GetVacancySuggestions(
 		}
 	}
 
-	page, pageSize, err := pagination.ParsePageRequest(pageRequest)
+	request, err := pagination.ParseRequest(pageRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -52,21 +51,29 @@ func GetVacancySuggestions(
 		return nil, err
 	}
 
-	offset, numMaxPages := processing.ParseOffsetAndTotalPages(
-		page,
-		pageSize,
-		totalRecords,
-	)
+	keyset := pagination.Keyset{
+		Keys:   []pagination.Key{pagination.IDKey},
+		Cursor: request.Cursor,
+	}
 
-	fmt.Printf("pageSize: %+v | totalRecords: %+v\n", pageSize, totalRecords)
 	factHiringProcesses, err := query.
-		Offset(offset).
-		Limit(pageSize).
+		Where(predicate.FactHiringProcess(keyset.Where())).
+		Order(facthiringprocess.OrderOption(keyset.Order())).
+		Offset(request.Offset()).
+		Limit(request.Limit()).
 		All(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	factHiringProcesses, cursors := pagination.Trim(
+		factHiringProcesses,
+		request,
+		func(item *ent.FactHiringProcess) []any {
+			return []any{item.ID}
+		},
+	)
+
 	var suggestions []model.Suggestion
 	for _, fact := range factHiringProcesses {
 		if fact.Edges.DimVacancy != nil {
@@ -78,8 +85,5 @@ func GetVacancySuggestions(
 		}
 	}
 
-	return &model.Page[model.Suggestion]{
-		Items:       suggestions,
-		NumMaxPages: numMaxPages,
-	}, nil
+	return pagination.NewPage(suggestions, request, totalRecords, cursors), nil
 }
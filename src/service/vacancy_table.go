@@ -0,0 +1,462 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"api5back/ent"
+	"api5back/ent/dimprocess"
+	"api5back/ent/dimvacancy"
+	"api5back/ent/facthiringprocess"
+	"api5back/ent/predicate"
+	"api5back/src/model"
+	"api5back/src/pagination"
+	"api5back/src/processing"
+	"api5back/src/warehouse"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// vacancyTableSource is what the computed columns read: the table the
+// candidates are read from and the unit the hiring durations are
+// counted in.
+type vacancyTableSource struct {
+	candidates   string
+	durationUnit processing.DurationUnit
+}
+
+// vacancyTableColumn is a column of `DashboardTableRow`. `Order` returns
+// the SQL expression the column is sorted by, so computed columns can be
+// sorted across pages.
+type vacancyTableColumn struct {
+	Name   string
+	Metric *processing.MetricDefinition
+	Order  func(s *sql.Selector, source vacancyTableSource) string
+}
+
+func vacancySubquery(s *sql.Selector, expression string) string {
+	return fmt.Sprintf(
+		"(SELECT %s FROM dim_vacancy v WHERE v.id = %s)",
+		expression,
+		s.C(facthiringprocess.FieldDimVacancyId),
+	)
+}
+
+// hiringDays returns the SQL expression of the days from the application
+// to the hiring of the candidate `c`. Business days leave out weekends
+// and the holidays of the date dimension, as `parseDayCounter` does.
+func hiringDays(unit processing.DurationUnit) string {
+	if unit != processing.DurationUnitBusinessDays {
+		return "c.updated_at - c.apply_date"
+	}
+
+	return "(SELECT COUNT(*) FROM generate_series(c.apply_date + 1, c.updated_at, interval '1 day') AS d(day) " +
+		"WHERE EXTRACT(ISODOW FROM d.day) < 6 AND NOT EXISTS (" +
+		"SELECT 1 FROM dim_datetime h WHERE h.date = d.day::date AND h.is_holiday))"
+}
+
+func factColumns(columns ...string) func(s *sql.Selector, source vacancyTableSource) string {
+	return func(s *sql.Selector, _ vacancyTableSource) string {
+		qualified := make([]string, len(columns))
+		for i, column := range columns {
+			qualified[i] = s.C(column)
+		}
+		return strings.Join(qualified, " + ")
+	}
+}
+
+// vacancyTableColumns follows the order of the fields of `DashboardTableRow`.
+var vacancyTableColumns = []vacancyTableColumn{
+	{
+		Name: "processTitle",
+		Order: func(s *sql.Selector, _ vacancyTableSource) string {
+			return fmt.Sprintf(
+				"(SELECT p.title FROM dim_process p WHERE p.id = %s)",
+				s.C(facthiringprocess.FieldDimProcessId),
+			)
+		},
+	},
+	{
+		Name: "vacancyTitle",
+		Order: func(s *sql.Selector, _ vacancyTableSource) string {
+			return vacancySubquery(s, "v.title")
+		},
+	},
+	{
+		Name:   processing.MetricNumPositions.Id,
+		Metric: &processing.MetricNumPositions,
+		Order: func(s *sql.Selector, _ vacancyTableSource) string {
+			return vacancySubquery(s, "v.num_positions")
+		},
+	},
+	{
+		Name:   processing.MetricNumCandidates.Id,
+		Metric: &processing.MetricNumCandidates,
+		Order:  factColumns(facthiringprocess.FieldMetTotalCandidatesApplied),
+	},
+	{
+		Name:   processing.MetricCompetitionRate.Id,
+		Metric: &processing.MetricCompetitionRate,
+		Order: func(s *sql.Selector, _ vacancyTableSource) string {
+			return fmt.Sprintf(
+				"%s::float8 / NULLIF(%s, 0)",
+				s.C(facthiringprocess.FieldMetTotalCandidatesApplied),
+				vacancySubquery(s, "v.num_positions"),
+			)
+		},
+	},
+	{
+		Name:   processing.MetricNumInterviewed.Id,
+		Metric: &processing.MetricNumInterviewed,
+		Order:  factColumns(facthiringprocess.FieldMetTotalCandidatesInterviewed),
+	},
+	{
+		Name:   processing.MetricNumHired.Id,
+		Metric: &processing.MetricNumHired,
+		Order:  factColumns(facthiringprocess.FieldMetTotalCandidatesHired),
+	},
+	{
+		Name:   processing.MetricAverageHiringTime.Id,
+		Metric: &processing.MetricAverageHiringTime,
+		Order: func(s *sql.Selector, source vacancyTableSource) string {
+			return fmt.Sprintf(
+				"(SELECT AVG(%s) FROM %s c "+
+					"WHERE c.dim_vacancy_db_id = %s AND c.status = 'Hired')",
+				hiringDays(source.durationUnit),
+				source.candidates,
+				s.C(facthiringprocess.FieldDimVacancyId),
+			)
+		},
+	},
+	{
+		Name:   processing.MetricNumFeedback.Id,
+		Metric: &processing.MetricNumFeedback,
+		Order: factColumns(
+			facthiringprocess.FieldMetTotalFeedbackPositive,
+			facthiringprocess.FieldMetTotalNeutral,
+			facthiringprocess.FieldMetTotalNegative,
+		),
+	},
+}
+
+func lookupVacancyTableColumn(name string) (vacancyTableColumn, error) {
+	for _, column := range vacancyTableColumns {
+		if column.Name == name {
+			return column, nil
+		}
+	}
+
+	return vacancyTableColumn{}, fmt.Errorf("unknown column: %q", name)
+}
+
+// vacancyTableKeys translates the sort of the table into the keys of a
+// keyset. The ID of the fact breaks the ties, so the pages are stable.
+func vacancyTableKeys(
+	sorts []model.TableSort,
+	source vacancyTableSource,
+) ([]pagination.Key, error) {
+	var keys []pagination.Key
+	for _, sort := range sorts {
+		column, err := lookupVacancyTableColumn(sort.Column)
+		if err != nil {
+			return nil, err
+		}
+
+		direction := strings.ToUpper(sort.Direction)
+		if direction != "" && direction != "ASC" && direction != "DESC" {
+			return nil, fmt.Errorf("invalid sort direction: %q", sort.Direction)
+		}
+
+		keys = append(keys, pagination.Key{
+			Expression: func(s *sql.Selector) string {
+				return column.Order(s, source)
+			},
+			Descending: direction == "DESC",
+		})
+	}
+
+	return append(keys, pagination.IDKey), nil
+}
+
+// vacancyTableTotalsRow is a row of the aggregation of the vacancy table.
+type vacancyTableTotalsRow struct {
+	ProcessId         *int     `sql:"process_id"`
+	ProcessTitle      *string  `sql:"process_title"`
+	NumRows           int      `sql:"num_rows"`
+	NumPositions      int      `sql:"num_positions"`
+	NumCandidates     int      `sql:"num_candidates"`
+	CompetitionRate   *float64 `sql:"competition_rate"`
+	NumInterviewed    int      `sql:"num_interviewed"`
+	NumHired          int      `sql:"num_hired"`
+	AverageHiringTime *float64 `sql:"average_hiring_time"`
+	NumFeedback       int      `sql:"num_feedback"`
+}
+
+// vacancyTableTotals aggregates the rows of the filtered query in the
+// database, once for the whole set or once per process.
+func vacancyTableTotals(
+	ctx context.Context,
+	query *ent.FactHiringProcessQuery,
+	source vacancyTableSource,
+	byProcess bool,
+) ([]model.TableTotals, error) {
+	var rows []vacancyTableTotalsRow
+	err := query.
+		Clone().
+		Modify(func(s *sql.Selector) {
+			vacancy := sql.Table(dimvacancy.Table).As("v")
+			s.Join(vacancy).On(
+				s.C(facthiringprocess.FieldDimVacancyId),
+				vacancy.C(dimvacancy.FieldID),
+			)
+
+			sum := func(column string) string {
+				return fmt.Sprintf("COALESCE(SUM(%s), 0)", column)
+			}
+			hiredCandidates := func(aggregate string) string {
+				return fmt.Sprintf(
+					"(SELECT %s FROM %s c "+
+						"WHERE c.dim_vacancy_db_id = v.id AND c.status = 'Hired')",
+					aggregate,
+					source.candidates,
+				)
+			}
+
+			applied := s.C(facthiringprocess.FieldMetTotalCandidatesApplied)
+			columns := []string{
+				"COUNT(*) AS num_rows",
+				sum("v.num_positions") + " AS num_positions",
+				sum(applied) + " AS num_candidates",
+				fmt.Sprintf(
+					"SUM(%s)::float8 / NULLIF(SUM(v.num_positions), 0) AS competition_rate",
+					applied,
+				),
+				sum(s.C(facthiringprocess.FieldMetTotalCandidatesInterviewed)) + " AS num_interviewed",
+				sum(s.C(facthiringprocess.FieldMetTotalCandidatesHired)) + " AS num_hired",
+				fmt.Sprintf(
+					"SUM(%s)::float8 / NULLIF(SUM(%s), 0) AS average_hiring_time",
+					hiredCandidates("SUM("+hiringDays(source.durationUnit)+")"),
+					hiredCandidates("COUNT(*)"),
+				),
+				sum(factColumns(
+					facthiringprocess.FieldMetTotalFeedbackPositive,
+					facthiringprocess.FieldMetTotalNeutral,
+					facthiringprocess.FieldMetTotalNegative,
+				)(s, source)) + " AS num_feedback",
+			}
+
+			if byProcess {
+				process := sql.Table(dimprocess.Table).As("p")
+				s.Join(process).On(
+					s.C(facthiringprocess.FieldDimProcessId),
+					process.C(dimprocess.FieldID),
+				)
+
+				columns = append(
+					columns,
+					process.C(dimprocess.FieldID)+" AS process_id",
+					process.C(dimprocess.FieldTitle)+" AS process_title",
+				)
+				s.GroupBy(
+					process.C(dimprocess.FieldID),
+					process.C(dimprocess.FieldTitle),
+				).OrderBy(
+					process.C(dimprocess.FieldTitle),
+					process.C(dimprocess.FieldID),
+				)
+			}
+
+			s.Select(columns...)
+		}).
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute the totals: %w", err)
+	}
+
+	totals := make([]model.TableTotals, len(rows))
+	for i, row := range rows {
+		totals[i] = model.TableTotals{
+			ProcessId:         row.ProcessId,
+			NumRows:           row.NumRows,
+			NumPositions:      row.NumPositions,
+			NumCandidates:     row.NumCandidates,
+			CompetitionRate:   float32Metric(row.CompetitionRate),
+			NumInterviewed:    row.NumInterviewed,
+			NumHired:          row.NumHired,
+			AverageHiringTime: float32Metric(row.AverageHiringTime),
+			NumFeedback:       row.NumFeedback,
+		}
+		if row.ProcessTitle != nil {
+			totals[i].ProcessTitle = *row.ProcessTitle
+		}
+	}
+
+	return totals, nil
+}
+
+func intMetric(value *float64) int {
+	if value == nil {
+		return 0
+	}
+	return int(*value)
+}
+
+func float32Metric(value *float64) *float32 {
+	if value == nil {
+		return nil
+	}
+	converted := float32(*value)
+	return &converted
+}
+
+func GetVacancyTable(
+	ctx context.Context,
+	client *ent.Client,
+	filter model.VacancyTableFilter,
+) (*model.Page[model.DashboardTableRow], error) {
+	query, err := applyFactHiringProcessQueryFilters(
+		createFactHiringProcessBaseQuery(client, filter.CandidateHistory),
+		filter.FactHiringProcessFilter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not apply filters: %w",
+			err,
+		)
+	}
+
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		query = query.Where(
+			facthiringprocess.Or(
+				facthiringprocess.HasDimProcessWith(
+					dimprocess.TitleContainsFold(search),
+				),
+				facthiringprocess.HasDimVacancyWith(
+					dimvacancy.TitleContainsFold(search),
+				),
+			),
+		)
+	}
+
+	days, err := parseDayCounter(ctx, client, filter.DurationUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	source := vacancyTableSource{
+		candidates:   warehouse.DimCandidateTable(filter.CandidateHistory),
+		durationUnit: days.Unit,
+	}
+
+	keys, err := vacancyTableKeys(filter.Sort, source)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	columns := vacancyTableColumns
+	if len(filter.Columns) > 0 {
+		columns = nil
+		for _, name := range filter.Columns {
+			column, err := lookupVacancyTableColumn(name)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+			}
+			columns = append(columns, column)
+		}
+	}
+
+	request, err := pagination.ParseRequest(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totalRecords, err := query.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := vacancyTableTotals(ctx, query, source, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var subtotals []model.TableTotals
+	if filter.Subtotals {
+		subtotals, err = vacancyTableTotals(ctx, query, source, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keyset := pagination.Keyset{
+		Keys:   keys,
+		Cursor: request.Cursor,
+	}
+
+	query.
+		Where(predicate.FactHiringProcess(keyset.Where())).
+		Order(keyset.Order()).
+		Modify(keyset.SelectKeys())
+
+	factHiringProcesses, err := query.
+		Offset(request.Offset()).
+		Limit(request.Limit()).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	factHiringProcesses, cursors := pagination.Trim(
+		factHiringProcesses,
+		request,
+		func(factHiringProcess *ent.FactHiringProcess) []any {
+			values := make([]any, len(keys))
+			for i := range keys {
+				value, _ := factHiringProcess.Value(pagination.KeyColumn(i))
+				values[i] = pagination.KeyValue(value)
+			}
+			return values
+		},
+	)
+
+	var tableDatas []model.DashboardTableRow
+	for _, factHiringProcess := range factHiringProcesses {
+		metrics := make(map[string]*float64)
+		for _, column := range columns {
+			if column.Metric == nil {
+				continue
+			}
+
+			value, err := column.Metric.Evaluate(factHiringProcess, days)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"could not evaluate metric %q for `FactHiringProcess` with ID %d: %w",
+					column.Metric.Id,
+					factHiringProcess.ID,
+					err,
+				)
+			}
+			metrics[column.Metric.Id] = value
+		}
+
+		tableDatas = append(tableDatas, model.DashboardTableRow{
+			ProcessTitle:      factHiringProcess.Edges.DimProcess.Title,
+			VacancyTitle:      factHiringProcess.Edges.DimVacancy.Title,
+			NumPositions:      intMetric(metrics[processing.MetricNumPositions.Id]),
+			NumCandidates:     intMetric(metrics[processing.MetricNumCandidates.Id]),
+			CompetitionRate:   float32Metric(metrics[processing.MetricCompetitionRate.Id]),
+			NumInterviewed:    intMetric(metrics[processing.MetricNumInterviewed.Id]),
+			NumHired:          intMetric(metrics[processing.MetricNumHired.Id]),
+			AverageHiringTime: float32Metric(metrics[processing.MetricAverageHiringTime.Id]),
+			NumFeedback:       intMetric(metrics[processing.MetricNumFeedback.Id]),
+			Columns:           filter.Columns,
+		})
+	}
+
+	page := pagination.NewPage(tableDatas, request, totalRecords, cursors)
+	page.Totals = &totals[0]
+	page.Subtotals = subtotals
+
+	return page, nil
+}
@@ -0,0 +1,139 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api5back/ent"
+	"api5back/ent/dimdatetime"
+	"api5back/src/calendar"
+)
+
+// calendarBatchSize bounds the rows created by each insert.
+const calendarBatchSize = 1000
+
+type CalendarLoad struct {
+	Created int
+	Updated int
+}
+
+// LoadCalendar fills the date dimension with every date from `from` to
+// `to`, both inclusive. Dates already in the dimension keep their rows,
+// and so the date keys of the facts pointing to them, and only get their
+// calendar attributes updated; the missing dates are created at
+// midnight.
+func LoadCalendar(
+	ctx context.Context,
+	client *ent.Client,
+	days *calendar.Calendar,
+	from, to time.Time,
+) (CalendarLoad, error) {
+	fromDate, toDate := Date(from), Date(to)
+	if toDate.Time.Before(fromDate.Time) {
+		return CalendarLoad{}, fmt.Errorf(
+			"end date %s is before start date %s",
+			toDate.Time.Format(time.DateOnly),
+			fromDate.Time.Format(time.DateOnly),
+		)
+	}
+
+	return inTx(ctx, client, "dim_datetime", func(tx *ent.Tx) (CalendarLoad, error) {
+		return loadCalendarInTx(ctx, tx, days, fromDate.Time, toDate.Time)
+	})
+}
+
+func loadCalendarInTx(
+	ctx context.Context,
+	tx *ent.Tx,
+	days *calendar.Calendar,
+	from, to time.Time,
+) (CalendarLoad, error) {
+	var result CalendarLoad
+
+	existing, err := tx.DimDatetime.Query().
+		Where(
+			dimdatetime.DateGTE(Date(from)),
+			dimdatetime.DateLTE(Date(to)),
+		).
+		All(ctx)
+	if err != nil {
+		return CalendarLoad{}, fmt.Errorf("could not query dim_datetime: %w", err)
+	}
+
+	existingByDate := make(map[time.Time][]*ent.DimDatetime)
+	for _, datetime := range existing {
+		date := Date(datetime.Date.Time).Time
+		existingByDate[date] = append(existingByDate[date], datetime)
+	}
+
+	var builders []*ent.DimDatetimeCreate
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := days.Day(date)
+
+		for _, datetime := range existingByDate[day.Date] {
+			update := tx.DimDatetime.UpdateOne(datetime)
+			setCalendarDay(update.Mutation(), day)
+			if err := update.Exec(ctx); err != nil {
+				return CalendarLoad{}, fmt.Errorf(
+					"could not update dim_datetime with ID %d: %w",
+					datetime.ID,
+					err,
+				)
+			}
+			result.Updated++
+		}
+
+		if len(existingByDate[day.Date]) > 0 {
+			continue
+		}
+
+		create := tx.DimDatetime.Create().
+			SetDate(Date(day.Date)).
+			SetHour(0).
+			SetMinute(0).
+			SetSecond(0)
+		setCalendarDay(create.Mutation(), day)
+		builders = append(builders, create)
+
+		if len(builders) == calendarBatchSize {
+			if err := tx.DimDatetime.CreateBulk(builders...).Exec(ctx); err != nil {
+				return CalendarLoad{}, fmt.Errorf("could not create dim_datetime: %w", err)
+			}
+			result.Created += len(builders)
+			builders = nil
+		}
+	}
+
+	if len(builders) > 0 {
+		if err := tx.DimDatetime.CreateBulk(builders...).Exec(ctx); err != nil {
+			return CalendarLoad{}, fmt.Errorf("could not create dim_datetime: %w", err)
+		}
+		result.Created += len(builders)
+	}
+
+	return result, nil
+}
+
+func setCalendarDay(mutation *ent.DimDatetimeMutation, day calendar.Day) {
+	mutation.SetYear(day.Year)
+	mutation.SetMonth(day.Month)
+	mutation.SetDay(day.Day)
+	mutation.SetWeekday(day.Weekday)
+	mutation.SetIsoYear(day.ISOYear)
+	mutation.SetIsoWeek(day.ISOWeek)
+	mutation.SetQuarter(day.Quarter)
+	mutation.SetFiscalYear(day.FiscalYear)
+	mutation.SetFiscalQuarter(day.FiscalQuarter)
+	mutation.SetMonthNamePt(day.MonthNamePt)
+	mutation.SetMonthNameEn(day.MonthNameEn)
+	mutation.SetDayNamePt(day.DayNamePt)
+	mutation.SetDayNameEn(day.DayNameEn)
+	mutation.SetIsWeekend(day.IsWeekend)
+	mutation.SetIsHoliday(day.IsHoliday)
+	if day.IsHoliday {
+		mutation.SetHolidayName(day.HolidayName)
+	} else {
+		mutation.ClearHolidayName()
+	}
+}
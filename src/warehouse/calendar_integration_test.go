@@ -0,0 +1,68 @@
+//go:build integration
+// +build integration
+
+package warehouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api5back/ent/dimdatetime"
+	"api5back/src/calendar"
+	"api5back/src/database"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCalendar(t *testing.T) {
+	ctx := context.Background()
+	var intEnv *database.IntegrationEnvironment = nil
+
+	if testResult := t.Run("Setup database connection", func(t *testing.T) {
+		intEnv = database.DefaultIntegrationEnvironment(ctx)
+
+		require.NotNil(t, intEnv)
+		require.NoError(t, intEnv.Error)
+		require.NotNil(t, intEnv.Client)
+	}); !testResult {
+		t.Fatalf("Setup test failed")
+	}
+
+	if testResult := t.Run("LoadCalendar keeps the keys of the dates loaded", func(t *testing.T) {
+		days := calendar.New(calendar.Options{
+			Holidays: []calendar.HolidayCalendar{calendar.BrazilNational},
+		})
+		from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+		before, err := intEnv.Client.DimDatetime.Get(ctx, 1)
+		require.NoError(t, err)
+
+		_, err = LoadCalendar(ctx, intEnv.Client, days, from, to)
+		require.NoError(t, err)
+
+		// loading again only updates the dates
+		result, err := LoadCalendar(ctx, intEnv.Client, days, from, to)
+		require.NoError(t, err)
+		require.Zero(t, result.Created)
+		require.NotZero(t, result.Updated)
+
+		after, err := intEnv.Client.DimDatetime.Get(ctx, 1)
+		require.NoError(t, err)
+		require.True(t, sameDate(before.Date, after.Date))
+		require.Equal(t, before.Date.Time.Day(), after.Day)
+
+		christmas, err := intEnv.Client.DimDatetime.Query().
+			Where(dimdatetime.Date(Date(time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)))).
+			Only(ctx)
+		require.NoError(t, err)
+		require.True(t, christmas.IsHoliday)
+		require.Equal(t, "Natal", christmas.HolidayName)
+
+		_, err = LoadCalendar(ctx, intEnv.Client, days, to, from)
+		require.Error(t, err)
+	}); !testResult {
+		t.Fatalf("LoadCalendar test failed")
+	}
+}
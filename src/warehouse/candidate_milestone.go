@@ -0,0 +1,219 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/ent/dimcandidate"
+	"api5back/ent/diminterview"
+	"api5back/ent/dimoffer"
+	"api5back/ent/factcandidatemilestone"
+	"api5back/ent/factcandidatestatuschange"
+	"api5back/ent/predicate"
+	"api5back/src/property"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// candidateMilestones are the milestone dates of an application. Each
+// one is nil until the candidate reaches it.
+type candidateMilestones struct {
+	applied        *pgtype.Date
+	screened       *pgtype.Date
+	firstInterview *pgtype.Date
+	offer          *pgtype.Date
+	hired          *pgtype.Date
+	rejected       *pgtype.Date
+}
+
+// LoadCandidateMilestone upserts the accumulating snapshot of the
+// application of the candidate, from its status changes, interviews and
+// offers across every version of the candidate. `LoadDimCandidate`,
+// `LoadCandidateStatusChange`, `LoadDimInterview` and `LoadDimOffer` run
+// it in their own transaction, so it only needs to be called after rows
+// were written another way. Milestones already recorded are kept, unless an earlier date is found
+// for them.
+func LoadCandidateMilestone(
+	ctx context.Context,
+	client *ent.Client,
+	candidate *ent.DimCandidate,
+) (*ent.FactCandidateMilestone, error) {
+	return inTx(ctx, client, "fact_candidate_milestone", func(tx *ent.Tx) (*ent.FactCandidateMilestone, error) {
+		return loadCandidateMilestoneInTx(ctx, tx, candidateKey{candidate.DbId, candidate.DimVacancyDbId})
+	})
+}
+
+// refreshCandidateMilestoneInTx upserts the snapshot of the candidate of
+// the version with the given ID, once one of its milestones is loaded.
+func refreshCandidateMilestoneInTx(
+	ctx context.Context,
+	tx *ent.Tx,
+	candidateId int,
+) error {
+	candidate, err := tx.DimCandidate.Get(ctx, candidateId)
+	if err != nil {
+		return fmt.Errorf("could not query candidate with ID %d: %w", candidateId, err)
+	}
+
+	if _, err := loadCandidateMilestoneInTx(
+		ctx, tx,
+		candidateKey{candidate.DbId, candidate.DimVacancyDbId},
+	); err != nil {
+		return fmt.Errorf("could not load the milestones of candidate %d: %w", candidate.DbId, err)
+	}
+
+	return nil
+}
+
+func loadCandidateMilestoneInTx(
+	ctx context.Context,
+	tx *ent.Tx,
+	key candidateKey,
+) (*ent.FactCandidateMilestone, error) {
+	sameCandidate := []predicate.DimCandidate{
+		dimcandidate.DbId(key.dbId),
+		dimcandidate.DimVacancyDbId(key.vacancyDbId),
+	}
+
+	latest, err := tx.DimCandidate.Query().
+		Where(sameCandidate...).
+		Order(ent.Desc(dimcandidate.FieldID)).
+		First(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not query candidate %d: %w", key.dbId, err)
+	}
+
+	changes, err := tx.FactCandidateStatusChange.Query().
+		Where(factcandidatestatuschange.HasDimCandidateWith(sameCandidate...)).
+		Order(
+			ent.Asc(factcandidatestatuschange.FieldChangedAt),
+			ent.Asc(factcandidatestatuschange.FieldID),
+		).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not query the status changes of candidate %d: %w", key.dbId, err)
+	}
+
+	interviews, err := tx.DimInterview.Query().
+		Where(diminterview.HasDimCandidateWith(sameCandidate...)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not query the interviews of candidate %d: %w", key.dbId, err)
+	}
+
+	offers, err := tx.DimOffer.Query().
+		Where(dimoffer.HasDimCandidateWith(sameCandidate...)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not query the offers of candidate %d: %w", key.dbId, err)
+	}
+
+	milestones := deriveCandidateMilestones(latest, changes, interviews, offers)
+
+	existing, err := tx.FactCandidateMilestone.Query().
+		Where(
+			factcandidatemilestone.CandidateDbId(key.dbId),
+			factcandidatemilestone.DimVacancyId(key.vacancyDbId),
+		).
+		Only(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		return nil, fmt.Errorf("could not query the milestones of candidate %d: %w", key.dbId, err)
+	}
+
+	if existing == nil {
+		return tx.FactCandidateMilestone.Create().
+			SetCandidateDbId(key.dbId).
+			SetDimVacancyId(key.vacancyDbId).
+			SetDimCandidateId(latest.ID).
+			SetAppliedDate(milestones.applied).
+			SetScreenedDate(milestones.screened).
+			SetFirstInterviewDate(milestones.firstInterview).
+			SetOfferDate(milestones.offer).
+			SetHiredDate(milestones.hired).
+			SetRejectedDate(milestones.rejected).
+			Save(ctx)
+	}
+
+	return tx.FactCandidateMilestone.UpdateOne(existing).
+		SetDimCandidateId(latest.ID).
+		SetAppliedDate(earliestDate(existing.AppliedDate, milestones.applied)).
+		SetScreenedDate(earliestDate(existing.ScreenedDate, milestones.screened)).
+		SetFirstInterviewDate(earliestDate(existing.FirstInterviewDate, milestones.firstInterview)).
+		SetOfferDate(earliestDate(existing.OfferDate, milestones.offer)).
+		SetHiredDate(earliestDate(existing.HiredDate, milestones.hired)).
+		SetRejectedDate(earliestDate(existing.RejectedDate, milestones.rejected)).
+		Save(ctx)
+}
+
+// deriveCandidateMilestones finds the milestones of the candidate, given
+// its status changes in order. A candidate is screened once it leaves
+// the analysis, and hired or rejected the first time it moves to those
+// statuses; when it has no status change recorded, the `updatedAt` of
+// its latest version dates its current status. The first interview
+// leaves out the ones the candidate did not show up to.
+func deriveCandidateMilestones(
+	candidate *ent.DimCandidate,
+	changes []*ent.FactCandidateStatusChange,
+	interviews []*ent.DimInterview,
+	offers []*ent.DimOffer,
+) candidateMilestones {
+	milestones := candidateMilestones{
+		applied: candidate.ApplyDate,
+	}
+
+	for _, change := range changes {
+		changedAt := Date(change.ChangedAt)
+		milestones.applied = earliestDate(milestones.applied, changedAt)
+
+		if change.FromStatus != nil &&
+			*change.FromStatus == property.DimCandidateStatusInAnalysis {
+			milestones.screened = earliestDate(milestones.screened, changedAt)
+		}
+
+		switch change.ToStatus {
+		case property.DimCandidateStatusHired:
+			milestones.hired = earliestDate(milestones.hired, changedAt)
+		case property.DimCandidateStatusRejected:
+			milestones.rejected = earliestDate(milestones.rejected, changedAt)
+		}
+	}
+
+	if len(changes) == 0 {
+		switch candidate.Status {
+		case property.DimCandidateStatusHired:
+			milestones.hired = candidate.UpdatedAt
+		case property.DimCandidateStatusRejected:
+			milestones.rejected = candidate.UpdatedAt
+		}
+	}
+
+	for _, interview := range interviews {
+		if interview.Outcome != property.DimInterviewOutcomeNoShow {
+			milestones.firstInterview = earliestDate(milestones.firstInterview, interview.Date)
+		}
+	}
+
+	for _, offer := range offers {
+		milestones.offer = earliestDate(milestones.offer, offer.SentDate)
+	}
+
+	return milestones
+}
+
+// earliestDate returns the earliest of the dates, ignoring the empty
+// ones, or nil when both are empty.
+func earliestDate(a, b *pgtype.Date) *pgtype.Date {
+	if a == nil || !a.Valid {
+		if b == nil || !b.Valid {
+			return nil
+		}
+		return b
+	}
+
+	if b == nil || !b.Valid || !b.Time.Before(a.Time) {
+		return a
+	}
+
+	return b
+}
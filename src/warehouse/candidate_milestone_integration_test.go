@@ -0,0 +1,152 @@
+//go:build integration
+// +build integration
+
+package warehouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/ent/dimcandidate"
+	"api5back/ent/factcandidatemilestone"
+	"api5back/src/database"
+	"api5back/src/property"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCandidateMilestone(t *testing.T) {
+	ctx := context.Background()
+	var intEnv *database.IntegrationEnvironment = nil
+
+	if testResult := t.Run("Setup database connection", func(t *testing.T) {
+		intEnv = database.DefaultIntegrationEnvironment(ctx)
+
+		require.NotNil(t, intEnv)
+		require.NoError(t, intEnv.Error)
+		require.NotNil(t, intEnv.Client)
+	}); !testResult {
+		t.Fatalf("Setup test failed")
+	}
+
+	if testResult := t.Run("LoadCandidateMilestone upserts one row per application", func(t *testing.T) {
+		candidate, err := intEnv.Client.DimCandidate.Query().
+			Where(dimcandidate.StatusEQ(property.DimCandidateStatusHired)).
+			First(ctx)
+		require.NoError(t, err)
+
+		first, err := LoadCandidateMilestone(ctx, intEnv.Client, candidate)
+		require.NoError(t, err)
+		require.NotNil(t, first.AppliedDate)
+		require.NotNil(t, first.ScreenedDate)
+		require.NotNil(t, first.FirstInterviewDate)
+		require.NotNil(t, first.OfferDate)
+		require.NotNil(t, first.HiredDate)
+		require.Nil(t, first.RejectedDate)
+
+		second, err := LoadCandidateMilestone(ctx, intEnv.Client, candidate)
+		require.NoError(t, err)
+		require.Equal(t, first.ID, second.ID)
+
+		count, err := intEnv.Client.FactCandidateMilestone.Query().
+			Where(
+				factcandidatemilestone.CandidateDbId(candidate.DbId),
+				factcandidatemilestone.DimVacancyId(candidate.DimVacancyDbId),
+			).
+			Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	}); !testResult {
+		t.Fatalf("LoadCandidateMilestone test failed")
+	}
+
+	if testResult := t.Run("Loading candidates, status changes and offers upserts the milestones", func(t *testing.T) {
+		existing, err := intEnv.Client.DimCandidate.Query().First(ctx)
+		require.NoError(t, err)
+
+		applyDate := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+		rejectedAt := applyDate.AddDate(0, 0, 10)
+
+		candidate, err := LoadDimCandidate(ctx, intEnv.Client, ent.DimCandidate{
+			DbId:           999999,
+			Name:           "Milestone",
+			Email:          "milestone@mail.com",
+			Phone:          "11999999999",
+			DimVacancyDbId: existing.DimVacancyDbId,
+			ApplyDate:      Date(applyDate),
+			Status:         property.DimCandidateStatusInAnalysis,
+		}, applyDate)
+		require.NoError(t, err)
+
+		milestoneOf := func() *ent.FactCandidateMilestone {
+			milestone, err := intEnv.Client.FactCandidateMilestone.Query().
+				Where(
+					factcandidatemilestone.CandidateDbId(candidate.DbId),
+					factcandidatemilestone.DimVacancyId(candidate.DimVacancyDbId),
+				).
+				Only(ctx)
+			require.NoError(t, err)
+			return milestone
+		}
+
+		require.True(t, Date(applyDate).Time.Equal(milestoneOf().AppliedDate.Time))
+		require.Nil(t, milestoneOf().RejectedDate)
+
+		_, err = LoadCandidateStatusChange(
+			ctx, intEnv.Client,
+			candidate,
+			property.DimCandidateStatusRejected,
+			rejectedAt,
+		)
+		require.NoError(t, err)
+
+		rejected := milestoneOf()
+		require.NotNil(t, rejected.RejectedDate)
+		require.True(t, Date(rejectedAt).Time.Equal(rejected.RejectedDate.Time))
+	}); !testResult {
+		t.Fatalf("Milestone load path test failed")
+	}
+
+	if testResult := t.Run("Loading interviews upserts the milestones", func(t *testing.T) {
+		existing, err := intEnv.Client.DimCandidate.Query().First(ctx)
+		require.NoError(t, err)
+
+		applyDate := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+		interviewedAt := applyDate.AddDate(0, 0, 7)
+
+		candidate, err := LoadDimCandidate(ctx, intEnv.Client, ent.DimCandidate{
+			DbId:           999998,
+			Name:           "Interview",
+			Email:          "interview@mail.com",
+			Phone:          "11999999998",
+			DimVacancyDbId: existing.DimVacancyDbId,
+			ApplyDate:      Date(applyDate),
+			Status:         property.DimCandidateStatusInAnalysis,
+		}, applyDate)
+		require.NoError(t, err)
+
+		_, err = LoadDimInterview(ctx, intEnv.Client, ent.DimInterview{
+			DbId:           candidate.DbId,
+			DimCandidateId: candidate.ID,
+			DimVacancyId:   candidate.DimVacancyDbId,
+			Date:           Date(interviewedAt),
+			Interviewer:    "Interviewer",
+			Outcome:        property.DimInterviewOutcomePending,
+		})
+		require.NoError(t, err)
+
+		milestone, err := intEnv.Client.FactCandidateMilestone.Query().
+			Where(
+				factcandidatemilestone.CandidateDbId(candidate.DbId),
+				factcandidatemilestone.DimVacancyId(candidate.DimVacancyDbId),
+			).
+			Only(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, milestone.FirstInterviewDate)
+		require.True(t, Date(interviewedAt).Time.Equal(milestone.FirstInterviewDate.Time))
+	}); !testResult {
+		t.Fatalf("Interview milestone test failed")
+	}
+}
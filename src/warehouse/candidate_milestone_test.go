@@ -0,0 +1,68 @@
+package warehouse
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEarliestDate(t *testing.T) {
+	july := Date(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
+	august := Date(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC))
+
+	require.Equal(t, july, earliestDate(july, august))
+	require.Equal(t, july, earliestDate(august, july))
+	require.Equal(t, august, earliestDate(nil, august))
+	require.Equal(t, august, earliestDate(august, nil))
+	require.Nil(t, earliestDate(nil, nil))
+}
+
+func TestDeriveCandidateMilestones(t *testing.T) {
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	inAnalysis := property.DimCandidateStatusInAnalysis
+	interview := property.DimCandidateStatusInterview
+
+	candidate := &ent.DimCandidate{
+		ApplyDate: Date(day(7, 1)),
+		Status:    property.DimCandidateStatusHired,
+		UpdatedAt: Date(day(9, 1)),
+	}
+
+	milestones := deriveCandidateMilestones(
+		candidate,
+		[]*ent.FactCandidateStatusChange{
+			{ToStatus: inAnalysis, ChangedAt: day(7, 1)},
+			{FromStatus: &inAnalysis, ToStatus: interview, ChangedAt: day(7, 10)},
+			{FromStatus: &interview, ToStatus: property.DimCandidateStatusHired, ChangedAt: day(8, 20)},
+		},
+		[]*ent.DimInterview{
+			{Date: Date(day(7, 12)), Outcome: property.DimInterviewOutcomeNoShow},
+			{Date: Date(day(7, 20)), Outcome: property.DimInterviewOutcomeApproved},
+			{Date: Date(day(7, 15)), Outcome: property.DimInterviewOutcomeApproved},
+		},
+		[]*ent.DimOffer{
+			{SentDate: Date(day(8, 10))},
+			{SentDate: Date(day(8, 5))},
+		},
+	)
+	require.Equal(t, candidateMilestones{
+		applied:        Date(day(7, 1)),
+		screened:       Date(day(7, 10)),
+		firstInterview: Date(day(7, 15)),
+		offer:          Date(day(8, 5)),
+		hired:          Date(day(8, 20)),
+	}, milestones)
+
+	// without status changes, `updatedAt` dates the current status
+	milestones = deriveCandidateMilestones(candidate, nil, nil, nil)
+	require.Equal(t, candidateMilestones{
+		applied: Date(day(7, 1)),
+		hired:   Date(day(9, 1)),
+	}, milestones)
+}
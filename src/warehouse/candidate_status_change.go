@@ -0,0 +1,94 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api5back/ent"
+	"api5back/ent/dimcandidate"
+	"api5back/ent/factcandidatestatuschange"
+	"api5back/src/property"
+)
+
+// LoadCandidateStatusChange records that the candidate moved to the
+// status at the given time. The status it moved from is the one of its
+// last recorded change, across every version of the candidate, so the
+// changes must be loaded in order. Nothing is recorded when the status
+// did not change; otherwise the milestones of the candidate are upserted
+// along with the change.
+func LoadCandidateStatusChange(
+	ctx context.Context,
+	client *ent.Client,
+	candidate *ent.DimCandidate,
+	toStatus property.DimCandidateStatus,
+	changedAt time.Time,
+) (*ent.FactCandidateStatusChange, error) {
+	return inTx(ctx, client, "fact_candidate_status_change", func(tx *ent.Tx) (*ent.FactCandidateStatusChange, error) {
+		return loadCandidateStatusChangeInTx(ctx, tx, candidate, toStatus, changedAt)
+	})
+}
+
+func loadCandidateStatusChangeInTx(
+	ctx context.Context,
+	tx *ent.Tx,
+	candidate *ent.DimCandidate,
+	toStatus property.DimCandidateStatus,
+	changedAt time.Time,
+) (*ent.FactCandidateStatusChange, error) {
+	last, err := tx.FactCandidateStatusChange.Query().
+		Where(factcandidatestatuschange.HasDimCandidateWith(
+			dimcandidate.DbId(candidate.DbId),
+			dimcandidate.DimVacancyDbId(candidate.DimVacancyDbId),
+		)).
+		Order(
+			ent.Desc(factcandidatestatuschange.FieldChangedAt),
+			ent.Desc(factcandidatestatuschange.FieldID),
+		).
+		First(ctx)
+	if err != nil && !ent.IsNotFound(err) {
+		return nil, fmt.Errorf(
+			"could not query the last status change of candidate %d: %w",
+			candidate.DbId,
+			err,
+		)
+	}
+
+	changeBuilder := tx.FactCandidateStatusChange.Create().
+		SetDimCandidateId(candidate.ID).
+		SetDimVacancyId(candidate.DimVacancyDbId).
+		SetToStatus(toStatus).
+		SetChangedAt(changedAt)
+
+	if last != nil {
+		if last.ToStatus == toStatus {
+			return last, nil
+		}
+
+		if changedAt.Before(last.ChangedAt) {
+			return nil, fmt.Errorf(
+				"status change of candidate %d at %s is older than its last one, at %s",
+				candidate.DbId,
+				changedAt.Format(time.RFC3339),
+				last.ChangedAt.Format(time.RFC3339),
+			)
+		}
+
+		changeBuilder.SetFromStatus(last.ToStatus)
+	}
+
+	change, err := changeBuilder.Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not create status change of candidate %d: %w",
+			candidate.DbId,
+			err,
+		)
+	}
+
+	if err := refreshCandidateMilestoneInTx(ctx, tx, candidate.ID); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
@@ -0,0 +1,439 @@
+package warehouse
+
+import (
+	"context"
+	"time"
+
+	"api5back/ent"
+	"api5back/ent/dimcandidate"
+	"api5back/ent/dimdepartment"
+	"api5back/ent/dimfeedback"
+	"api5back/ent/diminterview"
+	"api5back/ent/dimoffer"
+	"api5back/ent/dimprocess"
+	"api5back/ent/dimuser"
+	"api5back/ent/dimvacancy"
+	"api5back/ent/factcandidatemilestone"
+	"api5back/ent/factcandidatestatuschange"
+	"api5back/ent/facthiringprocess"
+	"api5back/ent/factpipelinesnapshot"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var dimUser = dimension[ent.DimUser]{
+	name: "dim_user",
+	current: func(ctx context.Context, tx *ent.Tx, version *ent.DimUser) (*ent.DimUser, error) {
+		return tx.DimUser.Query().
+			Where(
+				dimuser.DbId(version.DbId),
+				dimuser.IsCurrent(true),
+			).
+			Only(ctx)
+	},
+	changed: func(current, version *ent.DimUser) bool {
+		return current.Name != version.Name ||
+			current.Occupation != version.Occupation
+	},
+	validFrom: func(version *ent.DimUser) *pgtype.Date {
+		return version.ValidFrom
+	},
+	close: func(ctx context.Context, tx *ent.Tx, current *ent.DimUser, validTo *pgtype.Date) error {
+		return tx.DimUser.UpdateOneID(current.ID).
+			SetValidTo(validTo).
+			SetIsCurrent(false).
+			Exec(ctx)
+	},
+	open: func(ctx context.Context, tx *ent.Tx, version *ent.DimUser, validFrom *pgtype.Date) (*ent.DimUser, error) {
+		return tx.DimUser.Create().
+			SetDbId(version.DbId).
+			SetName(version.Name).
+			SetOccupation(version.Occupation).
+			SetUpdatedAt(version.UpdatedAt).
+			SetValidFrom(validFrom).
+			SetIsCurrent(true).
+			Save(ctx)
+	},
+	repoint: func(ctx context.Context, tx *ent.Tx, closed, opened *ent.DimUser) error {
+		if err := tx.FactHiringProcess.Update().
+			Where(facthiringprocess.DimUserId(closed.ID)).
+			SetDimUserId(opened.ID).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		return tx.FactPipelineSnapshot.Update().
+			Where(factpipelinesnapshot.DimUserId(closed.ID)).
+			SetDimUserId(opened.ID).
+			Exec(ctx)
+	},
+}
+
+var dimDepartment = dimension[ent.DimDepartment]{
+	name: "dim_department",
+	current: func(ctx context.Context, tx *ent.Tx, version *ent.DimDepartment) (*ent.DimDepartment, error) {
+		return tx.DimDepartment.Query().
+			Where(
+				dimdepartment.DbId(version.DbId),
+				dimdepartment.IsCurrent(true),
+			).
+			Only(ctx)
+	},
+	changed: func(current, version *ent.DimDepartment) bool {
+		return current.Name != version.Name ||
+			current.Description != version.Description
+	},
+	validFrom: func(version *ent.DimDepartment) *pgtype.Date {
+		return version.ValidFrom
+	},
+	close: func(ctx context.Context, tx *ent.Tx, current *ent.DimDepartment, validTo *pgtype.Date) error {
+		return tx.DimDepartment.UpdateOneID(current.ID).
+			SetValidTo(validTo).
+			SetIsCurrent(false).
+			Exec(ctx)
+	},
+	open: func(ctx context.Context, tx *ent.Tx, version *ent.DimDepartment, validFrom *pgtype.Date) (*ent.DimDepartment, error) {
+		return tx.DimDepartment.Create().
+			SetDbId(version.DbId).
+			SetName(version.Name).
+			SetDescription(version.Description).
+			SetValidFrom(validFrom).
+			SetIsCurrent(true).
+			Save(ctx)
+	},
+	repoint: func(ctx context.Context, tx *ent.Tx, closed, opened *ent.DimDepartment) error {
+		if err := tx.DimProcess.Update().
+			Where(dimprocess.DimDepartmentId(closed.ID)).
+			SetDimDepartmentId(opened.ID).
+			Exec(ctx); err != nil {
+			return err
+		}
+
+		return tx.FactPipelineSnapshot.Update().
+			Where(factpipelinesnapshot.DimDepartmentId(closed.ID)).
+			SetDimDepartmentId(opened.ID).
+			Exec(ctx)
+	},
+}
+
+var dimProcess = dimension[ent.DimProcess]{
+	name: "dim_process",
+	current: func(ctx context.Context, tx *ent.Tx, version *ent.DimProcess) (*ent.DimProcess, error) {
+		return tx.DimProcess.Query().
+			Where(
+				dimprocess.DbId(version.DbId),
+				dimprocess.IsCurrent(true),
+			).
+			Only(ctx)
+	},
+	changed: func(current, version *ent.DimProcess) bool {
+		return current.Title != version.Title ||
+			!sameDate(current.InitialDate, version.InitialDate) ||
+			!sameDate(current.FinishDate, version.FinishDate) ||
+			current.Status != version.Status ||
+			current.DimUsrId != version.DimUsrId ||
+			current.Description != version.Description ||
+			current.DimDepartmentId != version.DimDepartmentId
+	},
+	validFrom: func(version *ent.DimProcess) *pgtype.Date {
+		return version.ValidFrom
+	},
+	close: func(ctx context.Context, tx *ent.Tx, current *ent.DimProcess, validTo *pgtype.Date) error {
+		return tx.DimProcess.UpdateOneID(current.ID).
+			SetValidTo(validTo).
+			SetIsCurrent(false).
+			Exec(ctx)
+	},
+	open: func(ctx context.Context, tx *ent.Tx, version *ent.DimProcess, validFrom *pgtype.Date) (*ent.DimProcess, error) {
+		return tx.DimProcess.Create().
+			SetDbId(version.DbId).
+			SetTitle(version.Title).
+			SetInitialDate(version.InitialDate).
+			SetFinishDate(version.FinishDate).
+			SetStatus(version.Status).
+			SetDimUsrId(version.DimUsrId).
+			SetDescription(version.Description).
+			SetDimDepartmentId(version.DimDepartmentId).
+			SetValidFrom(validFrom).
+			SetIsCurrent(true).
+			Save(ctx)
+	},
+	repoint: func(ctx context.Context, tx *ent.Tx, closed, opened *ent.DimProcess) error {
+		return tx.FactHiringProcess.Update().
+			Where(facthiringprocess.DimProcessId(closed.ID)).
+			SetDimProcessId(opened.ID).
+			Exec(ctx)
+	},
+}
+
+var dimVacancy = dimension[ent.DimVacancy]{
+	name: "dim_vacancy",
+	current: func(ctx context.Context, tx *ent.Tx, version *ent.DimVacancy) (*ent.DimVacancy, error) {
+		return tx.DimVacancy.Query().
+			Where(
+				dimvacancy.DbId(version.DbId),
+				dimvacancy.IsCurrent(true),
+			).
+			Only(ctx)
+	},
+	changed: func(current, version *ent.DimVacancy) bool {
+		return current.Title != version.Title ||
+			current.NumPositions != version.NumPositions ||
+			current.ReqId != version.ReqId ||
+			current.Status != version.Status ||
+			current.Location != version.Location ||
+			current.DimUsrId != version.DimUsrId ||
+			!sameDate(current.OpeningDate, version.OpeningDate) ||
+			!sameDate(current.ClosingDate, version.ClosingDate)
+	},
+	validFrom: func(version *ent.DimVacancy) *pgtype.Date {
+		return version.ValidFrom
+	},
+	close: func(ctx context.Context, tx *ent.Tx, current *ent.DimVacancy, validTo *pgtype.Date) error {
+		return tx.DimVacancy.UpdateOneID(current.ID).
+			SetValidTo(validTo).
+			SetIsCurrent(false).
+			Exec(ctx)
+	},
+	open: func(ctx context.Context, tx *ent.Tx, version *ent.DimVacancy, validFrom *pgtype.Date) (*ent.DimVacancy, error) {
+		return tx.DimVacancy.Create().
+			SetDbId(version.DbId).
+			SetTitle(version.Title).
+			SetNumPositions(version.NumPositions).
+			SetReqId(version.ReqId).
+			SetStatus(version.Status).
+			SetLocation(version.Location).
+			SetDimUsrId(version.DimUsrId).
+			SetOpeningDate(version.OpeningDate).
+			SetClosingDate(version.ClosingDate).
+			SetValidFrom(validFrom).
+			SetIsCurrent(true).
+			Save(ctx)
+	},
+	repoint: func(ctx context.Context, tx *ent.Tx, closed, opened *ent.DimVacancy) error {
+		for _, update := range []interface{ Exec(context.Context) error }{
+			tx.DimCandidate.Update().
+				Where(dimcandidate.DimVacancyDbId(closed.ID)).
+				SetDimVacancyDbId(opened.ID),
+			tx.DimInterview.Update().
+				Where(diminterview.DimVacancyId(closed.ID)).
+				SetDimVacancyId(opened.ID),
+			tx.DimFeedback.Update().
+				Where(dimfeedback.DimVacancyId(closed.ID)).
+				SetDimVacancyId(opened.ID),
+			tx.DimOffer.Update().
+				Where(dimoffer.DimVacancyId(closed.ID)).
+				SetDimVacancyId(opened.ID),
+			tx.FactCandidateStatusChange.Update().
+				Where(factcandidatestatuschange.DimVacancyId(closed.ID)).
+				SetDimVacancyId(opened.ID),
+			tx.FactCandidateMilestone.Update().
+				Where(factcandidatemilestone.DimVacancyId(closed.ID)).
+				SetDimVacancyId(opened.ID),
+			tx.FactHiringProcess.Update().
+				Where(facthiringprocess.DimVacancyId(closed.ID)).
+				SetDimVacancyId(opened.ID),
+		} {
+			if err := update.Exec(ctx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+// candidates are identified by their `dbId` within the vacancy they
+// applied to
+var dimCandidate = dimension[ent.DimCandidate]{
+	name: "dim_candidate",
+	current: func(ctx context.Context, tx *ent.Tx, version *ent.DimCandidate) (*ent.DimCandidate, error) {
+		return tx.DimCandidate.Query().
+			Where(
+				dimcandidate.DbId(version.DbId),
+				dimcandidate.DimVacancyDbId(version.DimVacancyDbId),
+				dimcandidate.IsCurrent(true),
+			).
+			Only(ctx)
+	},
+	changed: func(current, version *ent.DimCandidate) bool {
+		return current.Name != version.Name ||
+			current.Email != version.Email ||
+			current.Phone != version.Phone ||
+			current.Score != version.Score ||
+			!sameDate(current.ApplyDate, version.ApplyDate) ||
+			current.Status != version.Status ||
+			!sameDate(current.UpdatedAt, version.UpdatedAt)
+	},
+	validFrom: func(version *ent.DimCandidate) *pgtype.Date {
+		return version.ValidFrom
+	},
+	close: func(ctx context.Context, tx *ent.Tx, current *ent.DimCandidate, validTo *pgtype.Date) error {
+		return tx.DimCandidate.UpdateOneID(current.ID).
+			SetValidTo(validTo).
+			SetIsCurrent(false).
+			Exec(ctx)
+	},
+	open: func(ctx context.Context, tx *ent.Tx, version *ent.DimCandidate, validFrom *pgtype.Date) (*ent.DimCandidate, error) {
+		return tx.DimCandidate.Create().
+			SetDbId(version.DbId).
+			SetName(version.Name).
+			SetEmail(version.Email).
+			SetPhone(version.Phone).
+			SetScore(version.Score).
+			SetDimVacancyDbId(version.DimVacancyDbId).
+			SetApplyDate(version.ApplyDate).
+			SetStatus(version.Status).
+			SetUpdatedAt(version.UpdatedAt).
+			SetValidFrom(validFrom).
+			SetIsCurrent(true).
+			Save(ctx)
+	},
+}
+
+var dimOffer = dimension[ent.DimOffer]{
+	name: "dim_offer",
+	current: func(ctx context.Context, tx *ent.Tx, version *ent.DimOffer) (*ent.DimOffer, error) {
+		return tx.DimOffer.Query().
+			Where(
+				dimoffer.DbId(version.DbId),
+				dimoffer.IsCurrent(true),
+			).
+			Only(ctx)
+	},
+	changed: func(current, version *ent.DimOffer) bool {
+		return current.Salary != version.Salary ||
+			current.Currency != version.Currency ||
+			!sameDate(current.SentDate, version.SentDate) ||
+			!sameDate(current.AcceptedDate, version.AcceptedDate) ||
+			!sameDate(current.DeclinedDate, version.DeclinedDate) ||
+			current.Status != version.Status
+	},
+	validFrom: func(version *ent.DimOffer) *pgtype.Date {
+		return version.ValidFrom
+	},
+	close: func(ctx context.Context, tx *ent.Tx, current *ent.DimOffer, validTo *pgtype.Date) error {
+		return tx.DimOffer.UpdateOneID(current.ID).
+			SetValidTo(validTo).
+			SetIsCurrent(false).
+			Exec(ctx)
+	},
+	open: func(ctx context.Context, tx *ent.Tx, version *ent.DimOffer, validFrom *pgtype.Date) (*ent.DimOffer, error) {
+		return tx.DimOffer.Create().
+			SetDbId(version.DbId).
+			SetDimCandidateId(version.DimCandidateId).
+			SetDimVacancyId(version.DimVacancyId).
+			SetSalary(version.Salary).
+			SetCurrency(version.Currency).
+			SetSentDate(version.SentDate).
+			SetAcceptedDate(version.AcceptedDate).
+			SetDeclinedDate(version.DeclinedDate).
+			SetStatus(version.Status).
+			SetValidFrom(validFrom).
+			SetIsCurrent(true).
+			Save(ctx)
+	},
+}
+
+// LoadDimUser stores the user as the version valid from the date on,
+// closing the current version of the same `dbId` when it changed. The
+// facts and snapshots of the user are moved to the new version.
+func LoadDimUser(
+	ctx context.Context,
+	client *ent.Client,
+	user ent.DimUser,
+	validFrom time.Time,
+) (*ent.DimUser, error) {
+	return load(ctx, client, dimUser, &user, validFrom)
+}
+
+// LoadDimDepartment stores the department as the version valid from the
+// date on, closing the current version of the same `dbId` when it
+// changed. The processes and snapshots of the department are moved to
+// the new version.
+func LoadDimDepartment(
+	ctx context.Context,
+	client *ent.Client,
+	department ent.DimDepartment,
+	validFrom time.Time,
+) (*ent.DimDepartment, error) {
+	return load(ctx, client, dimDepartment, &department, validFrom)
+}
+
+// LoadDimProcess stores the process as the version valid from the date
+// on, closing the current version of the same `dbId` when it changed.
+// The facts of the process are moved to the new version.
+func LoadDimProcess(
+	ctx context.Context,
+	client *ent.Client,
+	process ent.DimProcess,
+	validFrom time.Time,
+) (*ent.DimProcess, error) {
+	return load(ctx, client, dimProcess, &process, validFrom)
+}
+
+// LoadDimVacancy stores the vacancy as the version valid from the date
+// on, closing the current version of the same `dbId` when it changed.
+// The candidates, their interviews, feedbacks, offers, status changes
+// and milestones, and the facts of the vacancy are moved to the new
+// version.
+func LoadDimVacancy(
+	ctx context.Context,
+	client *ent.Client,
+	vacancy ent.DimVacancy,
+	validFrom time.Time,
+) (*ent.DimVacancy, error) {
+	return load(ctx, client, dimVacancy, &vacancy, validFrom)
+}
+
+// LoadDimCandidate stores the candidate as the version valid from the
+// date on, closing the current version of the same `dbId` and vacancy
+// when it changed, e.g. when the candidate moves to another status. The
+// milestones of the candidate are upserted along with it.
+func LoadDimCandidate(
+	ctx context.Context,
+	client *ent.Client,
+	candidate ent.DimCandidate,
+	validFrom time.Time,
+) (*ent.DimCandidate, error) {
+	return inTx(ctx, client, dimCandidate.name, func(tx *ent.Tx) (*ent.DimCandidate, error) {
+		loaded, err := loadInTx(ctx, tx, dimCandidate, &candidate, Date(validFrom))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := refreshCandidateMilestoneInTx(ctx, tx, loaded.ID); err != nil {
+			return nil, err
+		}
+
+		return loaded, nil
+	})
+}
+
+// LoadDimOffer stores the offer as the version valid from the date on,
+// closing the current version of the same `dbId` when it changed, e.g.
+// when the candidate accepts or declines it. The currency defaults to
+// BRL. The milestones of the candidate are upserted along with it.
+func LoadDimOffer(
+	ctx context.Context,
+	client *ent.Client,
+	offer ent.DimOffer,
+	validFrom time.Time,
+) (*ent.DimOffer, error) {
+	if offer.Currency == "" {
+		offer.Currency = dimoffer.DefaultCurrency
+	}
+
+	return inTx(ctx, client, dimOffer.name, func(tx *ent.Tx) (*ent.DimOffer, error) {
+		loaded, err := loadInTx(ctx, tx, dimOffer, &offer, Date(validFrom))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := refreshCandidateMilestoneInTx(ctx, tx, loaded.DimCandidateId); err != nil {
+			return nil, err
+		}
+
+		return loaded, nil
+	})
+}
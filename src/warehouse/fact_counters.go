@@ -0,0 +1,124 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/ent/predicate"
+	"api5back/src/property"
+)
+
+type factCounters struct {
+	interviewed int
+	positive    int
+	neutral     int
+	negative    int
+}
+
+// deriveFactCounters counts the candidates interviewed for the vacancy,
+// the distinct candidates with at least one interview they showed up
+// to, and its feedbacks by `property.FeedbackSentimentOf`. It reports
+// false for a vacancy without interview or feedback rows, whose loaded
+// counters are kept.
+func deriveFactCounters(vacancy *ent.DimVacancy) (factCounters, bool) {
+	if len(vacancy.Edges.DimInterviews) == 0 && len(vacancy.Edges.DimFeedbacks) == 0 {
+		return factCounters{}, false
+	}
+
+	var counters factCounters
+
+	candidates := make(map[int]bool)
+	for _, interview := range vacancy.Edges.DimInterviews {
+		if interview.Outcome == property.DimInterviewOutcomeNoShow ||
+			interview.Edges.DimCandidate == nil {
+			continue
+		}
+		candidates[interview.Edges.DimCandidate.DbId] = true
+	}
+	counters.interviewed = len(candidates)
+
+	for _, feedback := range vacancy.Edges.DimFeedbacks {
+		switch property.FeedbackSentimentOf(feedback.SentimentScore) {
+		case property.FeedbackSentimentPositive:
+			counters.positive++
+		case property.FeedbackSentimentNegative:
+			counters.negative++
+		default:
+			counters.neutral++
+		}
+	}
+
+	return counters, true
+}
+
+// DeriveFactHiringProcessCounters recomputes, in a single transaction,
+// the interview and feedback counters of the facts from the
+// `DimInterview` and `DimFeedback` rows of their vacancies, see
+// `deriveFactCounters`, and returns the number of facts updated.
+// `LoadDimInterview` and `LoadDimFeedback` already derive the counters
+// of the facts of the vacancy they load, so this only rebuilds them all,
+// see `scripts/counters`.
+func DeriveFactHiringProcessCounters(
+	ctx context.Context,
+	client *ent.Client,
+) (int, error) {
+	return inTx(ctx, client, "fact_hiring_process", func(tx *ent.Tx) (int, error) {
+		return deriveFactCountersInTx(ctx, tx)
+	})
+}
+
+func deriveFactCountersInTx(
+	ctx context.Context,
+	tx *ent.Tx,
+	predicates ...predicate.FactHiringProcess,
+) (int, error) {
+	factHiringProcesses, err := tx.FactHiringProcess.
+		Query().
+		Where(predicates...).
+		WithDimVacancy(func(query *ent.DimVacancyQuery) {
+			query.
+				WithDimInterviews(func(query *ent.DimInterviewQuery) {
+					query.WithDimCandidate()
+				}).
+				WithDimFeedbacks()
+		}).
+		All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve `FactHiringProcess` data: %w", err)
+	}
+
+	updated := 0
+	for _, factHiringProcess := range factHiringProcesses {
+		vacancy := factHiringProcess.Edges.DimVacancy
+		if vacancy == nil {
+			continue
+		}
+
+		counters, ok := deriveFactCounters(vacancy)
+		if !ok ||
+			counters.interviewed == factHiringProcess.MetTotalCandidatesInterviewed &&
+				counters.positive == factHiringProcess.MetTotalFeedbackPositive &&
+				counters.neutral == factHiringProcess.MetTotalNeutral &&
+				counters.negative == factHiringProcess.MetTotalNegative {
+			continue
+		}
+
+		if err := tx.FactHiringProcess.
+			UpdateOneID(factHiringProcess.ID).
+			SetMetTotalCandidatesInterviewed(counters.interviewed).
+			SetMetTotalFeedbackPositive(counters.positive).
+			SetMetTotalNeutral(counters.neutral).
+			SetMetTotalNegative(counters.negative).
+			Exec(ctx); err != nil {
+			return 0, fmt.Errorf(
+				"could not update the counters of `FactHiringProcess` with ID %d: %w",
+				factHiringProcess.ID,
+				err,
+			)
+		}
+		updated++
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,52 @@
+package warehouse
+
+import (
+	"testing"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeriveFactCounters(t *testing.T) {
+	candidate := func(dbId int) *ent.DimCandidate {
+		return &ent.DimCandidate{DbId: dbId}
+	}
+	interview := func(outcome property.DimInterviewOutcome, dbId int) *ent.DimInterview {
+		return &ent.DimInterview{
+			Outcome: outcome,
+			Edges:   ent.DimInterviewEdges{DimCandidate: candidate(dbId)},
+		}
+	}
+
+	vacancy := &ent.DimVacancy{
+		Edges: ent.DimVacancyEdges{
+			DimInterviews: []*ent.DimInterview{
+				interview(property.DimInterviewOutcomeApproved, 1),
+				// a second version of the same candidate
+				interview(property.DimInterviewOutcomeRejected, 1),
+				interview(property.DimInterviewOutcomeNoShow, 2),
+				interview(property.DimInterviewOutcomePending, 3),
+			},
+			DimFeedbacks: []*ent.DimFeedback{
+				{SentimentScore: 0.8},
+				{SentimentScore: 0.5},
+				{SentimentScore: 0.1},
+				{SentimentScore: -0.6},
+			},
+		},
+	}
+
+	counters, ok := deriveFactCounters(vacancy)
+	require.True(t, ok)
+	require.Equal(t, factCounters{
+		interviewed: 2,
+		positive:    2,
+		neutral:     1,
+		negative:    1,
+	}, counters)
+
+	_, ok = deriveFactCounters(&ent.DimVacancy{})
+	require.False(t, ok, "the loaded counters are kept without detail rows")
+}
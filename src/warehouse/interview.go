@@ -0,0 +1,140 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+
+	"api5back/ent"
+	"api5back/ent/dimfeedback"
+	"api5back/ent/diminterview"
+	"api5back/ent/facthiringprocess"
+	"api5back/ent/predicate"
+)
+
+// LoadDimInterview upserts the interview by its `dbId` within its
+// vacancy, matched across every version of the vacancy. The milestones
+// of the candidate and the counters of the facts of the vacancy are
+// upserted along with it.
+func LoadDimInterview(
+	ctx context.Context,
+	client *ent.Client,
+	interview ent.DimInterview,
+) (*ent.DimInterview, error) {
+	return inTx(ctx, client, "dim_interview", func(tx *ent.Tx) (*ent.DimInterview, error) {
+		current, err := tx.DimInterview.Query().
+			Where(
+				diminterview.DbId(interview.DbId),
+				diminterview.HasDimVacancyWith(predicate.DimVacancy(SameDbId(interview.DimVacancyId))),
+			).
+			Only(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return nil, fmt.Errorf("could not query interview %d: %w", interview.DbId, err)
+		}
+
+		var loaded *ent.DimInterview
+		if current == nil {
+			loaded, err = tx.DimInterview.Create().
+				SetDbId(interview.DbId).
+				SetDimCandidateId(interview.DimCandidateId).
+				SetDimVacancyId(interview.DimVacancyId).
+				SetDate(interview.Date).
+				SetInterviewer(interview.Interviewer).
+				SetOutcome(interview.Outcome).
+				Save(ctx)
+		} else {
+			loaded, err = current.Update().
+				SetDimVacancyId(interview.DimVacancyId).
+				SetDate(interview.Date).
+				SetInterviewer(interview.Interviewer).
+				SetOutcome(interview.Outcome).
+				Save(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not load interview %d: %w", interview.DbId, err)
+		}
+
+		if err := refreshCandidateMilestoneInTx(ctx, tx, loaded.DimCandidateId); err != nil {
+			return nil, err
+		}
+
+		if err := deriveVacancyCountersInTx(ctx, tx, loaded.DimVacancyId); err != nil {
+			return nil, err
+		}
+
+		return loaded, nil
+	})
+}
+
+// LoadDimFeedback upserts the feedback by its `dbId` within its vacancy,
+// matched across every version of the vacancy, and derives the counters
+// of the facts of the vacancy along with it.
+func LoadDimFeedback(
+	ctx context.Context,
+	client *ent.Client,
+	feedback ent.DimFeedback,
+) (*ent.DimFeedback, error) {
+	return inTx(ctx, client, "dim_feedback", func(tx *ent.Tx) (*ent.DimFeedback, error) {
+		current, err := tx.DimFeedback.Query().
+			Where(
+				dimfeedback.DbId(feedback.DbId),
+				dimfeedback.HasDimVacancyWith(predicate.DimVacancy(SameDbId(feedback.DimVacancyId))),
+			).
+			Only(ctx)
+		if err != nil && !ent.IsNotFound(err) {
+			return nil, fmt.Errorf("could not query feedback %d: %w", feedback.DbId, err)
+		}
+
+		var loaded *ent.DimFeedback
+		if current == nil {
+			builder := tx.DimFeedback.Create().
+				SetDbId(feedback.DbId).
+				SetDimCandidateId(feedback.DimCandidateId).
+				SetDimVacancyId(feedback.DimVacancyId).
+				SetDate(feedback.Date).
+				SetSentimentScore(feedback.SentimentScore).
+				SetComment(feedback.Comment)
+			if feedback.DimInterviewId != 0 {
+				builder.SetDimInterviewId(feedback.DimInterviewId)
+			}
+			loaded, err = builder.Save(ctx)
+		} else {
+			builder := current.Update().
+				SetDimVacancyId(feedback.DimVacancyId).
+				SetDate(feedback.Date).
+				SetSentimentScore(feedback.SentimentScore).
+				SetComment(feedback.Comment)
+			if feedback.DimInterviewId != 0 {
+				builder.SetDimInterviewId(feedback.DimInterviewId)
+			} else {
+				builder.ClearDimInterviewId()
+			}
+			loaded, err = builder.Save(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not load feedback %d: %w", feedback.DbId, err)
+		}
+
+		if err := deriveVacancyCountersInTx(ctx, tx, loaded.DimVacancyId); err != nil {
+			return nil, err
+		}
+
+		return loaded, nil
+	})
+}
+
+// deriveVacancyCountersInTx derives the counters of the facts of every
+// version of the vacancy with the given ID.
+func deriveVacancyCountersInTx(
+	ctx context.Context,
+	tx *ent.Tx,
+	vacancyId int,
+) error {
+	if _, err := deriveFactCountersInTx(
+		ctx, tx,
+		facthiringprocess.HasDimVacancyWith(predicate.DimVacancy(SameDbId(vacancyId))),
+	); err != nil {
+		return fmt.Errorf("could not derive the counters of vacancy with ID %d: %w", vacancyId, err)
+	}
+
+	return nil
+}
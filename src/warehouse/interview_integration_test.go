@@ -0,0 +1,88 @@
+//go:build integration
+// +build integration
+
+package warehouse
+
+import (
+	"context"
+	"testing"
+
+	"api5back/ent"
+	"api5back/ent/dimfeedback"
+	"api5back/ent/facthiringprocess"
+	"api5back/src/database"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDimFeedback(t *testing.T) {
+	ctx := context.Background()
+	var intEnv *database.IntegrationEnvironment = nil
+
+	if testResult := t.Run("Setup database connection", func(t *testing.T) {
+		intEnv = database.DefaultIntegrationEnvironment(ctx)
+
+		require.NotNil(t, intEnv)
+		require.NoError(t, intEnv.Error)
+		require.NotNil(t, intEnv.Client)
+	}); !testResult {
+		t.Fatalf("Setup test failed")
+	}
+
+	if testResult := t.Run("Loading feedbacks derives the counters of their facts", func(t *testing.T) {
+		existing, err := intEnv.Client.DimFeedback.Query().First(ctx)
+		require.NoError(t, err)
+
+		factOf := func() *ent.FactHiringProcess {
+			fact, err := intEnv.Client.FactHiringProcess.Query().
+				Where(facthiringprocess.DimVacancyId(existing.DimVacancyId)).
+				First(ctx)
+			require.NoError(t, err)
+			return fact
+		}
+		before := factOf()
+
+		feedback := ent.DimFeedback{
+			DbId:           999999,
+			DimCandidateId: existing.DimCandidateId,
+			DimVacancyId:   existing.DimVacancyId,
+			Date:           existing.Date,
+			SentimentScore: 0.8,
+		}
+
+		_, err = LoadDimFeedback(ctx, intEnv.Client, feedback)
+		require.NoError(t, err)
+		require.Equal(t, before.MetTotalFeedbackPositive+1, factOf().MetTotalFeedbackPositive)
+
+		feedback.SentimentScore = -0.8
+		_, err = LoadDimFeedback(ctx, intEnv.Client, feedback)
+		require.NoError(t, err)
+
+		after := factOf()
+		require.Equal(t, before.MetTotalFeedbackPositive, after.MetTotalFeedbackPositive)
+		require.Equal(t, before.MetTotalNegative+1, after.MetTotalNegative)
+
+		count, err := intEnv.Client.DimFeedback.Query().
+			Where(
+				dimfeedback.DbId(feedback.DbId),
+				dimfeedback.DimVacancyId(feedback.DimVacancyId),
+			).
+			Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, count, "the feedback is upserted by its dbId")
+
+		_, err = intEnv.Client.DimFeedback.Delete().
+			Where(
+				dimfeedback.DbId(feedback.DbId),
+				dimfeedback.DimVacancyId(feedback.DimVacancyId),
+			).
+			Exec(ctx)
+		require.NoError(t, err)
+
+		_, err = DeriveFactHiringProcessCounters(ctx, intEnv.Client)
+		require.NoError(t, err)
+		require.Equal(t, before.MetTotalNegative, factOf().MetTotalNegative)
+	}); !testResult {
+		t.Fatalf("LoadDimFeedback test failed")
+	}
+}
@@ -0,0 +1,47 @@
+package warehouse
+
+import (
+	"fmt"
+
+	"api5back/ent/dimcandidate"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// A candidate is reloaded, and so stored once more, whenever it changes,
+// e.g. when it moves to another status. Its latest version is the row
+// last loaded for its `dbId` within the vacancy it applied to.
+
+// LatestDimCandidates is a derived table holding only the latest
+// version of each candidate, for the raw SQL over `dim_candidate`.
+var LatestDimCandidates = fmt.Sprintf(
+	"(SELECT DISTINCT ON (%[1]s, %[2]s) * FROM %[3]s ORDER BY %[1]s, %[2]s, %[4]s DESC)",
+	dimcandidate.FieldDbId,
+	dimcandidate.FieldDimVacancyDbId,
+	dimcandidate.Table,
+	dimcandidate.FieldID,
+)
+
+// DimCandidateTable returns the table the raw SQL reads candidates from:
+// their latest versions or, for audit reports, every version loaded.
+func DimCandidateTable(history bool) string {
+	if history {
+		return dimcandidate.Table
+	}
+
+	return LatestDimCandidates
+}
+
+// LatestDimCandidate matches the latest version of each candidate.
+func LatestDimCandidate(s *sql.Selector) {
+	newer := sql.Table(dimcandidate.Table).As("newer_" + dimcandidate.Table)
+	s.Where(sql.NotExists(
+		sql.Select(newer.C(dimcandidate.FieldID)).
+			From(newer).
+			Where(sql.And(
+				sql.ColumnsEQ(newer.C(dimcandidate.FieldDbId), s.C(dimcandidate.FieldDbId)),
+				sql.ColumnsEQ(newer.C(dimcandidate.FieldDimVacancyDbId), s.C(dimcandidate.FieldDimVacancyDbId)),
+				sql.ColumnsGT(newer.C(dimcandidate.FieldID), s.C(dimcandidate.FieldID)),
+			)),
+	))
+}
@@ -0,0 +1,29 @@
+package warehouse
+
+import (
+	"testing"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestDimCandidate(t *testing.T) {
+	require.Equal(t, "dim_candidate", DimCandidateTable(true))
+	require.Equal(t, LatestDimCandidates, DimCandidateTable(false))
+
+	selector := sql.Dialect(dialect.Postgres).
+		Select("*").
+		From(sql.Table("dim_candidate"))
+	LatestDimCandidate(selector)
+
+	query, _ := selector.Query()
+	require.Equal(
+		t,
+		`SELECT * FROM "dim_candidate" WHERE NOT EXISTS (SELECT "newer_dim_candidate"."id" FROM "dim_candidate" AS "newer_dim_candidate" `+
+			`WHERE "newer_dim_candidate"."db_id" = "dim_candidate"."db_id" `+
+			`AND "newer_dim_candidate"."dim_vacancy_db_id" = "dim_candidate"."dim_vacancy_db_id" `+
+			`AND "newer_dim_candidate"."id" > "dim_candidate"."id")`,
+		query,
+	)
+}
@@ -0,0 +1,317 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"api5back/ent"
+	"api5back/ent/dimcandidate"
+	"api5back/ent/dimprocess"
+	"api5back/ent/dimvacancy"
+	"api5back/ent/factcandidatestatuschange"
+	"api5back/ent/factpipelinesnapshot"
+	"api5back/ent/predicate"
+	"api5back/src/property"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// candidateKey identifies a candidate across its versions by its `dbId`
+// within the vacancy it applied to.
+type candidateKey struct {
+	dbId        int
+	vacancyDbId int
+}
+
+type pipelineGroup struct {
+	departmentId int
+	userId       int
+}
+
+// pipelineDimensions holds the dimension versions valid at the date of
+// a snapshot, keyed by their `dbId`, except for the candidates, keyed by
+// the ID of the vacancy they reference, and every candidate status
+// change.
+type pipelineDimensions struct {
+	processes     map[int]*ent.DimProcess
+	vacancies     map[int]*ent.DimVacancy
+	candidates    map[int][]*ent.DimCandidate
+	statusChanges []*ent.FactCandidateStatusChange
+}
+
+// SnapshotPipeline stores the snapshot of the pipeline at the date, one
+// row per department and recruiter of the facts, replacing the snapshot
+// already stored for the date, if any, so the job can run again for the
+// same date. The processes, vacancies and candidates are counted as of
+// the versions valid at the date, from their start on, and candidates
+// with recorded status changes by their status at the end of the date.
+func SnapshotPipeline(
+	ctx context.Context,
+	client *ent.Client,
+	date time.Time,
+) ([]*ent.FactPipelineSnapshot, error) {
+	return inTx(ctx, client, "fact_pipeline_snapshot", func(tx *ent.Tx) ([]*ent.FactPipelineSnapshot, error) {
+		return snapshotPipelineInTx(ctx, tx, Date(date))
+	})
+}
+
+func snapshotPipelineInTx(
+	ctx context.Context,
+	tx *ent.Tx,
+	snapshotDate *pgtype.Date,
+) ([]*ent.FactPipelineSnapshot, error) {
+	if _, err := tx.FactPipelineSnapshot.Delete().
+		Where(factpipelinesnapshot.SnapshotDate(snapshotDate)).
+		Exec(ctx); err != nil {
+		return nil, fmt.Errorf("could not delete the previous snapshot: %w", err)
+	}
+
+	facts, err := tx.FactHiringProcess.Query().
+		WithDimProcess().
+		WithDimVacancy().
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not query fact_hiring_process: %w", err)
+	}
+
+	dimensions, err := queryPipelineDimensions(ctx, tx, snapshotDate)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := countPipeline(snapshotDate, facts, dimensions)
+	if err != nil {
+		return nil, err
+	}
+
+	builders := make([]*ent.FactPipelineSnapshotCreate, len(snapshots))
+	for i, snapshot := range snapshots {
+		builders[i] = tx.FactPipelineSnapshot.Create().
+			SetSnapshotDate(snapshot.SnapshotDate).
+			SetDimDepartmentId(snapshot.DimDepartmentId).
+			SetDimUserId(snapshot.DimUserId).
+			SetNumOpenProcesses(snapshot.NumOpenProcesses).
+			SetNumInProgressProcesses(snapshot.NumInProgressProcesses).
+			SetNumClosedProcesses(snapshot.NumClosedProcesses).
+			SetNumOpenVacancies(snapshot.NumOpenVacancies).
+			SetNumInAnalysisVacancies(snapshot.NumInAnalysisVacancies).
+			SetNumClosedVacancies(snapshot.NumClosedVacancies).
+			SetNumCandidatesInAnalysis(snapshot.NumCandidatesInAnalysis).
+			SetNumCandidatesInterview(snapshot.NumCandidatesInterview).
+			SetNumCandidatesHired(snapshot.NumCandidatesHired).
+			SetNumCandidatesRejected(snapshot.NumCandidatesRejected)
+	}
+
+	saved, err := tx.FactPipelineSnapshot.CreateBulk(builders...).Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create fact_pipeline_snapshot: %w", err)
+	}
+
+	return saved, nil
+}
+
+func queryPipelineDimensions(
+	ctx context.Context,
+	tx *ent.Tx,
+	snapshotDate *pgtype.Date,
+) (pipelineDimensions, error) {
+	dimensions := pipelineDimensions{
+		processes:  make(map[int]*ent.DimProcess),
+		vacancies:  make(map[int]*ent.DimVacancy),
+		candidates: make(map[int][]*ent.DimCandidate),
+	}
+
+	processes, err := tx.DimProcess.Query().
+		Where(
+			predicate.DimProcess(ValidAt(snapshotDate)),
+			dimprocess.InitialDateLTE(snapshotDate),
+		).
+		All(ctx)
+	if err != nil {
+		return pipelineDimensions{}, fmt.Errorf("could not query dim_process: %w", err)
+	}
+	for _, process := range processes {
+		dimensions.processes[process.DbId] = process
+	}
+
+	vacancies, err := tx.DimVacancy.Query().
+		Where(
+			predicate.DimVacancy(ValidAt(snapshotDate)),
+			dimvacancy.OpeningDateLTE(snapshotDate),
+		).
+		All(ctx)
+	if err != nil {
+		return pipelineDimensions{}, fmt.Errorf("could not query dim_vacancy: %w", err)
+	}
+	for _, vacancy := range vacancies {
+		dimensions.vacancies[vacancy.DbId] = vacancy
+	}
+
+	candidates, err := tx.DimCandidate.Query().
+		Where(
+			predicate.DimCandidate(ValidAt(snapshotDate)),
+			dimcandidate.ApplyDateLTE(snapshotDate),
+		).
+		All(ctx)
+	if err != nil {
+		return pipelineDimensions{}, fmt.Errorf("could not query dim_candidate: %w", err)
+	}
+	for _, candidate := range candidates {
+		dimensions.candidates[candidate.DimVacancyDbId] = append(
+			dimensions.candidates[candidate.DimVacancyDbId],
+			candidate,
+		)
+	}
+
+	dimensions.statusChanges, err = tx.FactCandidateStatusChange.Query().
+		WithDimCandidate().
+		Order(
+			ent.Asc(factcandidatestatuschange.FieldChangedAt),
+			ent.Asc(factcandidatestatuschange.FieldID),
+		).
+		All(ctx)
+	if err != nil {
+		return pipelineDimensions{}, fmt.Errorf("could not query fact_candidate_status_change: %w", err)
+	}
+
+	return dimensions, nil
+}
+
+// countPipeline counts, by the department of the process and the
+// recruiter of each fact, the processes, vacancies and candidates at the
+// date. Each process, vacancy and candidate is counted once, in the
+// group of the first fact it appears in.
+func countPipeline(
+	snapshotDate *pgtype.Date,
+	facts []*ent.FactHiringProcess,
+	dimensions pipelineDimensions,
+) ([]*ent.FactPipelineSnapshot, error) {
+	endOfDate := snapshotDate.Time.AddDate(0, 0, 1)
+	changedCandidates := make(map[candidateKey]bool)
+	statusesAtDate := make(map[candidateKey]property.DimCandidateStatus)
+	for _, change := range dimensions.statusChanges {
+		candidate, err := change.Edges.DimCandidateOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimCandidate` of `FactCandidateStatusChange` with ID %d not found: %w",
+				change.ID,
+				err,
+			)
+		}
+
+		key := candidateKey{candidate.DbId, candidate.DimVacancyDbId}
+		changedCandidates[key] = true
+		if change.ChangedAt.Before(endOfDate) {
+			statusesAtDate[key] = change.ToStatus
+		}
+	}
+
+	snapshots := make(map[pipelineGroup]*ent.FactPipelineSnapshot)
+	countedProcesses := make(map[int]bool)
+	countedVacancies := make(map[int]bool)
+
+	for _, fact := range facts {
+		factProcess, err := fact.Edges.DimProcessOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimProcess` of `FactHiringProcess` with ID %d not found: %w",
+				fact.ID,
+				err,
+			)
+		}
+
+		factVacancy, err := fact.Edges.DimVacancyOrErr()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"`DimVacancy` of `FactHiringProcess` with ID %d not found: %w",
+				fact.ID,
+				err,
+			)
+		}
+
+		process, ok := dimensions.processes[factProcess.DbId]
+		if !ok {
+			continue
+		}
+
+		group := pipelineGroup{process.DimDepartmentId, fact.DimUserId}
+		snapshot, ok := snapshots[group]
+		if !ok {
+			snapshot = &ent.FactPipelineSnapshot{
+				SnapshotDate:    snapshotDate,
+				DimDepartmentId: group.departmentId,
+				DimUserId:       group.userId,
+			}
+			snapshots[group] = snapshot
+		}
+
+		if !countedProcesses[process.DbId] {
+			countedProcesses[process.DbId] = true
+
+			// statuses of processes and vacancies are scanned zero
+			// based, see `property.DimProcessStatus.Scan`
+			switch property.DimProcessStatus(process.Status + 1) {
+			case property.DimProcessStatusOpen:
+				snapshot.NumOpenProcesses++
+			case property.DimProcessStatusInProgress:
+				snapshot.NumInProgressProcesses++
+			case property.DimProcessStatusClosed:
+				snapshot.NumClosedProcesses++
+			}
+		}
+
+		vacancy, ok := dimensions.vacancies[factVacancy.DbId]
+		if !ok || countedVacancies[vacancy.DbId] {
+			continue
+		}
+		countedVacancies[vacancy.DbId] = true
+
+		switch property.DimVacancyStatus(vacancy.Status + 1) {
+		case property.DimVacancyStatusOpen:
+			snapshot.NumOpenVacancies++
+		case property.DimVacancyStatusInAnalysis:
+			snapshot.NumInAnalysisVacancies++
+		case property.DimVacancyStatusClosed:
+			snapshot.NumClosedVacancies++
+		}
+
+		// candidates reference the vacancy by its ID, which references
+		// to closed versions are moved from, so it is the ID of the
+		// version of the fact rather than the one valid at the date
+		for _, candidate := range dimensions.candidates[factVacancy.ID] {
+			key := candidateKey{candidate.DbId, candidate.DimVacancyDbId}
+			status := candidate.Status
+			if changedCandidates[key] {
+				if status, ok = statusesAtDate[key]; !ok {
+					continue
+				}
+			}
+
+			switch status {
+			case property.DimCandidateStatusInAnalysis:
+				snapshot.NumCandidatesInAnalysis++
+			case property.DimCandidateStatusInterview:
+				snapshot.NumCandidatesInterview++
+			case property.DimCandidateStatusHired:
+				snapshot.NumCandidatesHired++
+			case property.DimCandidateStatusRejected:
+				snapshot.NumCandidatesRejected++
+			}
+		}
+	}
+
+	sortedSnapshots := make([]*ent.FactPipelineSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		sortedSnapshots = append(sortedSnapshots, snapshot)
+	}
+	sort.Slice(sortedSnapshots, func(i, j int) bool {
+		if sortedSnapshots[i].DimDepartmentId != sortedSnapshots[j].DimDepartmentId {
+			return sortedSnapshots[i].DimDepartmentId < sortedSnapshots[j].DimDepartmentId
+		}
+		return sortedSnapshots[i].DimUserId < sortedSnapshots[j].DimUserId
+	})
+
+	return sortedSnapshots, nil
+}
@@ -0,0 +1,56 @@
+//go:build integration
+// +build integration
+
+package warehouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api5back/ent/factpipelinesnapshot"
+	"api5back/src/database"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotPipeline(t *testing.T) {
+	ctx := context.Background()
+	var intEnv *database.IntegrationEnvironment = nil
+
+	if testResult := t.Run("Setup database connection", func(t *testing.T) {
+		intEnv = database.DefaultIntegrationEnvironment(ctx)
+
+		require.NotNil(t, intEnv)
+		require.NoError(t, intEnv.Error)
+		require.NotNil(t, intEnv.Client)
+	}); !testResult {
+		t.Fatalf("Setup test failed")
+	}
+
+	if testResult := t.Run("SnapshotPipeline is idempotent per date", func(t *testing.T) {
+		date := time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC)
+
+		first, err := SnapshotPipeline(ctx, intEnv.Client, date)
+		require.NoError(t, err)
+		require.NotEmpty(t, first)
+
+		numOpenVacancies := 0
+		for _, snapshot := range first {
+			numOpenVacancies += snapshot.NumOpenVacancies
+		}
+		require.NotZero(t, numOpenVacancies)
+
+		second, err := SnapshotPipeline(ctx, intEnv.Client, date)
+		require.NoError(t, err)
+		require.Len(t, second, len(first))
+
+		stored, err := intEnv.Client.FactPipelineSnapshot.Query().
+			Where(factpipelinesnapshot.SnapshotDate(Date(date))).
+			Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, len(first), stored)
+	}); !testResult {
+		t.Fatalf("SnapshotPipeline test failed")
+	}
+}
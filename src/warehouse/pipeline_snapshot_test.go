@@ -0,0 +1,116 @@
+package warehouse
+
+import (
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/src/property"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountPipeline(t *testing.T) {
+	snapshotDate := Date(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC))
+
+	// statuses of processes and vacancies are scanned zero based
+	dimensions := pipelineDimensions{
+		processes: map[int]*ent.DimProcess{
+			1: {DbId: 1, DimDepartmentId: 2, Status: property.DimProcessStatusInProgress - 1},
+			2: {DbId: 2, DimDepartmentId: 3, Status: property.DimProcessStatusClosed - 1},
+		},
+		vacancies: map[int]*ent.DimVacancy{
+			10: {DbId: 10, Status: property.DimVacancyStatusOpen - 1},
+			20: {DbId: 20, Status: property.DimVacancyStatusClosed - 1},
+		},
+		// keyed by the ID of the vacancy, which differs from its dbId
+		candidates: map[int][]*ent.DimCandidate{
+			110: {
+				{DbId: 1, DimVacancyDbId: 110, Status: property.DimCandidateStatusHired},
+				{DbId: 2, DimVacancyDbId: 110, Status: property.DimCandidateStatusRejected},
+				{DbId: 3, DimVacancyDbId: 110, Status: property.DimCandidateStatusInterview},
+			},
+			120: {
+				{DbId: 1, DimVacancyDbId: 120, Status: property.DimCandidateStatusHired},
+			},
+			// of the vacancy with ID 10, unrelated to the one with dbId 10
+			10: {
+				{DbId: 4, DimVacancyDbId: 10, Status: property.DimCandidateStatusHired},
+			},
+		},
+		statusChanges: []*ent.FactCandidateStatusChange{
+			{
+				ToStatus:  property.DimCandidateStatusInAnalysis,
+				ChangedAt: time.Date(2024, 7, 20, 0, 0, 0, 0, time.UTC),
+				Edges: ent.FactCandidateStatusChangeEdges{
+					DimCandidate: &ent.DimCandidate{DbId: 1, DimVacancyDbId: 110},
+				},
+			},
+			// at the end of the snapshot date
+			{
+				ToStatus:  property.DimCandidateStatusInterview,
+				ChangedAt: time.Date(2024, 8, 1, 18, 0, 0, 0, time.UTC),
+				Edges: ent.FactCandidateStatusChangeEdges{
+					DimCandidate: &ent.DimCandidate{DbId: 1, DimVacancyDbId: 110},
+				},
+			},
+			{
+				ToStatus:  property.DimCandidateStatusHired,
+				ChangedAt: time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC),
+				Edges: ent.FactCandidateStatusChangeEdges{
+					DimCandidate: &ent.DimCandidate{DbId: 1, DimVacancyDbId: 110},
+				},
+			},
+			// had not applied yet at the snapshot date
+			{
+				ToStatus:  property.DimCandidateStatusInAnalysis,
+				ChangedAt: time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC),
+				Edges: ent.FactCandidateStatusChangeEdges{
+					DimCandidate: &ent.DimCandidate{DbId: 2, DimVacancyDbId: 110},
+				},
+			},
+		},
+	}
+
+	newFact := func(id, userId, processDbId, vacancyDbId int) *ent.FactHiringProcess {
+		return &ent.FactHiringProcess{
+			ID:        id,
+			DimUserId: userId,
+			Edges: ent.FactHiringProcessEdges{
+				DimProcess: &ent.DimProcess{DbId: processDbId},
+				DimVacancy: &ent.DimVacancy{ID: vacancyDbId + 100, DbId: vacancyDbId},
+			},
+		}
+	}
+
+	snapshots, err := countPipeline(snapshotDate, []*ent.FactHiringProcess{
+		newFact(1, 5, 2, 20),
+		newFact(2, 4, 1, 10),
+		// loaded again, counted once
+		newFact(3, 4, 1, 10),
+		// the process had not started at the snapshot date
+		newFact(4, 4, 3, 30),
+	}, dimensions)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+
+	require.Equal(t, ent.FactPipelineSnapshot{
+		SnapshotDate:           snapshotDate,
+		DimDepartmentId:        2,
+		DimUserId:              4,
+		NumInProgressProcesses: 1,
+		NumOpenVacancies:       1,
+		NumCandidatesInterview: 2,
+	}, *snapshots[0])
+	require.Equal(t, ent.FactPipelineSnapshot{
+		SnapshotDate:       snapshotDate,
+		DimDepartmentId:    3,
+		DimUserId:          5,
+		NumClosedProcesses: 1,
+		NumClosedVacancies: 1,
+		NumCandidatesHired: 1,
+	}, *snapshots[1])
+
+	_, err = countPipeline(snapshotDate, []*ent.FactHiringProcess{{ID: 1}}, dimensions)
+	require.Error(t, err)
+}
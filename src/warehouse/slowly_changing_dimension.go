@@ -0,0 +1,196 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api5back/ent"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// dimension describes how to version the rows of a slowly changing
+// dimension, see `schema.SlowlyChangingDimension`.
+type dimension[T any] struct {
+	name string
+	// current finds the current version with the natural key of the
+	// version being loaded
+	current func(ctx context.Context, tx *ent.Tx, version *T) (*T, error)
+	// changed reports whether the tracked attributes differ
+	changed func(current, version *T) bool
+	// validFrom returns the start of the validity of a version
+	validFrom func(version *T) *pgtype.Date
+	// close ends the validity of the current version
+	close func(ctx context.Context, tx *ent.Tx, current *T, validTo *pgtype.Date) error
+	// open creates the new current version
+	open func(ctx context.Context, tx *ent.Tx, version *T, validFrom *pgtype.Date) (*T, error)
+	// repoint moves the rows referencing the closed version to the opened
+	// one, if the dimension is referenced by ID
+	repoint func(ctx context.Context, tx *ent.Tx, closed, opened *T) error
+}
+
+// load stores the version of a dimension row that is valid from the
+// given date on. Nothing is stored when the tracked attributes did not
+// change; otherwise the current version is closed the day the new one
+// opens. Versions must be loaded in order of their validity.
+func load[T any](
+	ctx context.Context,
+	client *ent.Client,
+	dimension dimension[T],
+	version *T,
+	validFrom time.Time,
+) (*T, error) {
+	return inTx(ctx, client, dimension.name, func(tx *ent.Tx) (*T, error) {
+		return loadInTx(ctx, tx, dimension, version, Date(validFrom))
+	})
+}
+
+// inTx runs the function in a transaction, which is committed when the
+// function succeeds and rolled back otherwise. `name` is the table being
+// loaded, for the errors.
+func inTx[T any](
+	ctx context.Context,
+	client *ent.Client,
+	name string,
+	function func(tx *ent.Tx) (T, error),
+) (T, error) {
+	var zero T
+
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return zero, fmt.Errorf("could not start transaction: %w", err)
+	}
+
+	result, err := function(tx)
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			err = fmt.Errorf("%w: could not rollback: %w", err, rollbackErr)
+		}
+		return zero, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return zero, fmt.Errorf("could not commit %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func loadInTx[T any](
+	ctx context.Context,
+	tx *ent.Tx,
+	dimension dimension[T],
+	version *T,
+	validFrom *pgtype.Date,
+) (*T, error) {
+	current, err := dimension.current(ctx, tx, version)
+	if err != nil && !ent.IsNotFound(err) {
+		return nil, fmt.Errorf("could not query current %s: %w", dimension.name, err)
+	}
+
+	if current != nil {
+		if !dimension.changed(current, version) {
+			return current, nil
+		}
+
+		currentValidFrom := dimension.validFrom(current)
+		if currentValidFrom != nil && validFrom.Time.Before(currentValidFrom.Time) {
+			return nil, fmt.Errorf(
+				"%s valid from %s is older than its current version, valid from %s",
+				dimension.name,
+				validFrom.Time.Format(time.DateOnly),
+				currentValidFrom.Time.Format(time.DateOnly),
+			)
+		}
+
+		if err := dimension.close(ctx, tx, current, validFrom); err != nil {
+			return nil, fmt.Errorf("could not close current %s: %w", dimension.name, err)
+		}
+	}
+
+	opened, err := dimension.open(ctx, tx, version, validFrom)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %w", dimension.name, err)
+	}
+
+	if current != nil && dimension.repoint != nil {
+		if err := dimension.repoint(ctx, tx, current, opened); err != nil {
+			return nil, fmt.Errorf("could not repoint the references to %s: %w", dimension.name, err)
+		}
+	}
+
+	return opened, nil
+}
+
+// Date converts the time into the date stored by the dimensions.
+func Date(t time.Time) *pgtype.Date {
+	return &pgtype.Date{
+		Time:  time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC),
+		Valid: true,
+	}
+}
+
+// sameDate reports whether both dates are empty or the same day.
+func sameDate(a, b *pgtype.Date) bool {
+	if a == nil || !a.Valid || b == nil || !b.Valid {
+		return (a == nil || !a.Valid) == (b == nil || !b.Valid)
+	}
+
+	return a.Time.Equal(b.Time)
+}
+
+// validAt matches the versions whose validity, by the columns named by
+// `c`, includes the date.
+func validAt(c func(column string) string, date *pgtype.Date) *sql.Predicate {
+	return sql.And(
+		sql.Or(
+			sql.IsNull(c("valid_from")),
+			sql.LTE(c("valid_from"), date),
+		),
+		sql.Or(
+			sql.IsNull(c("valid_to")),
+			sql.GT(c("valid_to"), date),
+		),
+	)
+}
+
+// ValidAt matches the dimension versions valid at the date, including
+// the ones loaded before the dimension kept its history.
+func ValidAt(date *pgtype.Date) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		s.Where(validAt(s.C, date))
+	}
+}
+
+// HasVersionAt matches the dimension rows whose `dbId` has a version
+// valid at the date. Rows are referenced by their current version, so
+// this is how references are matched as of a date.
+func HasVersionAt(date *pgtype.Date) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		versions := sql.Table(s.TableName()).As("versions")
+		s.Where(sql.Exists(
+			sql.Select(versions.C("id")).
+				From(versions).
+				Where(sql.And(
+					sql.ColumnsEQ(versions.C("db_id"), s.C("db_id")),
+					validAt(versions.C, date),
+				)),
+		))
+	}
+}
+
+// SameDbId matches every version of the dimension rows with the given
+// IDs, so IDs of closed versions still match the current one.
+func SameDbId(ids ...int) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		versions := sql.Table(s.TableName()).As("versions")
+		s.Where(sql.In(
+			s.C("db_id"),
+			sql.Select(versions.C("db_id")).
+				From(versions).
+				Where(sql.InInts(versions.C("id"), ids...)),
+		))
+	}
+}
@@ -0,0 +1,78 @@
+//go:build integration
+// +build integration
+
+package warehouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api5back/ent"
+	"api5back/ent/dimuser"
+	"api5back/src/database"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDimUser(t *testing.T) {
+	ctx := context.Background()
+	var intEnv *database.IntegrationEnvironment = nil
+
+	if testResult := t.Run("Setup database connection", func(t *testing.T) {
+		intEnv = database.DefaultIntegrationEnvironment(ctx)
+
+		require.NotNil(t, intEnv)
+		require.NoError(t, intEnv.Error)
+		require.NotNil(t, intEnv.Client)
+	}); !testResult {
+		t.Fatalf("Setup test failed")
+	}
+
+	july := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	september := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	if testResult := t.Run("LoadDimUser versions changed users", func(t *testing.T) {
+		first, err := LoadDimUser(ctx, intEnv.Client, ent.DimUser{
+			DbId: 42, Name: "Eva Lima", Occupation: "Product Manager",
+		}, july)
+		require.NoError(t, err)
+		require.True(t, first.IsCurrent)
+
+		unchanged, err := LoadDimUser(ctx, intEnv.Client, ent.DimUser{
+			DbId: 42, Name: "Eva Lima", Occupation: "Product Manager",
+		}, september)
+		require.NoError(t, err)
+		require.Equal(t, first.ID, unchanged.ID)
+
+		second, err := LoadDimUser(ctx, intEnv.Client, ent.DimUser{
+			DbId: 42, Name: "Eva Lima", Occupation: "Chief Product Officer",
+		}, september)
+		require.NoError(t, err)
+		require.NotEqual(t, first.ID, second.ID)
+		require.True(t, second.IsCurrent)
+
+		closed, err := intEnv.Client.DimUser.Get(ctx, first.ID)
+		require.NoError(t, err)
+		require.False(t, closed.IsCurrent)
+		require.True(t, sameDate(closed.ValidTo, second.ValidFrom))
+
+		asOfAugust, err := intEnv.Client.DimUser.Query().
+			Where(dimuser.DbId(42)).
+			Where(ValidAt(Date(time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC)))).
+			Only(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "Product Manager", asOfAugust.Occupation)
+	}); !testResult {
+		t.Fatalf("LoadDimUser test failed")
+	}
+
+	if testResult := t.Run("LoadDimUser rejects versions older than the current one", func(t *testing.T) {
+		_, err := LoadDimUser(ctx, intEnv.Client, ent.DimUser{
+			DbId: 42, Name: "Eva Lima", Occupation: "Designer",
+		}, july)
+		require.Error(t, err)
+	}); !testResult {
+		t.Fatalf("LoadDimUser out of order test failed")
+	}
+}
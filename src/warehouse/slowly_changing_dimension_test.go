@@ -0,0 +1,79 @@
+package warehouse
+
+import (
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameDate(t *testing.T) {
+	day := Date(time.Date(2024, 7, 1, 15, 30, 0, 0, time.UTC))
+
+	for i, testCase := range []struct {
+		Name     string
+		A        *pgtype.Date
+		B        *pgtype.Date
+		Expected bool
+	}{
+		{Name: "both empty", Expected: true},
+		{Name: "empty and invalid", B: &pgtype.Date{}, Expected: true},
+		{Name: "empty and set", B: day, Expected: false},
+		{Name: "same day", A: day, B: Date(day.Time.Add(time.Hour)), Expected: true},
+		{Name: "other day", A: day, B: Date(day.Time.AddDate(0, 0, 1)), Expected: false},
+	} {
+		t.Run(testCase.Name, func(t *testing.T) {
+			if testResult := sameDate(testCase.A, testCase.B) == testCase.Expected; !testResult {
+				t.Errorf("Test case %d failed", i)
+			}
+		})
+	}
+}
+
+func TestValidAt(t *testing.T) {
+	selector := sql.Dialect(dialect.Postgres).
+		Select("*").
+		From(sql.Table("dim_user"))
+	ValidAt(Date(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)))(selector)
+
+	query, args := selector.Query()
+	require.Equal(
+		t,
+		`SELECT * FROM "dim_user" WHERE ("dim_user"."valid_from" IS NULL OR "dim_user"."valid_from" <= $1) AND ("dim_user"."valid_to" IS NULL OR "dim_user"."valid_to" > $2)`,
+		query,
+	)
+	require.Len(t, args, 2)
+}
+
+func TestHasVersionAt(t *testing.T) {
+	selector := sql.Dialect(dialect.Postgres).
+		Select("*").
+		From(sql.Table("dim_vacancy"))
+	HasVersionAt(Date(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)))(selector)
+
+	query, args := selector.Query()
+	require.Equal(
+		t,
+		`SELECT * FROM "dim_vacancy" WHERE EXISTS (SELECT "versions"."id" FROM "dim_vacancy" AS "versions" WHERE "versions"."db_id" = "dim_vacancy"."db_id" AND (("versions"."valid_from" IS NULL OR "versions"."valid_from" <= $1) AND ("versions"."valid_to" IS NULL OR "versions"."valid_to" > $2)))`,
+		query,
+	)
+	require.Len(t, args, 2)
+}
+
+func TestSameDbId(t *testing.T) {
+	selector := sql.Dialect(dialect.Postgres).
+		Select("*").
+		From(sql.Table("dim_department"))
+	SameDbId(1, 2)(selector)
+
+	query, args := selector.Query()
+	require.Equal(
+		t,
+		`SELECT * FROM "dim_department" WHERE "dim_department"."db_id" IN (SELECT "versions"."db_id" FROM "dim_department" AS "versions" WHERE "versions"."id" IN ($1, $2))`,
+		query,
+	)
+	require.Equal(t, []any{1, 2}, args)
+}